@@ -0,0 +1,77 @@
+// Package checkin генерирует и проверяет короткие меняющиеся коды для отметки присутствия на сессии.
+// Код - 6 цифр, вычисляемых по HMAC-SHA256 от ID сессии и номера временного окна (как в HOTP/TOTP),
+// поэтому хранить коды в базе не нужно: их можно пересчитать по секрету сервера.
+package checkin
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DefaultPeriod - время жизни одного кода
+const DefaultPeriod = 2 * time.Minute
+
+// codeDigits - количество цифр в коде
+const codeDigits = 6
+
+// Coder выдает и проверяет коды отметки присутствия
+type Coder struct {
+	secret []byte
+	period time.Duration
+}
+
+// NewCoder создает генератор кодов с секретом secret и временем жизни кода period
+func NewCoder(secret string, period time.Duration) *Coder {
+	if period <= 0 {
+		period = DefaultPeriod
+	}
+	return &Coder{secret: []byte(secret), period: period}
+}
+
+// Code возвращает код для сессии, действующий в момент now, и момент, когда он сменится
+func (c *Coder) Code(sessionID uuid.UUID, now time.Time) (string, time.Time) {
+	window := c.window(now)
+	expiresAt := time.Unix(0, 0).Add(time.Duration(window+1) * c.period)
+	return c.codeFor(sessionID, window), expiresAt.In(now.Location())
+}
+
+// Verify проверяет код. Принимается код текущего и предыдущего окна,
+// чтобы код, введенный в момент смены, не отклонялся.
+func (c *Coder) Verify(sessionID uuid.UUID, code string, now time.Time) bool {
+	code = strings.TrimSpace(code)
+	if len(code) != codeDigits {
+		return false
+	}
+	window := c.window(now)
+	for _, w := range []int64{window, window - 1} {
+		if hmac.Equal([]byte(c.codeFor(sessionID, w)), []byte(code)) {
+			return true
+		}
+	}
+	return false
+}
+
+// window возвращает номер временного окна, в которое попадает now
+func (c *Coder) window(now time.Time) int64 {
+	return now.UnixNano() / int64(c.period)
+}
+
+// codeFor вычисляет код окна window с динамическим усечением HMAC, как в RFC 4226
+func (c *Coder) codeFor(sessionID uuid.UUID, window int64) string {
+	mac := hmac.New(sha256.New, c.secret)
+	mac.Write(sessionID[:])
+	var counter [8]byte
+	binary.BigEndian.PutUint64(counter[:], uint64(window))
+	mac.Write(counter[:])
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+	return fmt.Sprintf("%0*d", codeDigits, value%1000000)
+}
@@ -0,0 +1,39 @@
+package checkin_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/BuzzLyutic/Skill-sharing-web-platform/checkin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCoder_CodeRotatesAndVerifies(t *testing.T) {
+	coder := checkin.NewCoder("secret", 2*time.Minute)
+	sessionID := uuid.MustParse("8f0e4f4e-4a3b-4f3a-9d55-0b6f7f5c2a11")
+	now := time.Date(2025, 6, 1, 10, 0, 30, 0, time.UTC)
+
+	code, expiresAt := coder.Code(sessionID, now)
+
+	assert.Len(t, code, 6)
+	assert.Equal(t, time.Date(2025, 6, 1, 10, 2, 0, 0, time.UTC), expiresAt)
+	assert.True(t, coder.Verify(sessionID, code, now))
+	assert.True(t, coder.Verify(sessionID, " "+code+" ", now.Add(time.Minute)))
+	// Код предыдущего окна еще принимается, более старый - нет
+	assert.True(t, coder.Verify(sessionID, code, expiresAt.Add(time.Minute)))
+	assert.False(t, coder.Verify(sessionID, code, expiresAt.Add(3*time.Minute)))
+
+	next, _ := coder.Code(sessionID, expiresAt)
+	assert.NotEqual(t, code, next)
+}
+
+func TestCoder_CodeIsBoundToSessionAndSecret(t *testing.T) {
+	now := time.Date(2025, 6, 1, 10, 0, 0, 0, time.UTC)
+	sessionID := uuid.New()
+	code, _ := checkin.NewCoder("secret", 0).Code(sessionID, now)
+
+	assert.False(t, checkin.NewCoder("secret", 0).Verify(uuid.New(), code, now))
+	assert.False(t, checkin.NewCoder("other", 0).Verify(sessionID, code, now))
+	assert.False(t, checkin.NewCoder("secret", 0).Verify(sessionID, "12345", now))
+}
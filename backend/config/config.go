@@ -18,6 +18,8 @@ type Config struct {
     DBPassword string
     DBName     string
     JWTConfig  JWTConfig
+    FrontendURL string // Базовый адрес веб-клиента для ссылок в ответах API
+    CheckInSecret string // Секрет для кодов отметки присутствия, обязателен и должен отличаться от секрета JWT
 }
 
 // LoadConfig загружает конфигурацию из переменных среды
@@ -33,6 +35,8 @@ func LoadConfig() Config {
         DBPassword: GetEnv("DB_PASSWORD", "password"),
         DBName:     GetEnv("DB_NAME", "skill-sharing-web-platform"),
         JWTConfig:  GetJWTConfig(),
+        FrontendURL: GetEnv("FRONTEND_URL", "http://localhost:3000"),
+        CheckInSecret: GetEnv("CHECK_IN_SECRET", ""),
     }
 }
 
@@ -0,0 +1,44 @@
+package config
+
+import (
+    "strconv"
+    "time"
+)
+
+// StorageConfig содержит настройки хранилища вложений сессий
+type StorageConfig struct {
+    Backend       string // "local" или "s3"
+    LocalDir      string // Каталог для файлов (local)
+    PublicURL     string // Внешний адрес API, от которого строятся ссылки на файлы (local)
+    SigningSecret string // Ключ подписи ссылок (local), обязателен и должен отличаться от секрета JWT
+    S3Endpoint    string
+    S3Region      string
+    S3Bucket      string
+    S3AccessKey   string
+    S3SecretKey   string
+    S3PathStyle   bool // true для MinIO
+    MaxFileSize   int64         // Максимальный размер одного файла в байтах
+    URLTTL        time.Duration // Срок действия ссылки на скачивание
+}
+
+// GetStorageConfig возвращает настройки хранилища вложений
+func GetStorageConfig() StorageConfig {
+    pathStyle, err := strconv.ParseBool(GetEnv("S3_PATH_STYLE", "true"))
+    if err != nil {
+        pathStyle = true
+    }
+    return StorageConfig{
+        Backend:       GetEnv("STORAGE_BACKEND", "local"),
+        LocalDir:      GetEnv("STORAGE_LOCAL_DIR", "./uploads"),
+        PublicURL:     GetEnv("API_PUBLIC_URL", "http://localhost:8080"),
+        SigningSecret: GetEnv("STORAGE_SIGNING_SECRET", ""),
+        S3Endpoint:    GetEnv("S3_ENDPOINT", "http://localhost:9000"),
+        S3Region:      GetEnv("S3_REGION", "us-east-1"),
+        S3Bucket:      GetEnv("S3_BUCKET", "session-attachments"),
+        S3AccessKey:   GetEnv("S3_ACCESS_KEY", ""),
+        S3SecretKey:   GetEnv("S3_SECRET_KEY", ""),
+        S3PathStyle:   pathStyle,
+        MaxFileSize:   int64(getEnvAsInt("ATTACHMENT_MAX_SIZE_MB", 50)) << 20,
+        URLTTL:        time.Duration(getEnvAsInt("ATTACHMENT_URL_TTL_MINUTES", 15)) * time.Minute,
+    }
+}
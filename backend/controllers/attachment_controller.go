@@ -0,0 +1,252 @@
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/BuzzLyutic/Skill-sharing-web-platform/models"
+	"github.com/BuzzLyutic/Skill-sharing-web-platform/repositories"
+	"github.com/BuzzLyutic/Skill-sharing-web-platform/storage"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// multipartOverhead - запас на заголовки multipart сверх максимального размера файла
+const multipartOverhead = 1 << 20
+
+// maxAttachmentNameLength - максимальная длина имени файла (как у столбца file_name)
+const maxAttachmentNameLength = 255
+
+// AttachmentController обрабатывает материалы сессий: загрузку, список, скачивание и удаление
+type AttachmentController struct {
+	repo        *repositories.AttachmentRepository
+	sessionRepo *repositories.SessionRepository
+	hostRepo    *repositories.HostRepository
+	storage     storage.Storage
+	maxFileSize int64
+	urlTTL      time.Duration
+}
+
+// NewAttachmentController создает новый контроллер вложений.
+// maxFileSize - максимальный размер файла в байтах, urlTTL - срок действия ссылки на скачивание.
+func NewAttachmentController(
+	repo *repositories.AttachmentRepository,
+	sessionRepo *repositories.SessionRepository,
+	hostRepo *repositories.HostRepository,
+	fileStorage storage.Storage,
+	maxFileSize int64,
+	urlTTL time.Duration,
+) *AttachmentController {
+	return &AttachmentController{
+		repo:        repo,
+		sessionRepo: sessionRepo,
+		hostRepo:    hostRepo,
+		storage:     fileStorage,
+		maxFileSize: maxFileSize,
+		urlTTL:      urlTTL,
+	}
+}
+
+// List обрабатывает GET /api/sessions/:id/attachments (организаторы и участники)
+func (c *AttachmentController) List(ctx *gin.Context) {
+	sessionID, ok := c.requireAccess(ctx)
+	if !ok {
+		return
+	}
+	attachments, err := c.repo.ListBySession(ctx.Request.Context(), sessionID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve session materials"})
+		return
+	}
+	ctx.JSON(http.StatusOK, attachments)
+}
+
+// Upload обрабатывает POST /api/sessions/:id/attachments - multipart-загрузка файла в поле "file"
+func (c *AttachmentController) Upload(ctx *gin.Context) {
+	ctx.Request.Body = http.MaxBytesReader(ctx.Writer, ctx.Request.Body, c.maxFileSize+multipartOverhead)
+
+	session, ok := loadSessionForPermission(ctx, c.sessionRepo, c.hostRepo, models.PermissionEdit,
+		"Forbidden: Only session organizers can upload materials")
+	if !ok {
+		return
+	}
+
+	fileHeader, err := ctx.FormFile("file")
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			c.respondTooLarge(ctx)
+		} else {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "A file is required in the 'file' form field"})
+		}
+		return
+	}
+	if fileHeader.Size > c.maxFileSize {
+		c.respondTooLarge(ctx)
+		return
+	}
+	if fileHeader.Size == 0 {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "File is empty"})
+		return
+	}
+	fileName := strings.TrimSpace(filepath.Base(fileHeader.Filename))
+	if fileName == "" || fileName == "." || !utf8.ValidString(fileName) || utf8.RuneCountInString(fileName) > maxAttachmentNameLength {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("File name must be valid UTF-8 of at most %d characters", maxAttachmentNameLength)})
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		log.Printf("ERROR opening uploaded file for session %s: %v", session.ID, err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to read uploaded file"})
+		return
+	}
+	defer file.Close()
+
+	head := make([]byte, 512)
+	n, err := io.ReadFull(file, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to read uploaded file"})
+		return
+	}
+	contentType, err := models.DetectAttachmentType(fileName, fileHeader.Header.Get("Content-Type"), head[:n])
+	if err != nil {
+		ctx.JSON(http.StatusUnsupportedMediaType, gin.H{"error": err.Error()})
+		return
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to read uploaded file"})
+		return
+	}
+
+	userID, _ := getUserIDFromContext(ctx)
+	attachment := models.SessionAttachment{
+		ID:          uuid.New(),
+		SessionID:   session.ID,
+		UploadedBy:  &userID,
+		FileName:    fileName,
+		ContentType: contentType,
+		SizeBytes:   fileHeader.Size,
+	}
+	// Ключ не содержит имени от клиента: только ID и расширение, соответствующее проверенному типу
+	attachment.StorageKey = fmt.Sprintf("sessions/%s/%s%s", session.ID, attachment.ID, models.AttachmentExtension(contentType))
+
+	requestContext := ctx.Request.Context()
+	if err := c.storage.Put(requestContext, attachment.StorageKey, file, attachment.SizeBytes, contentType); err != nil {
+		log.Printf("ERROR storing attachment %s for session %s: %v", attachment.StorageKey, session.ID, err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to store file"})
+		return
+	}
+	created, err := c.repo.Create(requestContext, attachment)
+	if err != nil {
+		log.Printf("ERROR saving attachment metadata for session %s: %v", session.ID, err)
+		if errDelete := c.storage.Delete(requestContext, attachment.StorageKey); errDelete != nil {
+			log.Printf("WARN: Failed to remove orphaned file %s: %v", attachment.StorageKey, errDelete)
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save attachment"})
+		return
+	}
+	ctx.JSON(http.StatusCreated, created)
+}
+
+// Download обрабатывает GET /api/sessions/:id/attachments/:attachment_id/download.
+// Возвращает подписанную ссылку с ограниченным сроком действия, а не сам файл.
+func (c *AttachmentController) Download(ctx *gin.Context) {
+	attachmentID, err := uuid.Parse(ctx.Param("attachment_id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid attachment ID format"})
+		return
+	}
+	sessionID, ok := c.requireAccess(ctx)
+	if !ok {
+		return
+	}
+
+	requestContext := ctx.Request.Context()
+	attachment, err := c.repo.GetByID(requestContext, sessionID, attachmentID)
+	if err != nil {
+		respondAttachmentError(ctx, attachmentID, err)
+		return
+	}
+	expiresAt := time.Now().Add(c.urlTTL)
+	link, err := c.storage.SignedURL(requestContext, attachment.StorageKey, attachment.FileName, expiresAt)
+	if err != nil {
+		log.Printf("ERROR signing download link for attachment %s: %v", attachmentID, err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create download link"})
+		return
+	}
+	ctx.JSON(http.StatusOK, models.AttachmentDownload{URL: link, ExpiresAt: expiresAt})
+}
+
+// Delete обрабатывает DELETE /api/sessions/:id/attachments/:attachment_id
+func (c *AttachmentController) Delete(ctx *gin.Context) {
+	attachmentID, err := uuid.Parse(ctx.Param("attachment_id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid attachment ID format"})
+		return
+	}
+	session, ok := loadSessionForPermission(ctx, c.sessionRepo, c.hostRepo, models.PermissionEdit,
+		"Forbidden: Only session organizers can delete materials")
+	if !ok {
+		return
+	}
+
+	requestContext := ctx.Request.Context()
+	attachment, err := c.repo.Delete(requestContext, session.ID, attachmentID)
+	if err != nil {
+		respondAttachmentError(ctx, attachmentID, err)
+		return
+	}
+	// Метаданные уже удалены, поэтому ссылки на файл больше не выдаются, даже если хранилище не ответило
+	if err := c.storage.Delete(requestContext, attachment.StorageKey); err != nil {
+		log.Printf("WARN: Failed to delete file %s of attachment %s: %v", attachment.StorageKey, attachmentID, err)
+	}
+	ctx.JSON(http.StatusOK, gin.H{"message": "Attachment deleted successfully"})
+}
+
+// requireAccess разбирает :id и проверяет, что текущий пользователь - организатор или участник сессии
+func (c *AttachmentController) requireAccess(ctx *gin.Context) (uuid.UUID, bool) {
+	sessionID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid session ID format"})
+		return uuid.Nil, false
+	}
+	userID, ok := getUserIDFromContext(ctx)
+	if !ok {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized: User identification failed"})
+		return uuid.Nil, false
+	}
+	allowed, err := isHostOrParticipant(ctx.Request.Context(), c.sessionRepo, c.hostRepo, sessionID, userID)
+	if err != nil {
+		log.Printf("ERROR checking access of user %s to session %s: %v", userID, sessionID, err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to verify participation status"})
+		return uuid.Nil, false
+	}
+	if !allowed {
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "Only session participants and organizers can access materials"})
+		return uuid.Nil, false
+	}
+	return sessionID, true
+}
+
+// respondTooLarge отвечает 413 с допустимым размером файла
+func (c *AttachmentController) respondTooLarge(ctx *gin.Context) {
+	ctx.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": fmt.Sprintf("File exceeds the maximum size of %d MB", c.maxFileSize>>20)})
+}
+
+// respondAttachmentError преобразует ошибки репозитория вложений в HTTP-ответ
+func respondAttachmentError(ctx *gin.Context, attachmentID uuid.UUID, err error) {
+	if errors.Is(err, repositories.ErrAttachmentNotFound) {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	log.Printf("ERROR processing attachment %s: %v", attachmentID, err)
+	ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to process attachment"})
+}
@@ -0,0 +1,192 @@
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/BuzzLyutic/Skill-sharing-web-platform/checkin"
+	"github.com/BuzzLyutic/Skill-sharing-web-platform/models"
+	"github.com/BuzzLyutic/Skill-sharing-web-platform/repositories"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// AttendanceController обрабатывает отметку присутствия участников сессий
+type AttendanceController struct {
+	repo        *repositories.AttendanceRepository
+	sessionRepo *repositories.SessionRepository
+	hostRepo    *repositories.HostRepository
+	coder       *checkin.Coder
+	frontendURL string
+}
+
+// NewAttendanceController создает новый контроллер отметки присутствия.
+// frontendURL - адрес веб-клиента, из которого строится ссылка для QR-кода.
+func NewAttendanceController(
+	repo *repositories.AttendanceRepository,
+	sessionRepo *repositories.SessionRepository,
+	hostRepo *repositories.HostRepository,
+	coder *checkin.Coder,
+	frontendURL string,
+) *AttendanceController {
+	return &AttendanceController{
+		repo:        repo,
+		sessionRepo: sessionRepo,
+		hostRepo:    hostRepo,
+		coder:       coder,
+		frontendURL: strings.TrimSuffix(frontendURL, "/"),
+	}
+}
+
+// List обрабатывает GET /api/sessions/:id/attendance - участники с отметками и посещаемостью
+func (c *AttendanceController) List(ctx *gin.Context) {
+	session, ok := c.loadSession(ctx)
+	if !ok {
+		return
+	}
+	c.respondAttendees(ctx, session.ID)
+}
+
+// Update обрабатывает PUT /api/sessions/:id/attendance - отметки организатора
+func (c *AttendanceController) Update(ctx *gin.Context) {
+	var req models.AttendanceUpdateRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		log.Printf("Failed to bind JSON for attendance update: %v", err)
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body", "details": err.Error()})
+		return
+	}
+	if len(req.Entries) == 0 && !req.MarkRemainingNoShow {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Provide entries or mark_remaining_no_show"})
+		return
+	}
+
+	session, ok := c.loadSession(ctx)
+	if !ok {
+		return
+	}
+	if !session.AttendanceMarkable(time.Now()) {
+		ctx.JSON(http.StatusConflict, gin.H{"error": "Attendance can only be marked for published or completed sessions once check-in has opened"})
+		return
+	}
+
+	hostID, _ := getUserIDFromContext(ctx)
+	if err := c.repo.SetAttendance(ctx.Request.Context(), session.ID, hostID, req.Entries, req.MarkRemainingNoShow); err != nil {
+		if errors.Is(err, repositories.ErrNotJoined) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		} else {
+			log.Printf("ERROR marking attendance for session %s: %v", session.ID, err)
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to mark attendance"})
+		}
+		return
+	}
+	c.respondAttendees(ctx, session.ID)
+}
+
+// Code обрабатывает GET /api/sessions/:id/check-in/code - текущий код для показа участникам.
+// Код меняется каждые несколько минут; клиент обновляет его по expires_at.
+func (c *AttendanceController) Code(ctx *gin.Context) {
+	session, ok := c.loadSession(ctx)
+	if !ok {
+		return
+	}
+	now := time.Now()
+	if !session.CheckInOpen(now) {
+		ctx.JSON(http.StatusConflict, gin.H{"error": "Check-in is not open for this session"})
+		return
+	}
+
+	code, expiresAt := c.coder.Code(session.ID, now)
+	ctx.JSON(http.StatusOK, models.CheckInCode{
+		Code:      code,
+		ExpiresAt: expiresAt,
+		QRPayload: c.frontendURL + "/sessions/" + session.ID.String() + "/check-in?code=" + url.QueryEscape(code),
+	})
+}
+
+// CheckIn обрабатывает POST /api/sessions/:id/check-in - самостоятельная отметка участника кодом
+func (c *AttendanceController) CheckIn(ctx *gin.Context) {
+	sessionID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid session ID format"})
+		return
+	}
+	userID, ok := getUserIDFromContext(ctx)
+	if !ok {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized: User identification failed"})
+		return
+	}
+	var req models.CheckInRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body", "details": err.Error()})
+		return
+	}
+
+	requestContext := ctx.Request.Context()
+	// Сначала проверяем участие, чтобы посторонние не узнавали о сессии
+	if _, err := c.repo.GetAttendance(requestContext, sessionID, userID); err != nil {
+		if errors.Is(err, repositories.ErrNotJoined) {
+			ctx.JSON(http.StatusForbidden, gin.H{"error": "You must be a participant to check in"})
+		} else {
+			log.Printf("ERROR checking attendance of user %s in session %s: %v", userID, sessionID, err)
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to verify participation status"})
+		}
+		return
+	}
+	session, err := c.sessionRepo.GetByID(requestContext, sessionID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrSessionNotFound) {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		} else {
+			log.Printf("ERROR getting session %s: %v", sessionID, err)
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve session"})
+		}
+		return
+	}
+
+	now := time.Now()
+	if !session.CheckInOpen(now) {
+		ctx.JSON(http.StatusConflict, gin.H{"error": "Check-in is not open for this session"})
+		return
+	}
+	if !c.coder.Verify(session.ID, req.Code, now) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid or expired check-in code"})
+		return
+	}
+
+	checkedInAt, err := c.repo.CheckIn(requestContext, session.ID, userID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrNotJoined) {
+			ctx.JSON(http.StatusForbidden, gin.H{"error": "You must be a participant to check in"})
+		} else {
+			log.Printf("ERROR checking in user %s to session %s: %v", userID, session.ID, err)
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check in"})
+		}
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{
+		"message":       fmt.Sprintf("Checked in to '%s'", session.Title),
+		"attendance":    models.AttendanceAttended,
+		"checked_in_at": checkedInAt,
+	})
+}
+
+// loadSession загружает сессию :id, если текущий пользователь может отмечать присутствие
+func (c *AttendanceController) loadSession(ctx *gin.Context) (*models.Session, bool) {
+	return loadSessionForPermission(ctx, c.sessionRepo, c.hostRepo, models.PermissionCheckIn,
+		"Forbidden: Only session organizers can manage attendance")
+}
+
+// respondAttendees отвечает списком участников сессии с отметками
+func (c *AttendanceController) respondAttendees(ctx *gin.Context, sessionID uuid.UUID) {
+	attendees, err := c.repo.ListAttendees(ctx.Request.Context(), sessionID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve attendance"})
+		return
+	}
+	ctx.JSON(http.StatusOK, attendees)
+}
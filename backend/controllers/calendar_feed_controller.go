@@ -0,0 +1,185 @@
+package controllers
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/BuzzLyutic/Skill-sharing-web-platform/models"
+	"github.com/BuzzLyutic/Skill-sharing-web-platform/repositories"
+	"github.com/arran4/golang-ical"
+	"github.com/gin-gonic/gin"
+)
+
+// calendarFeedRefresh - как часто календарям предлагается обновлять подписку
+const calendarFeedRefresh = "PT1H"
+
+// CalendarFeedController обрабатывает персональную подписку на календарь (webcal):
+// выдачу и отзыв секретной ссылки и саму ленту, доступную по ссылке без JWT
+type CalendarFeedController struct {
+	repo        *repositories.CalendarFeedRepository
+	publicURL   string
+	frontendURL string
+}
+
+// NewCalendarFeedController создает новый контроллер подписок. publicURL - внешний адрес API,
+// от которого строятся ссылки на ленту, frontendURL - адрес веб-клиента для ссылок на сессии.
+func NewCalendarFeedController(repo *repositories.CalendarFeedRepository, publicURL, frontendURL string) *CalendarFeedController {
+	return &CalendarFeedController{repo: repo, publicURL: strings.TrimRight(publicURL, "/"), frontendURL: strings.TrimRight(frontendURL, "/")}
+}
+
+// Status обрабатывает GET /api/users/me/calendar-feed - есть ли у пользователя активная ссылка
+func (c *CalendarFeedController) Status(ctx *gin.Context) {
+	userID, ok := getUserIDFromContext(ctx)
+	if !ok {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized: User identification failed"})
+		return
+	}
+	feed, err := c.repo.Get(ctx.Request.Context(), userID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrCalendarFeedNotFound) {
+			ctx.JSON(http.StatusOK, models.CalendarFeedStatus{Active: false})
+			return
+		}
+		log.Printf("ERROR getting calendar feed of user %s: %v", userID, err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve calendar feed"})
+		return
+	}
+	ctx.JSON(http.StatusOK, models.CalendarFeedStatus{Active: true, CreatedAt: &feed.CreatedAt})
+}
+
+// Rotate обрабатывает POST /api/users/me/calendar-feed - выдает новую ссылку, прежняя перестает работать
+func (c *CalendarFeedController) Rotate(ctx *gin.Context) {
+	userID, ok := getUserIDFromContext(ctx)
+	if !ok {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized: User identification failed"})
+		return
+	}
+	token, err := generateFeedToken()
+	if err != nil {
+		log.Printf("ERROR generating calendar feed token: %v", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create calendar feed"})
+		return
+	}
+	feed, err := c.repo.Rotate(ctx.Request.Context(), userID, hashFeedToken(token))
+	if err != nil {
+		log.Printf("ERROR saving calendar feed of user %s: %v", userID, err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create calendar feed"})
+		return
+	}
+
+	feedURL := c.publicURL + "/api/calendar/" + token + ".ics"
+	webcalURL := feedURL
+	if i := strings.Index(feedURL, "://"); i >= 0 {
+		webcalURL = "webcal" + feedURL[i:]
+	}
+	ctx.JSON(http.StatusCreated, models.CalendarFeedLink{URL: feedURL, WebcalURL: webcalURL, CreatedAt: feed.CreatedAt})
+}
+
+// Revoke обрабатывает DELETE /api/users/me/calendar-feed - отзывает ссылку
+func (c *CalendarFeedController) Revoke(ctx *gin.Context) {
+	userID, ok := getUserIDFromContext(ctx)
+	if !ok {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized: User identification failed"})
+		return
+	}
+	if err := c.repo.Revoke(ctx.Request.Context(), userID); err != nil {
+		if errors.Is(err, repositories.ErrCalendarFeedNotFound) {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		log.Printf("ERROR revoking calendar feed of user %s: %v", userID, err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to revoke calendar feed"})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"message": "Calendar feed revoked successfully"})
+}
+
+// Feed обрабатывает GET /api/calendar/:token(.ics) - лента предстоящих (и недавно прошедших)
+// сессий пользователя. Авторизация - секретный токен в ссылке, т.к. календари не передают JWT.
+// Поддерживается If-None-Match: при неизменной ленте отвечает 304 без тела.
+func (c *CalendarFeedController) Feed(ctx *gin.Context) {
+	token := strings.TrimSuffix(ctx.Param("token"), ".ics")
+	requestContext := ctx.Request.Context()
+	userID, err := c.repo.GetUserIDByTokenHash(requestContext, hashFeedToken(token))
+	if err != nil {
+		if errors.Is(err, repositories.ErrCalendarFeedNotFound) {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		} else {
+			log.Printf("ERROR resolving calendar feed token: %v", err)
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve calendar feed"})
+		}
+		return
+	}
+
+	sessions, err := c.repo.ListSessions(requestContext, userID, time.Now().Add(-models.CalendarFeedWindow))
+	if err != nil {
+		log.Printf("ERROR listing calendar feed sessions of user %s: %v", userID, err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve calendar feed"})
+		return
+	}
+	body := buildCalendarFeed(sessions, c.frontendURL)
+	sum := sha256.Sum256([]byte(body))
+	etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+
+	ctx.Header("ETag", etag)
+	ctx.Header("Cache-Control", "private, no-cache") // Клиент каждый раз переспрашивает с If-None-Match
+	if etagMatches(ctx.GetHeader("If-None-Match"), etag) {
+		ctx.Status(http.StatusNotModified)
+		return
+	}
+	ctx.Header("Content-Disposition", `inline; filename="sessions.ics"`)
+	ctx.Data(http.StatusOK, "text/calendar; charset=utf-8", []byte(body))
+}
+
+// buildCalendarFeed собирает VCALENDAR подписки. Вывод зависит только от данных сессий
+// (DTSTAMP - время последнего изменения сессии), поэтому ETag меняется лишь при изменениях.
+// Пользователь ленты - организатор или участник каждой сессии, поэтому ссылки на встречи включаются.
+func buildCalendarFeed(sessions []models.Session, frontendURL string) string {
+	cal := ics.NewCalendar()
+	cal.SetMethod(ics.MethodPublish) // Подписка, а не приглашение: клиенты не предлагают ответить
+	cal.SetXWRCalName("Skill Sharing")
+	cal.SetRefreshInterval(calendarFeedRefresh, ics.WithValue(string(ics.ValueDataTypeDuration)))
+	cal.SetXPublishedTTL(calendarFeedRefresh)
+	zones := newICSTimezones()
+	for i := range sessions {
+		session := &sessions[i]
+		event := cal.AddEvent(session.ID.String())
+		setSessionEventFields(event, session, zones, frontendURL)
+		event.SetDtStampTime(session.UpdatedAt)
+	}
+	zones.addTo(cal)
+	return cal.Serialize()
+}
+
+// etagMatches проверяет заголовок If-None-Match (список ETag через запятую, "*" или слабые W/"...")
+func etagMatches(header, etag string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == "*" || candidate == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// generateFeedToken создает случайный токен ссылки подписки (256 бит)
+func generateFeedToken() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+// hashFeedToken возвращает SHA-256 токена в hex: в БД хранится только он
+func hashFeedToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
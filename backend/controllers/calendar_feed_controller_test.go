@@ -0,0 +1,51 @@
+package controllers
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/BuzzLyutic/Skill-sharing-web-platform/models"
+	"github.com/google/uuid"
+)
+
+func TestBuildCalendarFeed(t *testing.T) {
+	start := time.Date(2025, 6, 2, 15, 0, 0, 0, time.UTC)
+	updated := time.Date(2025, 5, 20, 9, 30, 0, 0, time.UTC)
+	sessions := []models.Session{
+		{ID: uuid.New(), Title: "Go", DateTime: start, EndTime: start.Add(time.Hour), TimeZone: "Europe/Moscow",
+			Status: models.SessionStatusPublished, CreatedAt: updated, UpdatedAt: updated, Version: 3},
+		{ID: uuid.New(), Title: "Rust", DateTime: start, EndTime: start.Add(time.Hour), TimeZone: "Asia/Tokyo",
+			Status: models.SessionStatusCancelled, CreatedAt: updated, UpdatedAt: updated},
+	}
+
+	body := buildCalendarFeed(sessions, testFrontendURL)
+	if again := buildCalendarFeed(sessions, testFrontendURL); again != body {
+		t.Fatal("feed must be deterministic for unchanged sessions")
+	}
+	for _, want := range []string{"METHOD:PUBLISH", "SEQUENCE:3", "DTSTAMP:20250520T093000Z", "STATUS:CANCELLED", "UID:" + sessions[0].ID.String()} {
+		if !strings.Contains(body, want) {
+			t.Errorf("feed does not contain %q", want)
+		}
+	}
+	if tokyo, moscow := strings.Index(body, "TZID:Asia/Tokyo"), strings.Index(body, "TZID:Europe/Moscow"); tokyo < 0 || moscow < 0 || tokyo > moscow {
+		t.Errorf("time zones must be sorted: Asia/Tokyo at %d, Europe/Moscow at %d", tokyo, moscow)
+	}
+}
+
+func TestETagMatches(t *testing.T) {
+	etag := `"abc"`
+	cases := map[string]bool{
+		``:           false,
+		`"abc"`:      true,
+		`W/"abc"`:    true,
+		`"x", "abc"`: true,
+		`*`:          true,
+		`"abcd"`:     false,
+	}
+	for header, want := range cases {
+		if got := etagMatches(header, etag); got != want {
+			t.Errorf("etagMatches(%q) = %v, want %v", header, got, want)
+		}
+	}
+}
@@ -0,0 +1,146 @@
+package controllers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/BuzzLyutic/Skill-sharing-web-platform/models"
+	"github.com/BuzzLyutic/Skill-sharing-web-platform/repositories"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// CategoryController обрабатывает дерево категорий: публичный просмотр и управление администратором
+type CategoryController struct {
+	repo *repositories.CategoryRepository
+}
+
+// NewCategoryController создает новый контроллер категорий
+func NewCategoryController(repo *repositories.CategoryRepository) *CategoryController {
+	return &CategoryController{repo: repo}
+}
+
+// List обрабатывает GET /api/categories?lang=ru - дерево категорий с названиями на нужном языке.
+// Без lang используется первый язык из Accept-Language.
+func (c *CategoryController) List(ctx *gin.Context) {
+	categories, err := c.repo.List(ctx.Request.Context())
+	if err != nil {
+		log.Printf("ERROR listing categories: %v", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve categories"})
+		return
+	}
+	ctx.JSON(http.StatusOK, models.BuildCategoryTree(categories, requestLocale(ctx)))
+}
+
+// Create обрабатывает POST /api/admin/categories
+func (c *CategoryController) Create(ctx *gin.Context) {
+	req, ok := bindCategoryRequest(ctx)
+	if !ok {
+		return
+	}
+	category, err := c.repo.Create(ctx.Request.Context(), req)
+	if err != nil {
+		respondCategoryError(ctx, err)
+		return
+	}
+	category.Name = category.LocalizedName(requestLocale(ctx))
+	ctx.JSON(http.StatusCreated, category)
+}
+
+// Update обрабатывает PUT /api/admin/categories/:id. Переименование slug переносится на сессии,
+// серии и шаблоны этой категории.
+func (c *CategoryController) Update(ctx *gin.Context) {
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid category ID format"})
+		return
+	}
+	req, ok := bindCategoryRequest(ctx)
+	if !ok {
+		return
+	}
+	userID, _ := getUserIDFromContext(ctx)
+	category, err := c.repo.Update(ctx.Request.Context(), id, userID, req)
+	if err != nil {
+		respondCategoryError(ctx, err)
+		return
+	}
+	category.Name = category.LocalizedName(requestLocale(ctx))
+	ctx.JSON(http.StatusOK, category)
+}
+
+// Delete обрабатывает DELETE /api/admin/categories/:id. Удалить можно только категорию
+// без подкатегорий, на которую не ссылается ни одна сессия, серия или шаблон.
+func (c *CategoryController) Delete(ctx *gin.Context) {
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid category ID format"})
+		return
+	}
+	if err := c.repo.Delete(ctx.Request.Context(), id); err != nil {
+		respondCategoryError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"message": "Category deleted successfully"})
+}
+
+// bindCategoryRequest разбирает и нормализует тело запроса категории, отвечая 400 при ошибке
+func bindCategoryRequest(ctx *gin.Context) (models.CategoryRequest, bool) {
+	var req models.CategoryRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return req, false
+	}
+	if err := req.Normalize(); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return req, false
+	}
+	return req, true
+}
+
+// respondCategoryError отвечает на ошибку репозитория категорий
+func respondCategoryError(ctx *gin.Context, err error) {
+	switch {
+	case errors.Is(err, repositories.ErrCategoryNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, repositories.ErrCategorySlugTaken), errors.Is(err, repositories.ErrCategoryInUse):
+		ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, repositories.ErrCategoryParentCycle):
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		log.Printf("ERROR changing category: %v", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save category"})
+	}
+}
+
+// requestLocale возвращает язык из ?lang= или первый язык заголовка Accept-Language
+func requestLocale(ctx *gin.Context) string {
+	if lang := strings.TrimSpace(ctx.Query("lang")); lang != "" {
+		return lang
+	}
+	first, _, _ := strings.Cut(ctx.GetHeader("Accept-Language"), ",")
+	first, _, _ = strings.Cut(first, ";")
+	if first = strings.TrimSpace(first); first != "" && first != "*" {
+		return first
+	}
+	return models.DefaultLocale
+}
+
+// resolveCategory заменяет категорию из запроса (slug или название на любом языке, без учета регистра)
+// на slug из дерева категорий. Для неизвестной категории отвечает 400 и возвращает false.
+func resolveCategory(ctx *gin.Context, repo *repositories.CategoryRepository, category *string) bool {
+	resolved, err := repo.Resolve(ctx.Request.Context(), *category)
+	if err != nil {
+		if errors.Is(err, repositories.ErrUnknownCategory) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		} else {
+			log.Printf("ERROR resolving category %q: %v", *category, err)
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to validate category"})
+		}
+		return false
+	}
+	*category = resolved.Slug
+	return true
+}
@@ -0,0 +1,297 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/BuzzLyutic/Skill-sharing-web-platform/models"
+	"github.com/BuzzLyutic/Skill-sharing-web-platform/repositories"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// CommentController обрабатывает обсуждение сессии: комментарии, ответы и упоминания
+type CommentController struct {
+	repo        *repositories.CommentRepository
+	sessionRepo *repositories.SessionRepository
+	hostRepo    *repositories.HostRepository
+	notifRepo   *repositories.NotificationRepository
+}
+
+// NewCommentController создает новый контроллер комментариев
+func NewCommentController(
+	repo *repositories.CommentRepository,
+	sessionRepo *repositories.SessionRepository,
+	hostRepo *repositories.HostRepository,
+	notifRepo *repositories.NotificationRepository,
+) *CommentController {
+	return &CommentController{repo: repo, sessionRepo: sessionRepo, hostRepo: hostRepo, notifRepo: notifRepo}
+}
+
+// List обрабатывает GET /api/sessions/:id/comments - обсуждение в виде дерева (организаторы и участники)
+func (c *CommentController) List(ctx *gin.Context) {
+	session, _, ok := c.loadSession(ctx)
+	if !ok {
+		return
+	}
+	comments, err := c.repo.ListBySession(ctx.Request.Context(), session.ID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve comments"})
+		return
+	}
+	ctx.JSON(http.StatusOK, models.BuildCommentTree(comments))
+}
+
+// Create обрабатывает POST /api/sessions/:id/comments - новый комментарий или ответ (parent_id)
+func (c *CommentController) Create(ctx *gin.Context) {
+	var req models.CommentRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		log.Printf("Failed to bind JSON for comment: %v", err)
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+	body := strings.TrimSpace(req.Body)
+	if body == "" {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Comment body must not be empty"})
+		return
+	}
+
+	session, userID, ok := c.loadSession(ctx)
+	if !ok {
+		return
+	}
+
+	requestContext := ctx.Request.Context()
+	comment, err := c.repo.Create(requestContext, session.ID, userID, req.ParentID, body)
+	if err != nil {
+		respondCommentError(ctx, err)
+		return
+	}
+
+	notified := c.notifyMentions(requestContext, session, comment, models.ParseMentions(body))
+	if comment.ParentID == nil {
+		c.notifyHosts(requestContext, session, comment, notified)
+	}
+	ctx.JSON(http.StatusCreated, comment)
+}
+
+// Update обрабатывает PUT /api/sessions/:id/comments/:comment_id. Редактировать может только автор;
+// уведомления получают только вновь упомянутые пользователи.
+func (c *CommentController) Update(ctx *gin.Context) {
+	var req models.CommentRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		log.Printf("Failed to bind JSON for comment update: %v", err)
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+	body := strings.TrimSpace(req.Body)
+	if body == "" {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Comment body must not be empty"})
+		return
+	}
+
+	session, userID, ok := c.loadSession(ctx)
+	if !ok {
+		return
+	}
+	existing, ok := c.loadOwnComment(ctx, session.ID, userID)
+	if !ok {
+		return
+	}
+
+	requestContext := ctx.Request.Context()
+	updated, err := c.repo.Update(requestContext, existing.ID, body)
+	if err != nil {
+		respondCommentError(ctx, err)
+		return
+	}
+
+	alreadyMentioned := map[uuid.UUID]bool{}
+	for _, id := range models.ParseMentions(existing.Body) {
+		alreadyMentioned[id] = true
+	}
+	newMentions := []uuid.UUID{}
+	for _, id := range models.ParseMentions(body) {
+		if !alreadyMentioned[id] {
+			newMentions = append(newMentions, id)
+		}
+	}
+	c.notifyMentions(requestContext, session, updated, newMentions)
+	ctx.JSON(http.StatusOK, updated)
+}
+
+// Delete обрабатывает DELETE /api/sessions/:id/comments/:comment_id - автор удаляет свой комментарий
+func (c *CommentController) Delete(ctx *gin.Context) {
+	session, userID, ok := c.loadSession(ctx)
+	if !ok {
+		return
+	}
+	comment, ok := c.loadOwnComment(ctx, session.ID, userID)
+	if !ok {
+		return
+	}
+	if err := c.repo.SoftDelete(ctx.Request.Context(), comment.ID, userID); err != nil {
+		respondCommentError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"message": "Comment deleted successfully"})
+}
+
+// ModeratorDelete обрабатывает DELETE /api/moderator/comments/:comment_id - удаление любого комментария
+func (c *CommentController) ModeratorDelete(ctx *gin.Context) {
+	commentID, err := uuid.Parse(ctx.Param("comment_id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid comment ID format"})
+		return
+	}
+	moderatorID, ok := getUserIDFromContext(ctx)
+	if !ok {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized: User identification failed"})
+		return
+	}
+	if err := c.repo.SoftDelete(ctx.Request.Context(), commentID, moderatorID); err != nil {
+		respondCommentError(ctx, err)
+		return
+	}
+	log.Printf("Comment %s deleted by moderator %s", commentID, moderatorID)
+	ctx.JSON(http.StatusOK, gin.H{"message": "Comment deleted successfully"})
+}
+
+// loadSession разбирает :id, загружает сессию и проверяет, что текущий пользователь -
+// организатор или участник. Возвращает сессию и ID пользователя, при ошибке отвечает клиенту.
+func (c *CommentController) loadSession(ctx *gin.Context) (*models.Session, uuid.UUID, bool) {
+	sessionID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid session ID format"})
+		return nil, uuid.Nil, false
+	}
+	userID, ok := getUserIDFromContext(ctx)
+	if !ok {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized: User identification failed"})
+		return nil, uuid.Nil, false
+	}
+
+	requestContext := ctx.Request.Context()
+	session, err := c.sessionRepo.GetByID(requestContext, sessionID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrSessionNotFound) {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		} else {
+			log.Printf("ERROR getting session %s for comments: %v", sessionID, err)
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve session"})
+		}
+		return nil, uuid.Nil, false
+	}
+	allowed, err := isHostOrParticipant(requestContext, c.sessionRepo, c.hostRepo, sessionID, userID)
+	if err != nil {
+		log.Printf("ERROR checking access of user %s to session %s: %v", userID, sessionID, err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to verify participation status"})
+		return nil, uuid.Nil, false
+	}
+	if !allowed {
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "Only session participants and organizers can access the discussion"})
+		return nil, uuid.Nil, false
+	}
+	return session, userID, true
+}
+
+// loadOwnComment разбирает :comment_id и проверяет, что комментарий относится к сессии,
+// не удален и написан текущим пользователем
+func (c *CommentController) loadOwnComment(ctx *gin.Context, sessionID, userID uuid.UUID) (*models.SessionComment, bool) {
+	commentID, err := uuid.Parse(ctx.Param("comment_id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid comment ID format"})
+		return nil, false
+	}
+	comment, err := c.repo.GetByID(ctx.Request.Context(), commentID)
+	if err == nil && (comment.SessionID != sessionID || comment.IsDeleted()) {
+		err = repositories.ErrCommentNotFound
+	}
+	if err != nil {
+		respondCommentError(ctx, err)
+		return nil, false
+	}
+	if comment.UserID == nil || *comment.UserID != userID {
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "Forbidden: You can only modify your own comments"})
+		return nil, false
+	}
+	return comment, true
+}
+
+// notifyMentions уведомляет упомянутых пользователей, у которых есть доступ к обсуждению
+// (кроме автора). Возвращает множество уведомленных.
+func (c *CommentController) notifyMentions(ctx context.Context, session *models.Session, comment *models.SessionComment, mentioned []uuid.UUID) map[uuid.UUID]bool {
+	notified := map[uuid.UUID]bool{}
+	for _, userID := range mentioned {
+		if comment.UserID != nil && userID == *comment.UserID {
+			continue
+		}
+		allowed, err := isHostOrParticipant(ctx, c.sessionRepo, c.hostRepo, session.ID, userID)
+		if err != nil {
+			log.Printf("WARN: Failed to check access of mentioned user %s in session %s: %v", userID, session.ID, err)
+			continue
+		}
+		if !allowed {
+			continue
+		}
+		c.notify(ctx, userID, session, models.NotificationTypeCommentMention,
+			fmt.Sprintf("%s mentioned you in the discussion of '%s'.", commentAuthorName(comment), session.Title))
+		notified[userID] = true
+	}
+	return notified
+}
+
+// notifyHosts сообщает организаторам о новом обсуждении, пропуская автора и уже уведомленных
+func (c *CommentController) notifyHosts(ctx context.Context, session *models.Session, comment *models.SessionComment, skip map[uuid.UUID]bool) {
+	hosts, err := c.hostRepo.ListHosts(ctx, session.ID, false)
+	if err != nil {
+		log.Printf("WARN: Failed to list hosts of session %s for comment notification: %v", session.ID, err)
+		return
+	}
+	for _, host := range hosts {
+		if skip[host.UserID] || (comment.UserID != nil && host.UserID == *comment.UserID) {
+			continue
+		}
+		c.notify(ctx, host.UserID, session, models.NotificationTypeNewComment,
+			fmt.Sprintf("%s started a discussion in '%s'.", commentAuthorName(comment), session.Title))
+	}
+}
+
+// notify создает уведомление, связанное с сессией. Ошибки только логируются.
+func (c *CommentController) notify(ctx context.Context, userID uuid.UUID, session *models.Session, notifType models.NotificationType, message string) {
+	newNotif := models.Notification{
+		UserID:      userID,
+		Message:     message,
+		Type:        notifType,
+		RelatedID:   &session.ID,
+		RelatedType: "session",
+	}
+	if _, errNotif := c.notifRepo.CreateNotification(ctx, newNotif); errNotif != nil {
+		log.Printf("WARN: Failed to create %s notification for user %s: %v", notifType, userID, errNotif)
+	}
+}
+
+// commentAuthorName возвращает имя автора для текста уведомления
+func commentAuthorName(comment *models.SessionComment) string {
+	if comment.AuthorName != nil && *comment.AuthorName != "" {
+		return *comment.AuthorName
+	}
+	return "Someone"
+}
+
+// respondCommentError преобразует ошибки репозитория комментариев в HTTP-ответ
+func respondCommentError(ctx *gin.Context, err error) {
+	switch {
+	case errors.Is(err, repositories.ErrCommentNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, repositories.ErrParentCommentNotFound):
+		ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		log.Printf("ERROR processing comment: %v", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to process comment"})
+	}
+}
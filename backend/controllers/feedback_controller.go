@@ -15,15 +15,17 @@ import (
 // func getUserIDFromContext(ctx *gin.Context) (uuid.UUID, bool) { ... }
 
 type FeedbackController struct {
-	repo        *repositories.FeedbackRepository
-	sessionRepo *repositories.SessionRepository
+	repo           *repositories.FeedbackRepository
+	sessionRepo    *repositories.SessionRepository
+	attendanceRepo *repositories.AttendanceRepository
 }
 
 // NewFeedbackController создает новый контроллер обратной связи
-func NewFeedbackController(repo *repositories.FeedbackRepository, sessionRepo *repositories.SessionRepository) *FeedbackController {
+func NewFeedbackController(repo *repositories.FeedbackRepository, sessionRepo *repositories.SessionRepository, attendanceRepo *repositories.AttendanceRepository) *FeedbackController {
 	return &FeedbackController{
-		repo:        repo,
-		sessionRepo: sessionRepo,
+		repo:           repo,
+		sessionRepo:    sessionRepo,
+		attendanceRepo: attendanceRepo,
 	}
 }
 
@@ -76,17 +78,21 @@ func (c *FeedbackController) CreateFeedback(ctx *gin.Context) {
         return
     }
 
-	// 6. Проверяем, был ли пользователь участником сессии
-    // (Предполагаем, что метод IsParticipant добавлен в SessionRepository)
-	isParticipant, err := c.sessionRepo.IsParticipant(requestContext, sessionID, userID)
+	// 6. Проверяем, что пользователь был участником сессии и действительно пришел
+	attendance, err := c.attendanceRepo.GetAttendance(requestContext, sessionID, userID)
 	if err != nil {
-        log.Printf("ERROR checking participation for user %s in session %s: %v", userID, sessionID, err)
-        ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to verify participation status"})
+		if errors.Is(err, repositories.ErrNotJoined) {
+			log.Printf("WARN: Non-participant user %s attempted to leave feedback on session %s", userID, sessionID)
+			ctx.JSON(http.StatusForbidden, gin.H{"error": "You must be a participant to leave feedback"})
+			return
+		}
+		log.Printf("ERROR checking attendance for user %s in session %s: %v", userID, sessionID, err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to verify participation status"})
 		return
 	}
-	if !isParticipant {
-        log.Printf("WARN: Non-participant user %s attempted to leave feedback on session %s", userID, sessionID)
-		ctx.JSON(http.StatusForbidden, gin.H{"error": "You must be a participant to leave feedback"})
+	if attendance != models.AttendanceAttended {
+		log.Printf("WARN: User %s without attendance attempted to leave feedback on session %s", userID, sessionID)
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "Only participants who attended the session can leave feedback"})
 		return
 	}
 
@@ -121,8 +127,13 @@ func (c *FeedbackController) GetFeedback(ctx *gin.Context) {
 		return
 	}
 
+	page, ok := parsePageRequest(ctx, 20)
+	if !ok {
+		return
+	}
+
 	// Получаем отзывы (передаем контекст!)
-	feedbacks, err := c.repo.GetFeedbackBySession(ctx.Request.Context(), sessionID)
+	feedbacks, info, err := c.repo.GetFeedbackBySession(ctx.Request.Context(), sessionID, page)
 	if err != nil {
         // Ошибку ErrDatabase уже залогировал репозиторий
         ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve feedback"})
@@ -131,5 +142,5 @@ func (c *FeedbackController) GetFeedback(ctx *gin.Context) {
 
     // feedbacks гарантированно не nil из-за изменений в репозитории
 
-	ctx.JSON(http.StatusOK, feedbacks)
+	ctx.JSON(http.StatusOK, gin.H{"data": feedbacks, "meta": pageMeta(info)})
 }
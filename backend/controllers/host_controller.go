@@ -0,0 +1,266 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/BuzzLyutic/Skill-sharing-web-platform/models"
+	"github.com/BuzzLyutic/Skill-sharing-web-platform/repositories"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// HostController обрабатывает HTTP-запросы, связанные с организаторами сессий
+type HostController struct {
+	repo        *repositories.HostRepository
+	sessionRepo *repositories.SessionRepository
+	userRepo    *repositories.UserRepository
+	notifRepo   *repositories.NotificationRepository
+}
+
+// NewHostController создает новый контроллер организаторов
+func NewHostController(
+	repo *repositories.HostRepository,
+	sessionRepo *repositories.SessionRepository,
+	userRepo *repositories.UserRepository,
+	notifRepo *repositories.NotificationRepository,
+) *HostController {
+	return &HostController{repo: repo, sessionRepo: sessionRepo, userRepo: userRepo, notifRepo: notifRepo}
+}
+
+// ListHosts обрабатывает GET /api/sessions/:id/hosts.
+// Неподтвержденные приглашения видны только тем, кто управляет организаторами.
+func (c *HostController) ListHosts(ctx *gin.Context) {
+	session, role, ok := c.loadSession(ctx)
+	if !ok {
+		return
+	}
+	if session.Status == models.SessionStatusDraft && !role.Can(models.PermissionViewDraft) {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": repositories.ErrSessionNotFound.Error()})
+		return
+	}
+
+	hosts, err := c.repo.ListHosts(ctx.Request.Context(), session.ID, role.Can(models.PermissionManageHosts))
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve session hosts"})
+		return
+	}
+	ctx.JSON(http.StatusOK, hosts)
+}
+
+// Invite обрабатывает POST /api/sessions/:id/hosts - приглашает пользователя в организаторы
+func (c *HostController) Invite(ctx *gin.Context) {
+	var req models.HostInviteRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		log.Printf("Failed to bind JSON for host invitation: %v", err)
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	session, role, ok := c.loadSession(ctx)
+	if !ok {
+		return
+	}
+	if !role.Can(models.PermissionManageHosts) {
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "Forbidden: Only the session owner can invite hosts"})
+		return
+	}
+
+	requestContext := ctx.Request.Context()
+	invitee, err := c.userRepo.GetByID(requestContext, req.UserID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrUserNotFound) {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		} else {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve user"})
+		}
+		return
+	}
+
+	inviterID, _ := getUserIDFromContext(ctx)
+	host, err := c.repo.Invite(requestContext, session.ID, invitee.ID, req.Role, inviterID)
+	if err != nil {
+		respondHostError(ctx, session.ID, err)
+		return
+	}
+
+	c.notify(requestContext, invitee.ID, session, models.NotificationTypeHostInvitation,
+		fmt.Sprintf("You have been invited to host the session '%s' as %s.", session.Title, req.Role))
+	ctx.JSON(http.StatusCreated, host)
+}
+
+// Accept обрабатывает POST /api/sessions/:id/hosts/accept - текущий пользователь принимает приглашение
+func (c *HostController) Accept(ctx *gin.Context) {
+	session, _, ok := c.loadSession(ctx)
+	if !ok {
+		return
+	}
+	userID, _ := getUserIDFromContext(ctx)
+
+	requestContext := ctx.Request.Context()
+	host, err := c.repo.Accept(requestContext, session.ID, userID)
+	if err != nil {
+		respondHostError(ctx, session.ID, err)
+		return
+	}
+
+	c.notify(requestContext, session.CreatorID, session, models.NotificationTypeHostAccepted,
+		fmt.Sprintf("%s accepted your invitation to host '%s' as %s.", host.Name, session.Title, host.Role))
+	ctx.JSON(http.StatusOK, host)
+}
+
+// Remove обрабатывает DELETE /api/sessions/:id/hosts/:user_id. Владелец может удалить любого
+// организатора, остальные - только себя (отказаться от приглашения или роли).
+func (c *HostController) Remove(ctx *gin.Context) {
+	targetID, err := uuid.Parse(ctx.Param("user_id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID format"})
+		return
+	}
+
+	session, role, ok := c.loadSession(ctx)
+	if !ok {
+		return
+	}
+	userID, _ := getUserIDFromContext(ctx)
+	if targetID != userID && !role.Can(models.PermissionManageHosts) {
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "Forbidden: Only the session owner can remove other hosts"})
+		return
+	}
+
+	if err := c.repo.Remove(ctx.Request.Context(), session.ID, targetID); err != nil {
+		respondHostError(ctx, session.ID, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"message": "Host removed successfully"})
+}
+
+// loadSession разбирает :id, загружает сессию и роль текущего пользователя в ней,
+// отвечая клиенту при ошибке
+func (c *HostController) loadSession(ctx *gin.Context) (*models.Session, models.HostRole, bool) {
+	sessionID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid session ID format"})
+		return nil, "", false
+	}
+	userID, ok := getUserIDFromContext(ctx)
+	if !ok {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized: User identification failed"})
+		return nil, "", false
+	}
+
+	requestContext := ctx.Request.Context()
+	session, err := c.sessionRepo.GetByID(requestContext, sessionID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrSessionNotFound) {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		} else {
+			log.Printf("ERROR getting session %s for hosts: %v", sessionID, err)
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve session"})
+		}
+		return nil, "", false
+	}
+	role, err := c.repo.GetRole(requestContext, sessionID, userID)
+	if err != nil {
+		log.Printf("ERROR getting host role of user %s in session %s: %v", userID, sessionID, err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check session permissions"})
+		return nil, "", false
+	}
+	return session, role, true
+}
+
+// loadSessionForPermission разбирает :id, загружает сессию и проверяет, что роль текущего пользователя
+// разрешает действие permission. При ошибке или отказе отвечает клиенту (forbiddenMessage для 403)
+// и возвращает false. Посторонним не сообщается о существовании сессии invite_only.
+func loadSessionForPermission(
+	ctx *gin.Context,
+	sessionRepo *repositories.SessionRepository,
+	hostRepo *repositories.HostRepository,
+	permission models.SessionPermission,
+	forbiddenMessage string,
+) (*models.Session, bool) {
+	sessionID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid session ID format"})
+		return nil, false
+	}
+	userID, ok := getUserIDFromContext(ctx)
+	if !ok {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized: User identification failed"})
+		return nil, false
+	}
+
+	requestContext := ctx.Request.Context()
+	session, err := sessionRepo.GetByID(requestContext, sessionID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrSessionNotFound) {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		} else {
+			log.Printf("ERROR getting session %s: %v", sessionID, err)
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve session"})
+		}
+		return nil, false
+	}
+	role, err := hostRepo.GetRole(requestContext, sessionID, userID)
+	if err != nil {
+		log.Printf("ERROR getting host role of user %s in session %s: %v", userID, sessionID, err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check session permissions"})
+		return nil, false
+	}
+	if !role.Can(permission) {
+		if role == "" && session.Visibility == models.VisibilityInviteOnly {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": repositories.ErrSessionNotFound.Error()})
+		} else {
+			ctx.JSON(http.StatusForbidden, gin.H{"error": forbiddenMessage})
+		}
+		return nil, false
+	}
+	return session, true
+}
+
+// notify создает уведомление, связанное с сессией. Ошибки только логируются.
+func (c *HostController) notify(ctx context.Context, userID uuid.UUID, session *models.Session, notifType models.NotificationType, message string) {
+	newNotif := models.Notification{
+		UserID:      userID,
+		Message:     message,
+		Type:        notifType,
+		RelatedID:   &session.ID,
+		RelatedType: "session",
+	}
+	if _, errNotif := c.notifRepo.CreateNotification(ctx, newNotif); errNotif != nil {
+		log.Printf("WARN: Failed to create %s notification for user %s: %v", notifType, userID, errNotif)
+	}
+}
+
+// respondHostError преобразует ошибки операций с организаторами в HTTP-ответ
+func respondHostError(ctx *gin.Context, sessionID uuid.UUID, err error) {
+	switch {
+	case errors.Is(err, repositories.ErrHostNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, repositories.ErrAlreadyHost), errors.Is(err, repositories.ErrCannotRemoveOwner):
+		ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		log.Printf("ERROR processing hosts of session %s: %v", sessionID, err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to process session hosts"})
+	}
+}
+
+// isHostOrParticipant проверяет, что пользователь - организатор (с принятой ролью) или участник сессии
+func isHostOrParticipant(
+	ctx context.Context,
+	sessionRepo *repositories.SessionRepository,
+	hostRepo *repositories.HostRepository,
+	sessionID, userID uuid.UUID,
+) (bool, error) {
+	role, err := hostRepo.GetRole(ctx, sessionID, userID)
+	if err != nil {
+		return false, err
+	}
+	if role != "" {
+		return true, nil
+	}
+	return sessionRepo.IsParticipant(ctx, sessionID, userID)
+}
@@ -0,0 +1,316 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/BuzzLyutic/Skill-sharing-web-platform/models"
+	"github.com/arran4/golang-ical"
+	"github.com/google/uuid"
+)
+
+const (
+	icsTimestampFormat      = "20060102T150405Z" // Время в UTC
+	icsLocalTimestampFormat = "20060102T150405"  // Локальное время, используется вместе с TZID
+)
+
+// icsTimezones собирает часовые пояса, используемые событиями календаря,
+// и интервал дат, для которого нужно описать их правила в VTIMEZONE.
+type icsTimezones struct {
+	locations map[string]*time.Location
+	from, to  time.Time
+}
+
+func newICSTimezones() *icsTimezones {
+	return &icsTimezones{locations: map[string]*time.Location{}}
+}
+
+// use регистрирует часовой пояс loc для события, которое длится с start по end
+func (z *icsTimezones) use(loc *time.Location, start, end time.Time) {
+	if loc == time.UTC {
+		return // Время в UTC выгружается с суффиксом Z, VTIMEZONE не нужен
+	}
+	z.locations[loc.String()] = loc
+	if z.from.IsZero() || start.Before(z.from) {
+		z.from = start
+	}
+	if end.After(z.to) {
+		z.to = end
+	}
+}
+
+// property возвращает значение и параметры свойства даты-времени (DTSTART, RECURRENCE-ID и т.д.)
+func (z *icsTimezones) property(t time.Time, loc *time.Location) (string, []ics.PropertyParameter) {
+	if loc == time.UTC {
+		return t.UTC().Format(icsTimestampFormat), nil
+	}
+	return t.In(loc).Format(icsLocalTimestampFormat), []ics.PropertyParameter{ics.WithTZID(loc.String())}
+}
+
+// setTimes устанавливает DTSTART и DTEND события в часовом поясе loc
+func (z *icsTimezones) setTimes(event *ics.VEvent, start, end time.Time, loc *time.Location) {
+	z.use(loc, start, end)
+	value, params := z.property(start, loc)
+	event.SetProperty(ics.ComponentPropertyDtStart, value, params...)
+	value, params = z.property(end, loc)
+	event.SetProperty(ics.ComponentPropertyDtEnd, value, params...)
+}
+
+// addTo добавляет VTIMEZONE для всех зарегистрированных часовых поясов в начало календаря
+func (z *icsTimezones) addTo(cal *ics.Calendar) {
+	names := make([]string, 0, len(z.locations))
+	for name := range z.locations {
+		names = append(names, name)
+	}
+	sort.Strings(names) // Стабильный порядок: одинаковые данные дают одинаковый файл (ETag подписки)
+	timezones := make([]ics.Component, 0, len(names))
+	for _, name := range names {
+		timezones = append(timezones, buildVTimezone(z.locations[name], z.from, z.to))
+	}
+	cal.Components = append(timezones, cal.Components...)
+}
+
+// zoneObservance - период действия одного смещения часового пояса (STANDARD или DAYLIGHT)
+type zoneObservance struct {
+	start      time.Time
+	offsetFrom int
+	offsetTo   int
+	name       string
+	dst        bool
+}
+
+// buildVTimezone описывает правила часового пояса loc на интервале [from, to] с запасом в год
+// с каждой стороны. Каждый переход записывается отдельным STANDARD/DAYLIGHT без RRULE:
+// так описание остается точным и для зон, правила которых менялись.
+func buildVTimezone(loc *time.Location, from, to time.Time) *ics.VTimezone {
+	tz := ics.NewTimezone(loc.String())
+	for _, observance := range zoneObservances(loc, from.AddDate(-1, 0, 0), to.AddDate(1, 0, 0)) {
+		var component *ics.ComponentBase
+		if observance.dst {
+			daylight := &ics.Daylight{}
+			tz.Components = append(tz.Components, daylight)
+			component = &daylight.ComponentBase
+		} else {
+			component = &tz.AddStandard().ComponentBase
+		}
+		// DTSTART перехода записывается в локальном времени до перехода
+		localStart := observance.start.In(time.FixedZone("", observance.offsetFrom))
+		component.SetProperty(ics.ComponentPropertyDtStart, localStart.Format(icsLocalTimestampFormat))
+		component.SetProperty(ics.ComponentProperty(ics.PropertyTzoffsetfrom), formatUTCOffset(observance.offsetFrom))
+		component.SetProperty(ics.ComponentProperty(ics.PropertyTzoffsetto), formatUTCOffset(observance.offsetTo))
+		if observance.name != "" {
+			component.SetProperty(ics.ComponentProperty(ics.PropertyTzname), observance.name)
+		}
+	}
+	return tz
+}
+
+// zoneObservances возвращает смещение, действующее в момент from, и все переходы до to.
+// Переходы ищутся с шагом в сутки, точный момент уточняется двоичным поиском до секунды.
+func zoneObservances(loc *time.Location, from, to time.Time) []zoneObservance {
+	name, offset := from.In(loc).Zone()
+	observances := []zoneObservance{{
+		start: from, offsetFrom: offset, offsetTo: offset, name: name, dst: from.In(loc).IsDST(),
+	}}
+
+	prev := from.Unix()
+	for t := prev + 86400; prev < to.Unix(); t += 86400 {
+		_, current := time.Unix(t, 0).In(loc).Zone()
+		if current == offset {
+			prev = t
+			continue
+		}
+		lo, hi := prev, t
+		for hi-lo > 1 {
+			mid := lo + (hi-lo)/2
+			if _, o := time.Unix(mid, 0).In(loc).Zone(); o == offset {
+				lo = mid
+			} else {
+				hi = mid
+			}
+		}
+		at := time.Unix(hi, 0).In(loc)
+		name, _ := at.Zone()
+		observances = append(observances, zoneObservance{
+			start: at, offsetFrom: offset, offsetTo: current, name: name, dst: at.IsDST(),
+		})
+		offset = current
+		prev = hi
+	}
+	return observances
+}
+
+// formatUTCOffset форматирует смещение в секундах как +HHMM (или +HHMMSS)
+func formatUTCOffset(seconds int) string {
+	sign := '+'
+	if seconds < 0 {
+		sign = '-'
+		seconds = -seconds
+	}
+	hours, minutes, secs := seconds/3600, seconds%3600/60, seconds%60
+	if secs != 0 {
+		return fmt.Sprintf("%c%02d%02d%02d", sign, hours, minutes, secs)
+	}
+	return fmt.Sprintf("%c%02d%02d", sign, hours, minutes)
+}
+
+// seriesSequence возвращает SEQUENCE основного события серии - наибольшую версию ее занятий.
+// Без него календари не замечают изменений, которые касаются всей серии.
+func seriesSequence(occurrences []models.Session) int {
+	sequence := 0
+	for _, occurrence := range occurrences {
+		if occurrence.Version > sequence {
+			sequence = occurrence.Version
+		}
+	}
+	return sequence
+}
+
+// setSessionEventFields заполняет VEVENT данными сессии. frontendURL - адрес веб-клиента для ссылки на сессию.
+func setSessionEventFields(event *ics.VEvent, session *models.Session, zones *icsTimezones, frontendURL string) {
+	event.SetCreatedTime(session.CreatedAt)
+	event.SetDtStampTime(time.Now()) // Время создания ICS файла
+	event.SetModifiedAt(session.UpdatedAt)
+	event.SetSequence(session.Version) // Растет с каждой версией сессии
+	zones.setTimes(event, session.DateTime, session.EndTime, session.TimeLocation())
+	event.SetSummary(session.Title)
+	location := session.Location
+	if session.VenueAddress != nil && *session.VenueAddress != "" {
+		location += ", " + *session.VenueAddress
+	}
+	event.SetLocation(location)
+	if session.Latitude != nil && session.Longitude != nil {
+		event.SetGeo(*session.Latitude, *session.Longitude)
+	}
+	event.SetDescription(appendMeetingDetails(session.Description, session.MeetingDetailsText()))
+	event.SetURL(frontendURL + "/sessions/" + session.ID.String()) // Ссылка на сессию на сайте
+	if session.Status == models.SessionStatusCancelled {
+		event.SetStatus(ics.ObjectStatusCancelled)
+	}
+	setHostFields(event, session.Hosts)
+}
+
+// appendMeetingDetails добавляет к описанию события ссылку на встречу и инструкции для входа.
+// Вызывающий код отвечает за то, чтобы details были пустыми для посторонних пользователей.
+func appendMeetingDetails(description, details string) string {
+	if details == "" {
+		return description
+	}
+	if description == "" {
+		return details
+	}
+	return description + "\n\n" + details
+}
+
+// setHostFields записывает владельца сессии в ORGANIZER, а соорганизаторов и помощников - в ATTENDEE
+// (соорганизаторы с ROLE=CHAIR). Учитываются только принятые приглашения.
+func setHostFields(event *ics.VEvent, hosts []models.SessionHost) {
+	for _, host := range hosts {
+		if host.Status != models.HostStatusAccepted {
+			continue
+		}
+		switch host.Role {
+		case models.HostRoleOwner:
+			event.SetOrganizer(host.Email, ics.WithCN(host.Name))
+		case models.HostRoleCoHost:
+			event.AddAttendee(host.Email, ics.WithCN(host.Name), ics.ParticipationRoleChair, ics.ParticipationStatusAccepted)
+		default:
+			event.AddAttendee(host.Email, ics.WithCN(host.Name), ics.ParticipationRoleReqParticipant, ics.ParticipationStatusAccepted)
+		}
+	}
+}
+
+// addSeriesEvents добавляет в календарь основное событие серии с RRULE/EXDATE
+// и отдельные VEVENT с RECURRENCE-ID для занятий, измененных отдельно от серии.
+// Организаторы и доступ пользователя userID к ссылке на встречу определяются для каждого занятия отдельно.
+func (c *SessionController) addSeriesEvents(ctx context.Context, cal *ics.Calendar, seriesID, userID uuid.UUID, zones *icsTimezones) (*models.SessionSeries, error) {
+	series, err := c.seriesRepo.GetByID(ctx, seriesID)
+	if err != nil {
+		return nil, err
+	}
+	exdates, err := c.seriesRepo.GetExdates(ctx, seriesID)
+	if err != nil {
+		return nil, err
+	}
+	occurrences, err := c.seriesRepo.GetOccurrences(ctx, seriesID, false)
+	if err != nil {
+		return nil, err
+	}
+
+	// Основное событие описывает все занятия по правилу: организаторы берутся у первого из них,
+	// а ссылка на встречу включается, только если пользователь видит ее у каждого предстоящего занятия
+	var ruleHosts []models.SessionHost
+	upcoming, showMeetingDetails := 0, true
+	now := time.Now()
+	for i := range occurrences {
+		occurrence := &occurrences[i]
+		if occurrence.IsOverride || occurrence.Status == models.SessionStatusCancelled {
+			continue
+		}
+		if ruleHosts == nil {
+			if ruleHosts, err = c.hostRepo.ListHosts(ctx, occurrence.ID, false); err != nil {
+				return nil, err
+			}
+		}
+		if occurrence.EndTime.After(now) {
+			upcoming++
+			showMeetingDetails = showMeetingDetails && c.canSeeMeetingDetails(ctx, occurrence, userID)
+		}
+	}
+
+	// RRULE разворачивается клиентом в часовом поясе DTSTART, поэтому DTSTART серии
+	// выгружается с TZID серии - так время занятий не сдвигается при переходе на летнее время
+	loc := series.TimeLocation()
+	master := cal.AddEvent(series.ID.String())
+	master.SetCreatedTime(series.CreatedAt)
+	master.SetDtStampTime(time.Now())
+	master.SetModifiedAt(series.UpdatedAt)
+	master.SetSequence(seriesSequence(occurrences))
+	zones.setTimes(master, series.DTStart, series.DTStart.Add(series.Duration()), loc)
+	if len(occurrences) > 0 {
+		last := occurrences[len(occurrences)-1]
+		zones.use(loc, last.DateTime, last.EndTime)
+	}
+	master.SetSummary(series.Title)
+	master.SetLocation(series.Location)
+	description := series.Description
+	if showMeetingDetails && upcoming > 0 {
+		description = appendMeetingDetails(description, models.MeetingDetailsText(series.MeetingURL, series.JoinInstructions))
+	}
+	master.SetDescription(description)
+	master.AddRrule(series.RRule)
+	setHostFields(master, ruleHosts)
+	for _, exdate := range exdates {
+		value, params := zones.property(exdate, loc)
+		master.AddExdate(value, params...)
+	}
+
+	for i := range occurrences {
+		occurrence := &occurrences[i]
+		if occurrence.OriginalStart == nil {
+			continue
+		}
+		if occurrence.Status == models.SessionStatusCancelled {
+			// Отмененное занятие исключается из правила так же, как удаленное
+			value, params := zones.property(*occurrence.OriginalStart, loc)
+			master.AddExdate(value, params...)
+			continue
+		}
+		if !occurrence.IsOverride {
+			continue
+		}
+		event := cal.AddEvent(series.ID.String()) // Тот же UID, что у серии
+		value, params := zones.property(*occurrence.OriginalStart, loc)
+		event.SetProperty(ics.ComponentPropertyRecurrenceId, value, params...)
+		if occurrence.Hosts, err = c.hostRepo.ListHosts(ctx, occurrence.ID, false); err != nil {
+			return nil, err
+		}
+		if !c.canSeeMeetingDetails(ctx, occurrence, userID) {
+			occurrence.HideMeetingDetails()
+		}
+		setSessionEventFields(event, occurrence, zones, c.frontendURL)
+	}
+	return series, nil
+}
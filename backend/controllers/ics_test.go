@@ -0,0 +1,177 @@
+package controllers
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/BuzzLyutic/Skill-sharing-web-platform/models"
+	"github.com/arran4/golang-ical"
+	"github.com/google/uuid"
+)
+
+const testFrontendURL = "https://skills.example.com"
+
+func TestZoneObservances_DSTTransitions(t *testing.T) {
+	loc, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skipf("tzdata unavailable: %v", err)
+	}
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, loc)
+	to := time.Date(2025, 12, 31, 0, 0, 0, 0, loc)
+
+	observances := zoneObservances(loc, from, to)
+	if len(observances) != 3 {
+		t.Fatalf("expected initial observance and 2 transitions, got %d", len(observances))
+	}
+
+	spring := observances[1]
+	if !spring.dst || spring.offsetFrom != 3600 || spring.offsetTo != 7200 {
+		t.Errorf("unexpected spring transition: %+v", spring)
+	}
+	// Переход на летнее время 30 марта 2025 в 02:00 по местному (01:00 UTC)
+	if want := time.Date(2025, 3, 30, 1, 0, 0, 0, time.UTC); !spring.start.Equal(want) {
+		t.Errorf("spring transition at %s, want %s", spring.start.UTC(), want)
+	}
+	if autumn := observances[2]; autumn.dst || autumn.offsetTo != 3600 {
+		t.Errorf("unexpected autumn transition: %+v", autumn)
+	}
+}
+
+func TestZoneObservances_NoDST(t *testing.T) {
+	loc, err := time.LoadLocation("Asia/Tokyo")
+	if err != nil {
+		t.Skipf("tzdata unavailable: %v", err)
+	}
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, loc)
+
+	observances := zoneObservances(loc, from, from.AddDate(1, 0, 0))
+	if len(observances) != 1 {
+		t.Fatalf("expected a single observance, got %d", len(observances))
+	}
+	if got := formatUTCOffset(observances[0].offsetTo); got != "+0900" {
+		t.Errorf("offset = %s, want +0900", got)
+	}
+}
+
+func TestSetSessionEventFields_UsesTZID(t *testing.T) {
+	start := time.Date(2025, 7, 1, 16, 0, 0, 0, time.UTC)
+	session := &models.Session{
+		ID:       uuid.New(),
+		Title:    "Go workshop",
+		DateTime: start,
+		EndTime:  start.Add(2 * time.Hour),
+		TimeZone: "Europe/Berlin",
+	}
+
+	cal := ics.NewCalendar()
+	zones := newICSTimezones()
+	setSessionEventFields(cal.AddEvent(session.ID.String()), session, zones, testFrontendURL)
+	zones.addTo(cal)
+	out := cal.Serialize()
+
+	for _, want := range []string{
+		"DTSTART;TZID=Europe/Berlin:20250701T180000",
+		"DTEND;TZID=Europe/Berlin:20250701T200000",
+		"BEGIN:VTIMEZONE",
+		"TZID:Europe/Berlin",
+		"BEGIN:DAYLIGHT",
+		"TZOFFSETTO:+0200",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("ICS output does not contain %q:\n%s", want, out)
+		}
+	}
+	if strings.Index(out, "BEGIN:VTIMEZONE") > strings.Index(out, "BEGIN:VEVENT") {
+		t.Errorf("VTIMEZONE must precede VEVENT")
+	}
+}
+
+func TestSetSessionEventFields_UTC(t *testing.T) {
+	start := time.Date(2025, 7, 1, 16, 0, 0, 0, time.UTC)
+	session := &models.Session{ID: uuid.New(), DateTime: start, EndTime: start.Add(time.Hour), TimeZone: "UTC"}
+
+	cal := ics.NewCalendar()
+	zones := newICSTimezones()
+	setSessionEventFields(cal.AddEvent(session.ID.String()), session, zones, testFrontendURL)
+	zones.addTo(cal)
+	out := cal.Serialize()
+
+	if !strings.Contains(out, "DTEND:20250701T170000Z") {
+		t.Errorf("expected UTC DTEND, got:\n%s", out)
+	}
+	if strings.Contains(out, "VTIMEZONE") {
+		t.Errorf("UTC sessions must not produce VTIMEZONE")
+	}
+	if !strings.Contains(strings.ReplaceAll(out, "\n ", ""), "URL:"+testFrontendURL+"/sessions/"+session.ID.String()) {
+		t.Errorf("expected URL on the configured frontend, got:\n%s", out)
+	}
+}
+
+func TestSetSessionEventFields_Hosts(t *testing.T) {
+	start := time.Date(2025, 7, 1, 16, 0, 0, 0, time.UTC)
+	session := &models.Session{
+		ID: uuid.New(), DateTime: start, EndTime: start.Add(time.Hour), TimeZone: "UTC",
+		Hosts: []models.SessionHost{
+			{Role: models.HostRoleOwner, Status: models.HostStatusAccepted, Name: "Owner", Email: "owner@example.com"},
+			{Role: models.HostRoleCoHost, Status: models.HostStatusAccepted, Name: "Co", Email: "co@example.com"},
+			{Role: models.HostRoleAssistant, Status: models.HostStatusInvited, Name: "Pending", Email: "pending@example.com"},
+		},
+	}
+
+	cal := ics.NewCalendar()
+	setSessionEventFields(cal.AddEvent(session.ID.String()), session, newICSTimezones(), testFrontendURL)
+	out := strings.ReplaceAll(cal.Serialize(), "\r\n ", "") // Склеиваем перенесенные строки
+
+	if !strings.Contains(out, "ORGANIZER;CN=Owner:mailto:owner@example.com") {
+		t.Errorf("expected owner as ORGANIZER:\n%s", out)
+	}
+	if !strings.Contains(out, "ROLE=CHAIR") || !strings.Contains(out, "mailto:co@example.com") {
+		t.Errorf("expected co-host as CHAIR ATTENDEE:\n%s", out)
+	}
+	if strings.Contains(out, "pending@example.com") {
+		t.Errorf("invited hosts must not be exported:\n%s", out)
+	}
+}
+
+func TestSetSessionEventFields_MeetingDetails(t *testing.T) {
+	start := time.Date(2025, 7, 1, 16, 0, 0, 0, time.UTC)
+	meetingURL := "https://meet.example.com/abc-defg"
+	instructions := "Passcode 4321"
+	newSession := func() *models.Session {
+		return &models.Session{
+			ID: uuid.New(), DateTime: start, EndTime: start.Add(time.Hour), TimeZone: "UTC",
+			Description: "Intro to Go", MeetingURL: &meetingURL, JoinInstructions: &instructions,
+		}
+	}
+	serialize := func(session *models.Session) string {
+		cal := ics.NewCalendar()
+		setSessionEventFields(cal.AddEvent(session.ID.String()), session, newICSTimezones(), testFrontendURL)
+		return strings.ReplaceAll(cal.Serialize(), "\r\n ", "")
+	}
+
+	out := serialize(newSession())
+	if !strings.Contains(out, meetingURL) || !strings.Contains(out, instructions) {
+		t.Errorf("expected meeting details in description:\n%s", out)
+	}
+
+	hidden := newSession()
+	hidden.HideMeetingDetails()
+	out = serialize(hidden)
+	if strings.Contains(out, meetingURL) || strings.Contains(out, instructions) {
+		t.Errorf("hidden meeting details must not be exported:\n%s", out)
+	}
+	if !strings.Contains(out, "DESCRIPTION:Intro to Go") {
+		t.Errorf("expected plain description:\n%s", out)
+	}
+}
+
+func TestSeriesSequence(t *testing.T) {
+	if got := seriesSequence(nil); got != 0 {
+		t.Errorf("empty series: expected SEQUENCE 0, got %d", got)
+	}
+	occurrences := []models.Session{{Version: 2}, {Version: 5}, {Version: 3}}
+	if got := seriesSequence(occurrences); got != 5 {
+		t.Errorf("expected the highest occurrence version 5, got %d", got)
+	}
+}
@@ -0,0 +1,323 @@
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/BuzzLyutic/Skill-sharing-web-platform/importer"
+	"github.com/BuzzLyutic/Skill-sharing-web-platform/models"
+	"github.com/BuzzLyutic/Skill-sharing-web-platform/repositories"
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+)
+
+// maxImportFileSize - максимальный размер файла импорта
+const maxImportFileSize = 5 << 20
+
+// ImportController обрабатывает массовый импорт сессий из CSV и iCalendar
+type ImportController struct {
+	sessionRepo  *repositories.SessionRepository
+	categoryRepo *repositories.CategoryRepository
+}
+
+// NewImportController создает новый контроллер импорта
+func NewImportController(sessionRepo *repositories.SessionRepository, categoryRepo *repositories.CategoryRepository) *ImportController {
+	return &ImportController{sessionRepo: sessionRepo, categoryRepo: categoryRepo}
+}
+
+// Import обрабатывает POST /api/sessions/import - multipart-форма с полями:
+//
+//	file             - CSV (столбцы см. importer.CSVColumns) или .ics
+//	format           - csv или ics; по умолчанию определяется по расширению файла
+//	dry_run          - true: только проверить и вернуть предпросмотр, ничего не создавая
+//	allow_conflicts  - true: создать сессии, даже если они пересекаются с другими сессиями пользователя
+//	time_zone, category, location, max_participants, draft - значения для незаданных в файле полей
+//
+// Каждая строка проверяется по правилам SessionRequest. Сессии создаются одной транзакцией и только
+// если все строки корректны; иначе ответ 422 с отчетом по строкам.
+func (c *ImportController) Import(ctx *gin.Context) {
+	ctx.Request.Body = http.MaxBytesReader(ctx.Writer, ctx.Request.Body, maxImportFileSize+multipartOverhead)
+
+	userID, ok := getUserIDFromContext(ctx)
+	if !ok {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized: User identification failed"})
+		return
+	}
+
+	fileHeader, err := ctx.FormFile("file")
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			ctx.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": fmt.Sprintf("File exceeds the maximum size of %d MB", maxImportFileSize>>20)})
+		} else {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "A file is required in the 'file' form field"})
+		}
+		return
+	}
+	if fileHeader.Size > maxImportFileSize {
+		ctx.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": fmt.Sprintf("File exceeds the maximum size of %d MB", maxImportFileSize>>20)})
+		return
+	}
+
+	format := strings.ToLower(strings.TrimSpace(ctx.PostForm("format")))
+	if format == "" {
+		switch strings.ToLower(filepath.Ext(fileHeader.Filename)) {
+		case ".csv":
+			format = "csv"
+		case ".ics", ".ical", ".ifb":
+			format = "ics"
+		}
+	}
+	if format != "csv" && format != "ics" {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Unsupported import format: use a .csv or .ics file or set format=csv|ics"})
+		return
+	}
+
+	defaults, dryRun, err := parseImportOptions(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	allowConflicts := false
+	if value := ctx.DefaultPostForm("allow_conflicts", ctx.Query("allow_conflicts")); value != "" {
+		if allowConflicts, err = strconv.ParseBool(value); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "allow_conflicts must be true or false"})
+			return
+		}
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		log.Printf("ERROR opening import file for user %s: %v", userID, err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to read uploaded file"})
+		return
+	}
+	defer file.Close()
+	data, err := io.ReadAll(file)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to read uploaded file"})
+		return
+	}
+
+	var rows []importer.Row
+	if format == "csv" {
+		rows, err = importer.ParseCSV(data, defaults)
+	} else {
+		rows, err = importer.ParseICS(data, defaults)
+	}
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	categories, err := c.resolveCategories(ctx, rows)
+	if err != nil {
+		log.Printf("ERROR resolving import categories for user %s: %v", userID, err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to validate categories"})
+		return
+	}
+
+	report, requests := buildImportReport(format, dryRun, rows, categories)
+	if !allowConflicts {
+		if err := c.markConflicts(ctx, userID, &report, requests); err != nil {
+			log.Printf("ERROR checking import schedule conflicts for user %s: %v", userID, err)
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check schedule conflicts"})
+			return
+		}
+	}
+	switch {
+	case dryRun:
+		ctx.JSON(http.StatusOK, gin.H{"report": report})
+		return
+	case report.Invalid > 0:
+		ctx.JSON(http.StatusUnprocessableEntity, gin.H{"error": "File contains invalid rows; no sessions were created", "report": report})
+		return
+	case report.Conflicting > 0:
+		ctx.JSON(http.StatusConflict, gin.H{
+			"error":  fmt.Sprintf("%d sessions overlap with your other sessions; set allow_conflicts to import anyway", report.Conflicting),
+			"report": report,
+		})
+		return
+	case report.Valid == 0:
+		ctx.JSON(http.StatusUnprocessableEntity, gin.H{"error": "File contains no sessions to import", "report": report})
+		return
+	}
+
+	sessions, err := c.sessionRepo.CreateMany(ctx.Request.Context(), userID, requests)
+	if err != nil {
+		log.Printf("ERROR importing %d sessions for user %s: %v", len(requests), userID, err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create sessions; nothing was imported"})
+		return
+	}
+	created := 0
+	for i := range report.Rows {
+		if report.Rows[i].Status != models.ImportRowValid {
+			continue
+		}
+		sessionID := sessions[created].ID
+		report.Rows[i].SessionID = &sessionID
+		report.Rows[i].Status = models.ImportRowCreated
+		created++
+	}
+	report.Created = created
+	ctx.JSON(http.StatusCreated, gin.H{"report": report})
+}
+
+// parseImportOptions разбирает параметры импорта из формы
+func parseImportOptions(ctx *gin.Context) (importer.Defaults, bool, error) {
+	defaults := importer.Defaults{
+		Category: strings.TrimSpace(ctx.PostForm("category")),
+		Location: strings.TrimSpace(ctx.PostForm("location")),
+		TimeZone: strings.TrimSpace(ctx.PostForm("time_zone")),
+	}
+	if defaults.TimeZone == "" {
+		defaults.TimeZone = models.DefaultTimeZone
+	}
+	if _, err := time.LoadLocation(defaults.TimeZone); err != nil {
+		return defaults, false, fmt.Errorf("unknown time_zone %q", defaults.TimeZone)
+	}
+	if value := ctx.PostForm("max_participants"); value != "" {
+		maxParticipants, err := strconv.Atoi(value)
+		if err != nil || maxParticipants < 1 {
+			return defaults, false, errors.New("max_participants must be a positive integer")
+		}
+		defaults.MaxParticipants = maxParticipants
+	}
+	var err error
+	if value := ctx.PostForm("draft"); value != "" {
+		if defaults.Draft, err = strconv.ParseBool(value); err != nil {
+			return defaults, false, errors.New("draft must be true or false")
+		}
+	}
+	dryRun := false
+	if value := ctx.DefaultPostForm("dry_run", ctx.Query("dry_run")); value != "" {
+		if dryRun, err = strconv.ParseBool(value); err != nil {
+			return defaults, false, errors.New("dry_run must be true or false")
+		}
+	}
+	return defaults, dryRun, nil
+}
+
+// resolveCategories сопоставляет различающиеся категории строк файла с деревом категорий.
+// Возвращает slug по категории в нижнем регистре; неизвестных категорий в результате нет.
+func (c *ImportController) resolveCategories(ctx *gin.Context, rows []importer.Row) (map[string]string, error) {
+	categories := make(map[string]string)
+	checked := make(map[string]bool)
+	for _, row := range rows {
+		key := strings.ToLower(strings.TrimSpace(row.Request.Category))
+		if key == "" || checked[key] {
+			continue
+		}
+		checked[key] = true
+		category, err := c.categoryRepo.Resolve(ctx.Request.Context(), key)
+		if errors.Is(err, repositories.ErrUnknownCategory) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		categories[key] = category.Slug
+	}
+	return categories, nil
+}
+
+// markConflicts отмечает в отчете корректные строки, пересекающиеся по времени с другими сессиями
+// пользователя. requests - нормализованные запросы корректных строк в порядке отчета (см. buildImportReport).
+func (c *ImportController) markConflicts(ctx *gin.Context, userID uuid.UUID, report *models.SessionImportReport, requests []models.SessionRequest) error {
+	next := 0
+	for i := range report.Rows {
+		if report.Rows[i].Status != models.ImportRowValid {
+			continue
+		}
+		req := requests[next]
+		next++
+		conflicts, err := c.sessionRepo.FindConflicts(ctx.Request.Context(), userID, req.DateTime, *req.EndTime, uuid.Nil)
+		if err != nil {
+			return err
+		}
+		if len(conflicts) > 0 {
+			report.Rows[i].Conflicts = conflicts
+			report.Conflicting++
+		}
+	}
+	return nil
+}
+
+// buildImportReport проверяет разобранные строки по правилам SessionRequest и нормализует их,
+// заменяя категорию на slug из categories (см. resolveCategories).
+// Возвращает отчет и нормализованные запросы корректных строк в порядке файла.
+func buildImportReport(format string, dryRun bool, rows []importer.Row, categories map[string]string) (models.SessionImportReport, []models.SessionRequest) {
+	report := models.SessionImportReport{Format: format, DryRun: dryRun, Total: len(rows), Rows: make([]models.SessionImportRow, 0, len(rows))}
+	requests := []models.SessionRequest{}
+	for _, row := range rows {
+		result := models.SessionImportRow{Row: row.Line, Title: row.Request.Title, Errors: row.Errors}
+		req := row.Request
+		switch {
+		case row.Skipped != "":
+			result.Status = models.ImportRowSkipped
+			result.Errors = []string{row.Skipped}
+			report.Skipped++
+			report.Rows = append(report.Rows, result)
+			continue
+		case len(result.Errors) == 0:
+			if err := binding.Validator.ValidateStruct(&req); err != nil {
+				result.Errors = validationMessages(err, req)
+			} else if err := req.Normalize(); err != nil {
+				result.Errors = []string{err.Error()}
+			} else if slug, ok := categories[strings.ToLower(strings.TrimSpace(req.Category))]; ok {
+				req.Category = slug
+			} else {
+				result.Errors = []string{"category: " + repositories.ErrUnknownCategory.Error()}
+			}
+		}
+
+		if len(result.Errors) > 0 {
+			result.Status = models.ImportRowInvalid
+			report.Invalid++
+		} else {
+			result.Status = models.ImportRowValid
+			report.Valid++
+			requests = append(requests, req)
+			if dryRun {
+				preview := req
+				result.Preview = &preview
+			}
+		}
+		report.Rows = append(report.Rows, result)
+	}
+	return report, requests
+}
+
+// validationMessages описывает ошибки валидатора по именам полей JSON
+func validationMessages(err error, target interface{}) []string {
+	var validationErrors validator.ValidationErrors
+	if !errors.As(err, &validationErrors) {
+		return []string{err.Error()}
+	}
+	targetType := reflect.TypeOf(target)
+	messages := make([]string, 0, len(validationErrors))
+	for _, fieldErr := range validationErrors {
+		name := fieldErr.Field()
+		if field, ok := targetType.FieldByName(fieldErr.StructField()); ok {
+			if tag := strings.Split(field.Tag.Get("json"), ",")[0]; tag != "" {
+				name = tag
+			}
+		}
+		rule := fieldErr.Tag()
+		if fieldErr.Param() != "" {
+			rule += "=" + fieldErr.Param()
+		}
+		messages = append(messages, fmt.Sprintf("%s: failed %q validation", name, rule))
+	}
+	return messages
+}
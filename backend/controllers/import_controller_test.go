@@ -0,0 +1,60 @@
+package controllers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/BuzzLyutic/Skill-sharing-web-platform/importer"
+	"github.com/BuzzLyutic/Skill-sharing-web-platform/models"
+)
+
+func TestBuildImportReport(t *testing.T) {
+	start := time.Date(2025, 5, 1, 10, 0, 0, 0, time.UTC)
+	valid := models.SessionRequest{Title: "Go", Category: "Programming", DateTime: start, Location: "online", MaxParticipants: 5}
+	noCategory := valid
+	noCategory.Category = ""
+	noCategory.MaxParticipants = 0
+	endBeforeStart := valid
+	earlier := start.Add(-time.Hour)
+	endBeforeStart.EndTime = &earlier
+	unknownCategory := valid
+	unknownCategory.Category = "Knitting"
+
+	rows := []importer.Row{
+		{Line: 2, Request: valid},
+		{Line: 3, Request: noCategory},
+		{Line: 4, Request: endBeforeStart},
+		{Line: 5, Request: valid, Errors: []string{"date_time: invalid time"}},
+		{Line: 6, Request: valid, Skipped: "event is cancelled"},
+		{Line: 7, Request: unknownCategory},
+	}
+	categories := map[string]string{"programming": "programming"}
+
+	report, requests := buildImportReport("csv", true, rows, categories)
+
+	if report.Total != 6 || report.Valid != 1 || report.Invalid != 4 || report.Skipped != 1 {
+		t.Fatalf("unexpected counters: %+v", report)
+	}
+	if len(requests) != 1 || requests[0].EndTime == nil || !requests[0].EndTime.Equal(start.Add(models.DefaultSessionDuration)) {
+		t.Fatalf("valid request must be normalized: %+v", requests)
+	}
+	if requests[0].Category != "programming" {
+		t.Errorf("category = %q, want the canonical slug", requests[0].Category)
+	}
+	if report.Rows[0].Preview == nil || report.Rows[0].Status != models.ImportRowValid {
+		t.Errorf("dry run must include a preview of valid rows: %+v", report.Rows[0])
+	}
+	want := []string{`category: failed "required" validation`, `max_participants: failed "required" validation`}
+	if got := report.Rows[1].Errors; len(got) != 2 || got[0] != want[0] || got[1] != want[1] {
+		t.Errorf("validation errors = %v, want %v", got, want)
+	}
+	if got := report.Rows[2].Errors; len(got) != 1 || got[0] != models.ErrEndBeforeStart.Error() {
+		t.Errorf("normalize errors = %v", got)
+	}
+	if report.Rows[4].Status != models.ImportRowSkipped {
+		t.Errorf("row 6 status = %s, want skipped", report.Rows[4].Status)
+	}
+	if got := report.Rows[5].Errors; len(got) != 1 || got[0] != "category: unknown category" {
+		t.Errorf("unknown category errors = %v", got)
+	}
+}
@@ -0,0 +1,134 @@
+package controllers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/BuzzLyutic/Skill-sharing-web-platform/models"
+	"github.com/BuzzLyutic/Skill-sharing-web-platform/repositories"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// InviteController обрабатывает HTTP-запросы, связанные с приглашениями в сессии
+type InviteController struct {
+	repo        *repositories.InviteRepository
+	sessionRepo *repositories.SessionRepository
+	hostRepo    *repositories.HostRepository
+	frontendURL string
+}
+
+// NewInviteController создает новый контроллер приглашений.
+// frontendURL - адрес веб-клиента, из которого строятся ссылки-приглашения.
+func NewInviteController(
+	repo *repositories.InviteRepository,
+	sessionRepo *repositories.SessionRepository,
+	hostRepo *repositories.HostRepository,
+	frontendURL string,
+) *InviteController {
+	return &InviteController{repo: repo, sessionRepo: sessionRepo, hostRepo: hostRepo, frontendURL: strings.TrimSuffix(frontendURL, "/")}
+}
+
+// inviteResponse - приглашение вместе с готовой ссылкой и признаком того, действует ли оно
+type inviteResponse struct {
+	models.SessionInvite
+	URL    string `json:"url"`
+	Active bool   `json:"active"`
+}
+
+// List обрабатывает GET /api/sessions/:id/invites
+func (c *InviteController) List(ctx *gin.Context) {
+	session, ok := c.loadSession(ctx)
+	if !ok {
+		return
+	}
+
+	invites, err := c.repo.ListBySession(ctx.Request.Context(), session.ID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve session invites"})
+		return
+	}
+	now := time.Now()
+	response := make([]inviteResponse, 0, len(invites))
+	for _, invite := range invites {
+		response = append(response, c.newInviteResponse(invite, now))
+	}
+	ctx.JSON(http.StatusOK, response)
+}
+
+// Create обрабатывает POST /api/sessions/:id/invites - создает новое приглашение
+func (c *InviteController) Create(ctx *gin.Context) {
+	var req models.SessionInviteRequest
+	if ctx.Request.ContentLength > 0 {
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			log.Printf("Failed to bind JSON for session invite: %v", err)
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+			return
+		}
+	}
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(time.Now()) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "expires_at must be in the future"})
+		return
+	}
+
+	session, ok := c.loadSession(ctx)
+	if !ok {
+		return
+	}
+	if session.Status.IsFinal() {
+		ctx.JSON(http.StatusConflict, gin.H{"error": "Cannot create invites for a cancelled or completed session"})
+		return
+	}
+
+	userID, _ := getUserIDFromContext(ctx)
+	invite, err := c.repo.Create(ctx.Request.Context(), session.ID, userID, req)
+	if err != nil {
+		log.Printf("ERROR creating invite for session %s: %v", session.ID, err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create session invite"})
+		return
+	}
+	ctx.JSON(http.StatusCreated, c.newInviteResponse(*invite, time.Now()))
+}
+
+// Revoke обрабатывает DELETE /api/sessions/:id/invites/:invite_id - отзывает приглашение.
+// Уже присоединившиеся по нему участники остаются в сессии.
+func (c *InviteController) Revoke(ctx *gin.Context) {
+	inviteID, err := uuid.Parse(ctx.Param("invite_id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid invite ID format"})
+		return
+	}
+
+	session, ok := c.loadSession(ctx)
+	if !ok {
+		return
+	}
+
+	invite, err := c.repo.Revoke(ctx.Request.Context(), session.ID, inviteID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrInviteNotFound) {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		} else {
+			log.Printf("ERROR revoking invite %s of session %s: %v", inviteID, session.ID, err)
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to revoke session invite"})
+		}
+		return
+	}
+	ctx.JSON(http.StatusOK, c.newInviteResponse(*invite, time.Now()))
+}
+
+// loadSession загружает сессию :id, если текущий пользователь может управлять приглашениями
+func (c *InviteController) loadSession(ctx *gin.Context) (*models.Session, bool) {
+	return loadSessionForPermission(ctx, c.sessionRepo, c.hostRepo, models.PermissionManageInvites,
+		"Forbidden: Only session organizers can manage invites")
+}
+
+// newInviteResponse добавляет к приглашению ссылку на страницу сессии с кодом
+func (c *InviteController) newInviteResponse(invite models.SessionInvite, now time.Time) inviteResponse {
+	link := c.frontendURL + "/sessions/" + invite.SessionID.String() + "?invite=" + url.QueryEscape(invite.Code)
+	return inviteResponse{SessionInvite: invite, URL: link, Active: invite.IsActive(now)}
+}
@@ -0,0 +1,200 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/BuzzLyutic/Skill-sharing-web-platform/models"
+	"github.com/BuzzLyutic/Skill-sharing-web-platform/repositories"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// JoinRequestController обрабатывает очередь заявок на участие в сессиях с одобрением
+type JoinRequestController struct {
+	repo        *repositories.JoinRequestRepository
+	sessionRepo *repositories.SessionRepository
+	hostRepo    *repositories.HostRepository
+	notifRepo   *repositories.NotificationRepository
+}
+
+// NewJoinRequestController создает новый контроллер заявок на участие
+func NewJoinRequestController(
+	repo *repositories.JoinRequestRepository,
+	sessionRepo *repositories.SessionRepository,
+	hostRepo *repositories.HostRepository,
+	notifRepo *repositories.NotificationRepository,
+) *JoinRequestController {
+	return &JoinRequestController{repo: repo, sessionRepo: sessionRepo, hostRepo: hostRepo, notifRepo: notifRepo}
+}
+
+// List обрабатывает GET /api/sessions/:id/requests. По умолчанию возвращает ожидающие заявки,
+// ?status=approved|rejected|withdrawn - историю решений.
+func (c *JoinRequestController) List(ctx *gin.Context) {
+	status := models.JoinRequestStatus(ctx.DefaultQuery("status", string(models.JoinRequestStatusPending)))
+	switch status {
+	case models.JoinRequestStatusPending, models.JoinRequestStatusApproved, models.JoinRequestStatusRejected, models.JoinRequestStatusWithdrawn:
+	default:
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid status, expected pending, approved, rejected or withdrawn"})
+		return
+	}
+
+	session, ok := c.loadSession(ctx)
+	if !ok {
+		return
+	}
+	requests, err := c.repo.ListBySession(ctx.Request.Context(), session.ID, status)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve join requests"})
+		return
+	}
+	ctx.JSON(http.StatusOK, requests)
+}
+
+// Approve обрабатывает POST /api/sessions/:id/requests/:user_id/approve.
+// Если мест уже нет, заявитель попадает в очередь ожидания. Пока заявка ждала решения, заявитель мог
+// записаться на другие сессии в то же время, поэтому пересечения проверяются заново, как в JoinSession.
+func (c *JoinRequestController) Approve(ctx *gin.Context) {
+	session, applicantID, decision, ok := c.parseDecision(ctx)
+	if !ok {
+		return
+	}
+	allowConflicts := decision.AllowConflicts || ctx.Query("allow_conflicts") == "true"
+	if !allowConflicts && !checkScheduleConflicts(ctx, c.sessionRepo, applicantID, session.DateTime, session.EndTime, session.ID) {
+		return
+	}
+
+	hostID, _ := getUserIDFromContext(ctx)
+	requestContext := ctx.Request.Context()
+	result, err := c.repo.Approve(requestContext, session.ID, applicantID, hostID, decision.Message)
+	if err != nil {
+		respondJoinRequestError(ctx, session.ID, err)
+		return
+	}
+
+	message := fmt.Sprintf("Your request to join '%s' has been approved.", session.Title)
+	if result.Status == models.ParticipationStatusWaitlisted {
+		message = fmt.Sprintf("Your request to join '%s' has been approved, but the session is full: you are #%d on the waitlist.",
+			session.Title, result.WaitlistPosition)
+	}
+	c.notifyApplicant(requestContext, applicantID, session, models.NotificationTypeJoinApproved, message, decision.Message)
+	ctx.JSON(http.StatusOK, gin.H{"message": "Join request approved", "status": result.Status, "waitlist_position": result.WaitlistPosition})
+}
+
+// Reject обрабатывает POST /api/sessions/:id/requests/:user_id/reject
+func (c *JoinRequestController) Reject(ctx *gin.Context) {
+	session, applicantID, decision, ok := c.parseDecision(ctx)
+	if !ok {
+		return
+	}
+
+	hostID, _ := getUserIDFromContext(ctx)
+	requestContext := ctx.Request.Context()
+	if err := c.repo.Reject(requestContext, session.ID, applicantID, hostID, decision.Message); err != nil {
+		respondJoinRequestError(ctx, session.ID, err)
+		return
+	}
+
+	c.notifyApplicant(requestContext, applicantID, session, models.NotificationTypeJoinRejected,
+		fmt.Sprintf("Your request to join '%s' has been declined.", session.Title), decision.Message)
+	ctx.JSON(http.StatusOK, gin.H{"message": "Join request rejected"})
+}
+
+// parseDecision разбирает :user_id и необязательное тело решения и загружает сессию.
+// При ошибке отвечает клиенту.
+func (c *JoinRequestController) parseDecision(ctx *gin.Context) (*models.Session, uuid.UUID, models.JoinRequestDecision, bool) {
+	var decision models.JoinRequestDecision
+	applicantID, err := uuid.Parse(ctx.Param("user_id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID format"})
+		return nil, uuid.Nil, decision, false
+	}
+	if ctx.Request.ContentLength > 0 {
+		if err := ctx.ShouldBindJSON(&decision); err != nil {
+			log.Printf("Failed to bind JSON for join request decision: %v", err)
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+			return nil, uuid.Nil, decision, false
+		}
+	}
+	session, ok := c.loadSession(ctx)
+	return session, applicantID, decision, ok
+}
+
+// loadSession загружает сессию :id, если текущий пользователь может рассматривать заявки
+func (c *JoinRequestController) loadSession(ctx *gin.Context) (*models.Session, bool) {
+	return loadSessionForPermission(ctx, c.sessionRepo, c.hostRepo, models.PermissionReviewRequests,
+		"Forbidden: Only session organizers can review join requests")
+}
+
+// notifyApplicant уведомляет заявителя о решении, добавляя комментарий организатора. Ошибки только логируются.
+func (c *JoinRequestController) notifyApplicant(ctx context.Context, applicantID uuid.UUID, session *models.Session, notifType models.NotificationType, message, comment string) {
+	if comment != "" {
+		message += fmt.Sprintf(" Message from the host: %s", comment)
+	}
+	newNotif := models.Notification{
+		UserID:      applicantID,
+		Message:     message,
+		Type:        notifType,
+		RelatedID:   &session.ID,
+		RelatedType: "session",
+	}
+	if _, errNotif := c.notifRepo.CreateNotification(ctx, newNotif); errNotif != nil {
+		log.Printf("WARN: Failed to create %s notification for user %s: %v", notifType, applicantID, errNotif)
+	}
+}
+
+// notifyJoinRequestReviewers уведомляет организаторов, которые могут рассматривать заявки,
+// о новой заявке пользователя applicantID. Ошибки только логируются.
+func notifyJoinRequestReviewers(
+	ctx context.Context,
+	hostRepo *repositories.HostRepository,
+	userRepo *repositories.UserRepository,
+	notifRepo *repositories.NotificationRepository,
+	session *models.Session,
+	applicantID uuid.UUID,
+) {
+	hosts, err := hostRepo.ListHosts(ctx, session.ID, false)
+	if err != nil {
+		log.Printf("WARN: Failed to list hosts of session %s for join request notification: %v", session.ID, err)
+		return
+	}
+	applicantName := "A user"
+	if applicant, err := userRepo.GetByID(ctx, applicantID); err == nil {
+		applicantName = fmt.Sprintf("User '%s'", applicant.Name)
+	} else {
+		log.Printf("WARN: Failed to load user %s for join request notification: %v", applicantID, err)
+	}
+
+	message := fmt.Sprintf("%s asked to join your session '%s'.", applicantName, session.Title)
+	for _, host := range hosts {
+		if !host.Role.Can(models.PermissionReviewRequests) {
+			continue
+		}
+		newNotif := models.Notification{
+			UserID:      host.UserID,
+			Message:     message,
+			Type:        models.NotificationTypeJoinRequest,
+			RelatedID:   &session.ID,
+			RelatedType: "session",
+		}
+		if _, errNotif := notifRepo.CreateNotification(ctx, newNotif); errNotif != nil {
+			log.Printf("WARN: Failed to create join request notification for host %s: %v", host.UserID, errNotif)
+		}
+	}
+}
+
+// respondJoinRequestError преобразует ошибки операций с заявками в HTTP-ответ
+func respondJoinRequestError(ctx *gin.Context, sessionID uuid.UUID, err error) {
+	switch {
+	case errors.Is(err, repositories.ErrJoinRequestNotFound), errors.Is(err, repositories.ErrSessionNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, repositories.ErrSessionNotOpen):
+		ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		log.Printf("ERROR processing join requests of session %s: %v", sessionID, err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to process join request"})
+	}
+}
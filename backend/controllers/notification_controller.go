@@ -25,14 +25,17 @@ func (c *NotificationController) GetMyUnreadNotifications(ctx *gin.Context) {
 		return
 	}
 
-	limit := 10 // По умолчанию
+	page, ok := parsePageRequest(ctx, 10) // По умолчанию 10
+	if !ok {
+		return
+	}
 
-	notifications, err := c.repo.GetUnreadNotificationsForUser(ctx.Request.Context(), userID, limit)
+	notifications, info, err := c.repo.GetUnreadNotificationsForUser(ctx.Request.Context(), userID, page)
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get notifications"})
 		return
 	}
-	ctx.JSON(http.StatusOK, notifications)
+	ctx.JSON(http.StatusOK, gin.H{"data": notifications, "meta": pageMeta(info)})
 }
 
 // MarkAsRead обрабатывает POST /api/notifications/:notification_id/read
@@ -0,0 +1,53 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/BuzzLyutic/Skill-sharing-web-platform/models"
+	"github.com/gin-gonic/gin"
+)
+
+// maxPageLimit - максимальный размер страницы при keyset-пагинации
+const maxPageLimit = 100
+
+// parsePageRequest разбирает параметры keyset-пагинации: limit, cursor (next_cursor или prev_cursor
+// из предыдущего ответа) и count=true для подсчета общего количества.
+// При некорректном курсоре отвечает 400 и возвращает false.
+func parsePageRequest(ctx *gin.Context, defaultLimit int) (models.PageRequest, bool) {
+	page := models.PageRequest{Limit: defaultLimit}
+	if limitStr := ctx.Query("limit"); limitStr != "" {
+		if l, err := strconv.Atoi(limitStr); err == nil && l > 0 {
+			page.Limit = min(l, maxPageLimit)
+		}
+	}
+	if cursorStr := ctx.Query("cursor"); cursorStr != "" {
+		cursor, err := models.DecodeCursor(cursorStr)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return page, false
+		}
+		page.Cursor = cursor
+	}
+	page.WithCount, _ = strconv.ParseBool(ctx.Query("count"))
+	return page, true
+}
+
+// pageMeta возвращает блок meta ответа для keyset-пагинации
+func pageMeta(info models.PageInfo) gin.H {
+	meta := gin.H{
+		"per_page":    info.PerPage,
+		"next_cursor": info.NextCursor,
+		"prev_cursor": info.PrevCursor,
+	}
+	if info.TotalItems != nil {
+		meta["total_items"] = *info.TotalItems
+	}
+	return meta
+}
+
+// useOffsetPagination сообщает, запросил ли клиент постраничную навигацию по номеру страницы (?page=)
+// вместо курсоров. Номер страницы сохранен для совместимости и для ранжированного поиска.
+func useOffsetPagination(ctx *gin.Context) bool {
+	return ctx.Query("page") != ""
+}
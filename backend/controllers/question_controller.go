@@ -0,0 +1,255 @@
+package controllers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/BuzzLyutic/Skill-sharing-web-platform/models"
+	"github.com/BuzzLyutic/Skill-sharing-web-platform/repositories"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// QuestionController обрабатывает Q&A сессии: вопросы участников, голоса и модерацию организаторами
+type QuestionController struct {
+	repo        *repositories.QuestionRepository
+	sessionRepo *repositories.SessionRepository
+	hostRepo    *repositories.HostRepository
+}
+
+// NewQuestionController создает новый контроллер вопросов
+func NewQuestionController(
+	repo *repositories.QuestionRepository,
+	sessionRepo *repositories.SessionRepository,
+	hostRepo *repositories.HostRepository,
+) *QuestionController {
+	return &QuestionController{repo: repo, sessionRepo: sessionRepo, hostRepo: hostRepo}
+}
+
+// List обрабатывает GET /api/sessions/:id/questions - вопросы по убыванию голосов (закрепленные первыми)
+func (c *QuestionController) List(ctx *gin.Context) {
+	sessionID, userID, ok := c.loadSession(ctx, false)
+	if !ok {
+		return
+	}
+	questions, err := c.repo.ListBySession(ctx.Request.Context(), sessionID, userID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve questions"})
+		return
+	}
+	ctx.JSON(http.StatusOK, questions)
+}
+
+// Create обрабатывает POST /api/sessions/:id/questions - вопрос может задать только участник
+func (c *QuestionController) Create(ctx *gin.Context) {
+	var req models.QuestionRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		log.Printf("Failed to bind JSON for question: %v", err)
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+	body := strings.TrimSpace(req.Body)
+	if body == "" {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Question must not be empty"})
+		return
+	}
+
+	sessionID, userID, ok := c.loadSession(ctx, true)
+	if !ok {
+		return
+	}
+	question, err := c.repo.Create(ctx.Request.Context(), sessionID, userID, body)
+	if err != nil {
+		respondQuestionError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusCreated, question)
+}
+
+// Vote обрабатывает POST /api/sessions/:id/questions/:question_id/vote - один голос на участника
+func (c *QuestionController) Vote(ctx *gin.Context) {
+	questionID, ok := parseQuestionID(ctx)
+	if !ok {
+		return
+	}
+	sessionID, userID, ok := c.loadSession(ctx, true)
+	if !ok {
+		return
+	}
+
+	requestContext := ctx.Request.Context()
+	if err := c.repo.Vote(requestContext, sessionID, questionID, userID); err != nil {
+		respondQuestionError(ctx, err)
+		return
+	}
+	c.respondWithQuestion(ctx, http.StatusOK, sessionID, questionID, userID)
+}
+
+// Unvote обрабатывает DELETE /api/sessions/:id/questions/:question_id/vote
+func (c *QuestionController) Unvote(ctx *gin.Context) {
+	questionID, ok := parseQuestionID(ctx)
+	if !ok {
+		return
+	}
+	sessionID, userID, ok := c.loadSession(ctx, true)
+	if !ok {
+		return
+	}
+
+	if err := c.repo.Unvote(ctx.Request.Context(), sessionID, questionID, userID); err != nil {
+		respondQuestionError(ctx, err)
+		return
+	}
+	c.respondWithQuestion(ctx, http.StatusOK, sessionID, questionID, userID)
+}
+
+// Update обрабатывает PATCH /api/sessions/:id/questions/:question_id - организаторы отмечают вопрос
+// отвеченным и/или закрепляют его
+func (c *QuestionController) Update(ctx *gin.Context) {
+	questionID, ok := parseQuestionID(ctx)
+	if !ok {
+		return
+	}
+	var req models.QuestionUpdateRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		log.Printf("Failed to bind JSON for question update: %v", err)
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+	if req.IsAnswered == nil && req.IsPinned == nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Nothing to update: provide is_answered and/or is_pinned"})
+		return
+	}
+
+	session, ok := loadSessionForPermission(ctx, c.sessionRepo, c.hostRepo, models.PermissionModerateQA,
+		"Forbidden: Only session organizers can moderate questions")
+	if !ok {
+		return
+	}
+	if err := c.repo.UpdateStatus(ctx.Request.Context(), session.ID, questionID, req.IsAnswered, req.IsPinned); err != nil {
+		respondQuestionError(ctx, err)
+		return
+	}
+	userID, _ := getUserIDFromContext(ctx)
+	c.respondWithQuestion(ctx, http.StatusOK, session.ID, questionID, userID)
+}
+
+// Delete обрабатывает DELETE /api/sessions/:id/questions/:question_id.
+// Удалить вопрос может его автор или организатор.
+func (c *QuestionController) Delete(ctx *gin.Context) {
+	questionID, ok := parseQuestionID(ctx)
+	if !ok {
+		return
+	}
+	sessionID, userID, ok := c.loadSession(ctx, false)
+	if !ok {
+		return
+	}
+
+	requestContext := ctx.Request.Context()
+	question, err := c.repo.GetByID(requestContext, sessionID, questionID, userID)
+	if err != nil {
+		respondQuestionError(ctx, err)
+		return
+	}
+	if question.UserID == nil || *question.UserID != userID {
+		role, err := c.hostRepo.GetRole(requestContext, sessionID, userID)
+		if err != nil {
+			log.Printf("ERROR getting host role of user %s in session %s: %v", userID, sessionID, err)
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check session permissions"})
+			return
+		}
+		if !role.Can(models.PermissionModerateQA) {
+			ctx.JSON(http.StatusForbidden, gin.H{"error": "Forbidden: You can only delete your own questions"})
+			return
+		}
+	}
+
+	if err := c.repo.Delete(requestContext, sessionID, questionID); err != nil {
+		respondQuestionError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"message": "Question deleted successfully"})
+}
+
+// loadSession разбирает :id, проверяет существование сессии и доступ текущего пользователя:
+// participantOnly - только участники (задать вопрос, проголосовать), иначе также организаторы.
+func (c *QuestionController) loadSession(ctx *gin.Context, participantOnly bool) (uuid.UUID, uuid.UUID, bool) {
+	sessionID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid session ID format"})
+		return uuid.Nil, uuid.Nil, false
+	}
+	userID, ok := getUserIDFromContext(ctx)
+	if !ok {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized: User identification failed"})
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	requestContext := ctx.Request.Context()
+	if _, err := c.sessionRepo.GetByID(requestContext, sessionID); err != nil {
+		if errors.Is(err, repositories.ErrSessionNotFound) {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		} else {
+			log.Printf("ERROR getting session %s for questions: %v", sessionID, err)
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve session"})
+		}
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	var allowed bool
+	if participantOnly {
+		allowed, err = c.sessionRepo.IsParticipant(requestContext, sessionID, userID)
+	} else {
+		allowed, err = isHostOrParticipant(requestContext, c.sessionRepo, c.hostRepo, sessionID, userID)
+	}
+	if err != nil {
+		log.Printf("ERROR checking access of user %s to session %s: %v", userID, sessionID, err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to verify participation status"})
+		return uuid.Nil, uuid.Nil, false
+	}
+	if !allowed {
+		message := "Only session participants and organizers can access questions"
+		if participantOnly {
+			message = "Only session participants can ask and vote on questions"
+		}
+		ctx.JSON(http.StatusForbidden, gin.H{"error": message})
+		return uuid.Nil, uuid.Nil, false
+	}
+	return sessionID, userID, true
+}
+
+// respondWithQuestion отвечает актуальным состоянием вопроса (голоса, отметки)
+func (c *QuestionController) respondWithQuestion(ctx *gin.Context, status int, sessionID, questionID, viewerID uuid.UUID) {
+	question, err := c.repo.GetByID(ctx.Request.Context(), sessionID, questionID, viewerID)
+	if err != nil {
+		respondQuestionError(ctx, err)
+		return
+	}
+	ctx.JSON(status, question)
+}
+
+// parseQuestionID разбирает :question_id, отвечая 400 при ошибке
+func parseQuestionID(ctx *gin.Context) (uuid.UUID, bool) {
+	questionID, err := uuid.Parse(ctx.Param("question_id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid question ID format"})
+		return uuid.Nil, false
+	}
+	return questionID, true
+}
+
+// respondQuestionError преобразует ошибки репозитория вопросов в HTTP-ответ
+func respondQuestionError(ctx *gin.Context, err error) {
+	switch {
+	case errors.Is(err, repositories.ErrQuestionNotFound), errors.Is(err, repositories.ErrVoteNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, repositories.ErrAlreadyVoted):
+		ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		log.Printf("ERROR processing question: %v", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to process question"})
+	}
+}
@@ -0,0 +1,141 @@
+package controllers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/BuzzLyutic/Skill-sharing-web-platform/models"
+	"github.com/BuzzLyutic/Skill-sharing-web-platform/repositories"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// ReconfirmationController обрабатывает подтверждение участия после переноса сессии
+type ReconfirmationController struct {
+	repo        *repositories.ReconfirmationRepository
+	sessionRepo *repositories.SessionRepository
+	hostRepo    *repositories.HostRepository
+	notifRepo   *repositories.NotificationRepository
+}
+
+// NewReconfirmationController создает новый контроллер подтверждений участия
+func NewReconfirmationController(
+	repo *repositories.ReconfirmationRepository,
+	sessionRepo *repositories.SessionRepository,
+	hostRepo *repositories.HostRepository,
+	notifRepo *repositories.NotificationRepository,
+) *ReconfirmationController {
+	return &ReconfirmationController{repo: repo, sessionRepo: sessionRepo, hostRepo: hostRepo, notifRepo: notifRepo}
+}
+
+// List обрабатывает GET /api/sessions/:id/reconfirmations - ответы участников на перенос (для организаторов)
+func (c *ReconfirmationController) List(ctx *gin.Context) {
+	session, ok := loadSessionForPermission(ctx, c.sessionRepo, c.hostRepo, models.PermissionEdit, "Forbidden: Only session hosts can view reconfirmations")
+	if !ok {
+		return
+	}
+	reconfirmations, err := c.repo.ListBySession(ctx.Request.Context(), session.ID)
+	if err != nil {
+		log.Printf("ERROR listing reconfirmations of session %s: %v", session.ID, err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve reconfirmations"})
+		return
+	}
+	ctx.JSON(http.StatusOK, reconfirmations)
+}
+
+// Get обрабатывает GET /api/sessions/:id/reconfirmation - запрос подтверждения текущего пользователя
+func (c *ReconfirmationController) Get(ctx *gin.Context) {
+	sessionID, userID, ok := parseReconfirmationRequest(ctx)
+	if !ok {
+		return
+	}
+	reconfirmation, err := c.repo.Get(ctx.Request.Context(), sessionID, userID)
+	if err != nil {
+		respondReconfirmationError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, reconfirmation)
+}
+
+// Respond обрабатывает POST /api/sessions/:id/reconfirmation - участник подтверждает, что придет
+// в новое время, или отказывается. При отказе он покидает сессию, а место занимает следующий из очереди.
+func (c *ReconfirmationController) Respond(ctx *gin.Context) {
+	sessionID, userID, ok := parseReconfirmationRequest(ctx)
+	if !ok {
+		return
+	}
+	var req models.ReconfirmationRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	requestContext := ctx.Request.Context()
+	existing, err := c.repo.Get(requestContext, sessionID, userID)
+	if err != nil {
+		respondReconfirmationError(ctx, err)
+		return
+	}
+	if existing.Status != models.ReconfirmationPending || !existing.Deadline.After(time.Now()) {
+		respondReconfirmationError(ctx, repositories.ErrReconfirmationClosed)
+		return
+	}
+
+	if *req.Attend {
+		reconfirmation, err := c.repo.Respond(requestContext, sessionID, userID, models.ReconfirmationConfirmed)
+		if err != nil {
+			respondReconfirmationError(ctx, err)
+			return
+		}
+		ctx.JSON(http.StatusOK, reconfirmation)
+		return
+	}
+
+	// Отказ и выход из сессии сохраняются вместе: место не освобождается без записанного ответа
+	reconfirmation, promoted, err := c.repo.Decline(requestContext, sessionID, userID)
+	if err != nil {
+		respondReconfirmationError(ctx, err)
+		return
+	}
+	if len(promoted) > 0 {
+		if session, err := c.sessionRepo.GetByID(requestContext, sessionID); err == nil {
+			notifyWaitlistPromotions(requestContext, c.notifRepo, session, promoted)
+		} else {
+			log.Printf("WARN: Failed to load session %s for promotion notifications: %v", sessionID, err)
+		}
+	}
+	ctx.JSON(http.StatusOK, reconfirmation)
+}
+
+// parseReconfirmationRequest извлекает ID сессии из :id и текущего пользователя.
+// При ошибке отвечает клиенту и возвращает false.
+func parseReconfirmationRequest(ctx *gin.Context) (uuid.UUID, uuid.UUID, bool) {
+	sessionID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid session ID format"})
+		return uuid.Nil, uuid.Nil, false
+	}
+	userID, ok := getUserIDFromContext(ctx)
+	if !ok {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized: User identification failed"})
+		return uuid.Nil, uuid.Nil, false
+	}
+	return sessionID, userID, true
+}
+
+// respondReconfirmationError преобразует ошибки репозитория подтверждений в HTTP-ответ
+func respondReconfirmationError(ctx *gin.Context, err error) {
+	switch {
+	case errors.Is(err, repositories.ErrReconfirmationNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, repositories.ErrReconfirmationClosed), errors.Is(err, repositories.ErrNotJoined):
+		ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, repositories.ErrSessionNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	default:
+		log.Printf("ERROR handling reconfirmation: %v", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to process reconfirmation"})
+	}
+}
@@ -0,0 +1,569 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/BuzzLyutic/Skill-sharing-web-platform/models"
+	"github.com/BuzzLyutic/Skill-sharing-web-platform/recurrence"
+	"github.com/BuzzLyutic/Skill-sharing-web-platform/repositories"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// errSeriesShiftNotRepresentable - перенос "этого и последующих" занятий нельзя выразить одним RRULE
+var errSeriesShiftNotRepresentable = errors.New("the new date cannot be applied to this and following occurrences; edit occurrences one by one")
+
+// SeriesController обрабатывает HTTP-запросы, связанные с повторяющимися сериями сессий
+type SeriesController struct {
+	repo        *repositories.SeriesRepository
+	sessionRepo *repositories.SessionRepository
+	userRepo    *repositories.UserRepository
+	notifRepo   *repositories.NotificationRepository
+	hostRepo    *repositories.HostRepository
+	categoryRepo *repositories.CategoryRepository
+}
+
+// NewSeriesController создает новый контроллер серий
+func NewSeriesController(
+	repo *repositories.SeriesRepository,
+	sessionRepo *repositories.SessionRepository,
+	userRepo *repositories.UserRepository,
+	notifRepo *repositories.NotificationRepository,
+	hostRepo *repositories.HostRepository,
+	categoryRepo *repositories.CategoryRepository,
+) *SeriesController {
+	return &SeriesController{repo: repo, sessionRepo: sessionRepo, userRepo: userRepo, notifRepo: notifRepo, hostRepo: hostRepo, categoryRepo: categoryRepo}
+}
+
+// Create обрабатывает POST /api/series
+func (c *SeriesController) Create(ctx *gin.Context) {
+	var req models.SessionSeriesRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		log.Printf("Failed to bind JSON for series creation: %v", err)
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	if err := req.Normalize(); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if !resolveCategory(ctx, c.categoryRepo, &req.Category) {
+		return
+	}
+
+	creatorID, ok := getUserIDFromContext(ctx)
+	if !ok {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized: User identification failed"})
+		return
+	}
+
+	rule, err := recurrence.Parse(req.RRule)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid recurrence rule", "details": err.Error()})
+		return
+	}
+	// Правило разворачивается в часовом поясе серии, чтобы время занятий не сдвигалось при переходе на летнее время
+	occurrences, err := rule.Expand(req.DateTime.In(req.TimeLocation()))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid recurrence rule", "details": err.Error()})
+		return
+	}
+	if len(occurrences) == 0 {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Recurrence rule produces no occurrences"})
+		return
+	}
+	if !req.AllowConflicts {
+		planned := make([]models.Session, 0, len(occurrences))
+		for _, start := range occurrences {
+			planned = append(planned, models.Session{DateTime: start, EndTime: start.Add(req.EndTime.Sub(req.DateTime))})
+		}
+		if !checkOccurrenceConflicts(ctx, c.sessionRepo, creatorID, planned) {
+			return
+		}
+	}
+
+	series, sessions, err := c.repo.Create(ctx.Request.Context(), creatorID, req, rule.String(), occurrences)
+	if err != nil {
+		log.Printf("ERROR creating series for user %s: %v", creatorID, err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create session series"})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, gin.H{"series": series, "occurrences": sessions})
+}
+
+// GetByID обрабатывает GET /api/series/:id
+func (c *SeriesController) GetByID(ctx *gin.Context) {
+	series, ok := c.loadSeries(ctx)
+	if !ok {
+		return
+	}
+
+	requestContext := ctx.Request.Context()
+	occurrences, err := c.repo.GetOccurrences(requestContext, series.ID, false)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve series occurrences"})
+		return
+	}
+	exdates, err := c.repo.GetExdates(requestContext, series.ID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve series occurrences"})
+		return
+	}
+
+	// Ссылки на встречи видны организаторам занятия, участники получают их у отдельных занятий
+	userID, _ := getUserIDFromContext(ctx)
+	roles, err := c.occurrenceRoles(requestContext, occurrences, userID)
+	if err != nil {
+		log.Printf("ERROR getting host roles of user %s in series %s: %v", userID, series.ID, err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check session permissions"})
+		return
+	}
+	// Черновики и занятия invite_only видны по тем же правилам, что и отдельные сессии (canViewSession)
+	total := len(occurrences)
+	occurrences, err = c.visibleOccurrences(requestContext, occurrences, roles, userID)
+	if err != nil {
+		log.Printf("ERROR checking visibility of series %s for user %s: %v", series.ID, userID, err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve series occurrences"})
+		return
+	}
+	if total > 0 && len(occurrences) == 0 && series.CreatorID != userID {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": repositories.ErrSeriesNotFound.Error()})
+		return
+	}
+	for i := range occurrences {
+		if roles[occurrences[i].ID] == "" {
+			occurrences[i].HideMeetingDetails()
+		}
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"series": series, "occurrences": occurrences, "exdates": exdates})
+}
+
+// Delete обрабатывает DELETE /api/series/:id - удаляет всю серию вместе с занятиями
+func (c *SeriesController) Delete(ctx *gin.Context) {
+	series, ok := c.loadSeries(ctx)
+	if !ok {
+		return
+	}
+
+	userID, ok := getUserIDFromContext(ctx)
+	if !ok {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized: User identification failed"})
+		return
+	}
+	// Удалить серию может только тот, кому роль разрешает удаление каждого ее занятия
+	requestContext := ctx.Request.Context()
+	occurrences, err := c.repo.GetOccurrences(requestContext, series.ID, false)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve series occurrences"})
+		return
+	}
+	roles, err := c.occurrenceRoles(requestContext, occurrences, userID)
+	if err != nil {
+		log.Printf("ERROR getting host roles of user %s in series %s: %v", userID, series.ID, err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check session permissions"})
+		return
+	}
+	allowed := series.CreatorID == userID // Серия без занятий
+	if len(occurrences) > 0 {
+		allowed = true
+		for _, occurrence := range occurrences {
+			allowed = allowed && roles[occurrence.ID].Can(models.PermissionDelete)
+		}
+	}
+	if !allowed {
+		log.Printf("WARN: User %s lacks %s permission for series %s", userID, models.PermissionDelete, series.ID)
+		ctx.JSON(http.StatusForbidden, gin.H{"error": fmt.Sprintf("Forbidden: You do not have %s permission for this series", models.PermissionDelete)})
+		return
+	}
+
+	if err := c.repo.Delete(ctx.Request.Context(), series.ID); err != nil {
+		respondSeriesError(ctx, series.ID, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"message": "Session series deleted successfully"})
+}
+
+// JoinSeries обрабатывает POST /api/series/:id/join - присоединяет пользователя ко всем
+// предстоящим занятиям серии. Для заполненных занятий пользователь попадает в очередь ожидания.
+func (c *SeriesController) JoinSeries(ctx *gin.Context) {
+	series, ok := c.loadSeries(ctx)
+	if !ok {
+		return
+	}
+
+	userID, ok := getUserIDFromContext(ctx)
+	if !ok {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized: User identification failed"})
+		return
+	}
+	if series.CreatorID == userID {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Creator cannot join their own series as a participant"})
+		return
+	}
+
+	requestContext := ctx.Request.Context()
+	occurrences, err := c.repo.GetOccurrences(requestContext, series.ID, true)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve series occurrences"})
+		return
+	}
+	if len(occurrences) == 0 {
+		ctx.JSON(http.StatusConflict, gin.H{"error": "Series has no upcoming occurrences"})
+		return
+	}
+
+	// Как и в JoinSession, организаторы (соорганизаторы, помощники) не присоединяются как участники
+	roles, err := c.occurrenceRoles(requestContext, occurrences, userID)
+	if err != nil {
+		log.Printf("ERROR getting host roles of user %s in series %s: %v", userID, series.ID, err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check session permissions"})
+		return
+	}
+	if len(roles) > 0 {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Session hosts cannot join their own series as participants"})
+		return
+	}
+
+	var joinReq models.JoinSeriesRequest
+	if ctx.Request.ContentLength > 0 {
+		if err := ctx.ShouldBindJSON(&joinReq); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+			return
+		}
+	}
+	// Как и в JoinSession, пересечения с другими сессиями пользователя можно игнорировать флагом allow_conflicts
+	allowConflicts := joinReq.AllowConflicts || ctx.Query("allow_conflicts") == "true"
+	if !allowConflicts && !checkOccurrenceConflicts(ctx, c.sessionRepo, userID, occurrences) {
+		return
+	}
+
+	results := make([]gin.H, 0, len(occurrences))
+	joined, requested := 0, 0
+	for _, occurrence := range occurrences {
+		entry := gin.H{"session_id": occurrence.ID, "date_time": occurrence.DateTime}
+		result, err := c.sessionRepo.JoinSession(requestContext, occurrence.ID, userID, models.JoinSessionRequest{})
+		switch {
+		case err == nil:
+			entry["status"] = result.Status
+			switch result.Status {
+			case models.ParticipationStatusWaitlisted:
+				entry["waitlist_position"] = result.WaitlistPosition
+			case models.ParticipationStatusJoined:
+				joined++
+			case models.ParticipationStatusPending:
+				requested++
+			}
+		case errors.Is(err, repositories.ErrAlreadyJoined):
+			entry["status"] = models.ParticipationStatusJoined
+		case errors.Is(err, repositories.ErrAlreadyWaitlisted):
+			entry["status"] = models.ParticipationStatusWaitlisted
+		case errors.Is(err, repositories.ErrJoinRequestPending):
+			entry["status"] = models.ParticipationStatusPending
+		case errors.Is(err, repositories.ErrSessionNotOpen):
+			continue // Занятие отменили между выборкой и присоединением
+		case errors.Is(err, repositories.ErrInviteRequired):
+			continue // К занятиям invite_only присоединяются по отдельным приглашениям
+		default:
+			log.Printf("ERROR joining occurrence %s of series %s for user %s: %v", occurrence.ID, series.ID, userID, err)
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to join series", "occurrences": results})
+			return
+		}
+		results = append(results, entry)
+	}
+
+	if joined > 0 || requested > 0 {
+		joiningUser, errUser := c.userRepo.GetByID(requestContext, userID)
+		if errUser != nil {
+			log.Printf("WARN: Failed to load user %s for series join notification: %v", userID, errUser)
+		} else {
+			newNotif := models.Notification{
+				UserID:      series.CreatorID,
+				Message:     fmt.Sprintf("User '%s' joined %d sessions of your series '%s'.", joiningUser.Name, joined, series.Title),
+				Type:        models.NotificationTypeNewParticipant,
+				RelatedID:   &series.ID,
+				RelatedType: "series",
+			}
+			if joined == 0 {
+				newNotif.Message = fmt.Sprintf("User '%s' requested to join %d sessions of your series '%s'.", joiningUser.Name, requested, series.Title)
+				newNotif.Type = models.NotificationTypeJoinRequest
+			}
+			if _, errNotif := c.notifRepo.CreateNotification(requestContext, newNotif); errNotif != nil {
+				log.Printf("WARN: Failed to create notification for new series participant: %v", errNotif)
+			}
+		}
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "Successfully joined the series", "occurrences": results})
+}
+
+// LeaveSeries обрабатывает POST /api/series/:id/leave - покидает все предстоящие занятия серии
+func (c *SeriesController) LeaveSeries(ctx *gin.Context) {
+	series, ok := c.loadSeries(ctx)
+	if !ok {
+		return
+	}
+
+	userID, ok := getUserIDFromContext(ctx)
+	if !ok {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized: User identification failed"})
+		return
+	}
+
+	requestContext := ctx.Request.Context()
+	occurrences, err := c.repo.GetOccurrences(requestContext, series.ID, true)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve series occurrences"})
+		return
+	}
+
+	left := 0
+	for i := range occurrences {
+		occurrence := &occurrences[i]
+		promoted, err := c.sessionRepo.LeaveSession(requestContext, occurrence.ID, userID)
+		if err != nil {
+			if errors.Is(err, repositories.ErrNotJoined) {
+				continue
+			}
+			log.Printf("ERROR leaving occurrence %s of series %s for user %s: %v", occurrence.ID, series.ID, userID, err)
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to leave series"})
+			return
+		}
+		left++
+		notifyWaitlistPromotions(requestContext, c.notifRepo, occurrence, promoted)
+	}
+
+	if left == 0 {
+		ctx.JSON(http.StatusConflict, gin.H{"error": repositories.ErrNotJoined.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"message": "Successfully left the series", "sessions_left": left})
+}
+
+// loadSeries разбирает :id и загружает серию, отвечая клиенту при ошибке
+func (c *SeriesController) loadSeries(ctx *gin.Context) (*models.SessionSeries, bool) {
+	seriesID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid series ID format"})
+		return nil, false
+	}
+	series, err := c.repo.GetByID(ctx.Request.Context(), seriesID)
+	if err != nil {
+		respondSeriesError(ctx, seriesID, err)
+		return nil, false
+	}
+	return series, true
+}
+
+// occurrenceRoles возвращает роли пользователя в занятиях серии; занятия без роли в результат не попадают
+func (c *SeriesController) occurrenceRoles(ctx context.Context, occurrences []models.Session, userID uuid.UUID) (map[uuid.UUID]models.HostRole, error) {
+	if userID == uuid.Nil || len(occurrences) == 0 {
+		return map[uuid.UUID]models.HostRole{}, nil
+	}
+	ids := make([]uuid.UUID, 0, len(occurrences))
+	for _, occurrence := range occurrences {
+		ids = append(ids, occurrence.ID)
+	}
+	return c.hostRepo.GetRoles(ctx, ids, userID)
+}
+
+// checkOccurrenceConflicts проверяет каждое занятие серии на пересечение с другими сессиями пользователя
+// и при пересечениях отвечает 409, как checkScheduleConflicts. Пересечения с занятиями этой же серии
+// не учитываются: повторное присоединение к серии не считается конфликтом. Еще не созданные занятия
+// передаются с uuid.Nil вместо ID.
+func checkOccurrenceConflicts(ctx *gin.Context, repo *repositories.SessionRepository, userID uuid.UUID, occurrences []models.Session) bool {
+	inSeries := make(map[uuid.UUID]bool, len(occurrences))
+	for _, occurrence := range occurrences {
+		inSeries[occurrence.ID] = true
+	}
+
+	clashes := []gin.H{}
+	for _, occurrence := range occurrences {
+		conflicts, err := repo.FindConflicts(ctx.Request.Context(), userID, occurrence.DateTime, occurrence.EndTime, occurrence.ID)
+		if err != nil {
+			log.Printf("ERROR checking schedule conflicts of user %s: %v", userID, err)
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check schedule conflicts"})
+			return false
+		}
+		others := conflicts[:0]
+		for _, conflict := range conflicts {
+			if !inSeries[conflict.SessionID] {
+				others = append(others, conflict)
+			}
+		}
+		if len(others) == 0 {
+			continue
+		}
+		clash := gin.H{"date_time": occurrence.DateTime, "conflicts": others}
+		if occurrence.ID != uuid.Nil {
+			clash["session_id"] = occurrence.ID
+		}
+		clashes = append(clashes, clash)
+	}
+	if len(clashes) > 0 {
+		ctx.JSON(http.StatusConflict, gin.H{
+			"error":     fmt.Sprintf("%d occurrences overlap with your other sessions; set allow_conflicts to proceed anyway", len(clashes)),
+			"conflicts": clashes,
+		})
+		return false
+	}
+	return true
+}
+
+// visibleOccurrences оставляет занятия, которые пользователь может видеть: черновики - организаторам
+// с правом просмотра черновиков, занятия invite_only - организаторам, участникам и очереди ожидания.
+// Код приглашения относится к отдельному занятию, поэтому здесь он не учитывается.
+func (c *SeriesController) visibleOccurrences(ctx context.Context, occurrences []models.Session, roles map[uuid.UUID]models.HostRole, userID uuid.UUID) ([]models.Session, error) {
+	visible := make([]models.Session, 0, len(occurrences))
+	for _, occurrence := range occurrences {
+		role := roles[occurrence.ID]
+		switch {
+		case occurrence.Status == models.SessionStatusDraft:
+			if !role.Can(models.PermissionViewDraft) {
+				continue
+			}
+		case occurrence.Visibility == models.VisibilityInviteOnly && role == "":
+			if userID == uuid.Nil {
+				continue
+			}
+			member, err := c.sessionRepo.IsParticipant(ctx, occurrence.ID, userID)
+			if err == nil && !member {
+				member, err = c.sessionRepo.IsWaitlisted(ctx, occurrence.ID, userID)
+			}
+			if err != nil {
+				return nil, err
+			}
+			if !member {
+				continue
+			}
+		}
+		visible = append(visible, occurrence)
+	}
+	return visible, nil
+}
+
+// parseSeriesScope читает ?scope= для изменения занятий серии. По умолчанию - только это занятие.
+func parseSeriesScope(ctx *gin.Context) (string, bool) {
+	scope := ctx.DefaultQuery("scope", models.SeriesScopeThis)
+	if scope != models.SeriesScopeThis && scope != models.SeriesScopeFollowing {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid scope, expected 'this' or 'following'"})
+		return "", false
+	}
+	return scope, true
+}
+
+// respondSeriesError преобразует ошибки операций над серией в HTTP-ответ
+func respondSeriesError(ctx *gin.Context, id uuid.UUID, err error) {
+	switch {
+	case errors.Is(err, repositories.ErrSeriesNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, errSeriesShiftNotRepresentable):
+		ctx.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+	case errors.Is(err, recurrence.ErrInvalidRule), errors.Is(err, recurrence.ErrTooManyOccurrences):
+		ctx.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+	default:
+		log.Printf("ERROR processing series operation for %s: %v", id, err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to process session series"})
+	}
+}
+
+// seriesSplitPoint разворачивает правило серии и возвращает исходные даты всех занятий
+// и количество занятий, которые идут раньше occurrence.
+func seriesSplitPoint(series *models.SessionSeries, occurrence *models.Session) (*recurrence.Rule, []time.Time, int, error) {
+	rule, err := recurrence.Parse(series.RRule)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+	all, err := rule.Expand(series.DTStart.In(series.TimeLocation()))
+	if err != nil {
+		return nil, nil, 0, err
+	}
+	before := 0
+	for _, t := range all {
+		if t.Before(*occurrence.OriginalStart) {
+			before++
+		}
+	}
+	return rule, all, before, nil
+}
+
+// updateSeriesFollowing применяет req к занятию occurrence и всем последующим занятиям серии.
+// Более ранние занятия остаются в исходной серии, правило которой обрезается через UNTIL.
+func updateSeriesFollowing(ctx context.Context, repo *repositories.SeriesRepository, occurrence *models.Session, actorID uuid.UUID, req models.SessionRequest) (*models.SessionSeries, error) {
+	series, err := repo.GetByID(ctx, *occurrence.SeriesID)
+	if err != nil {
+		return nil, err
+	}
+	rule, all, before, err := seriesSplitPoint(series, occurrence)
+	if err != nil {
+		return nil, err
+	}
+	if before >= len(all) {
+		return nil, errSeriesShiftNotRepresentable
+	}
+
+	from := *occurrence.OriginalStart
+	shift := req.DateTime.Sub(from)
+
+	// Новое правило: те же занятия, сдвинутые на shift (при переносе на другой день недели сдвигаем и BYDAY)
+	tailRule := rule.WithUntil(all[len(all)-1].Add(shift))
+	loc := req.TimeLocation()
+	if days := calendarDaysBetween(from, req.DateTime, loc); days != 0 && len(rule.ByDay) > 0 {
+		tailRule = tailRule.ShiftDays(days)
+	}
+	tailOccurrences, err := tailRule.Expand(req.DateTime.In(loc))
+	if err != nil {
+		return nil, err
+	}
+	expected := all[before:]
+	if len(tailOccurrences) != len(expected) {
+		return nil, errSeriesShiftNotRepresentable
+	}
+	for i, t := range expected {
+		if !tailOccurrences[i].Equal(t.Add(shift)) {
+			return nil, errSeriesShiftNotRepresentable
+		}
+	}
+
+	truncatedRule := ""
+	if before > 0 {
+		truncatedRule = rule.WithUntil(from.Add(-time.Second)).String()
+	}
+	tail := models.SessionSeries{
+		CreatorID:       series.CreatorID,
+		RRule:           tailRule.String(),
+		DTStart:         req.DateTime,
+		Title:           req.Title,
+		Description:     req.Description,
+		Category:        req.Category,
+		Location:        req.Location,
+		MaxParticipants: req.MaxParticipants,
+		DurationMinutes: req.DurationMinutes,
+		TimeZone:        req.TimeZone,
+		Tags:            req.Tags,
+		Latitude:        req.Latitude,
+		Longitude:       req.Longitude,
+		VenueAddress:    req.VenueAddress,
+		MeetingURL:       req.MeetingURL,
+		JoinInstructions: req.JoinInstructions,
+		Visibility:       req.Visibility,
+		RequiresApproval: *req.RequiresApproval, // req нормализован
+	}
+	return repo.UpdateFollowing(ctx, series.ID, actorID, from, truncatedRule, tail, shift)
+}
+
+// calendarDaysBetween возвращает разницу в календарных днях между a и b в часовом поясе loc
+func calendarDaysBetween(a, b time.Time, loc *time.Location) int {
+	ay, am, ad := a.In(loc).Date()
+	by, bm, bd := b.In(loc).Date()
+	dayA := time.Date(ay, am, ad, 0, 0, 0, 0, time.UTC)
+	dayB := time.Date(by, bm, bd, 0, 0, 0, 0, time.UTC)
+	return int(dayB.Sub(dayA).Hours() / 24)
+}
@@ -0,0 +1,109 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/BuzzLyutic/Skill-sharing-web-platform/models"
+	"github.com/BuzzLyutic/Skill-sharing-web-platform/repositories"
+)
+
+// sessionChangeSummary описывает изменения полей, важных для участников (время, место, вместимость),
+// в виде "поле: старое → новое". Время показывается в часовом поясе loc.
+// Пустой результат означает, что участников уведомлять не о чем.
+func sessionChangeSummary(before, after *models.Session, loc *time.Location) []string {
+	var changes []string
+	if !before.DateTime.Equal(after.DateTime) {
+		changes = append(changes, fmt.Sprintf("time: %s → %s",
+			before.DateTime.In(loc).Format(models.DisplayTimeFormat), after.DateTime.In(loc).Format(models.DisplayTimeFormat)))
+	}
+	if before.Location != after.Location {
+		changes = append(changes, fmt.Sprintf("location: %s → %s", before.Location, after.Location))
+	}
+	if before.MaxParticipants != after.MaxParticipants {
+		changes = append(changes, fmt.Sprintf("capacity: %d → %d", before.MaxParticipants, after.MaxParticipants))
+	}
+	return changes
+}
+
+// notifySessionUpdated уведомляет каждого участника о том, что именно изменилось в сессии.
+// Время в тексте показывается в часовом поясе получателя, номер версии позволяет найти
+// изменение в истории сессии. Ошибки только логируются: изменение уже сохранено в БД.
+func notifySessionUpdated(ctx context.Context, sessionRepo *repositories.SessionRepository, notifRepo *repositories.NotificationRepository, before, after *models.Session) {
+	if after.Version == before.Version || len(sessionChangeSummary(before, after, time.UTC)) == 0 {
+		return
+	}
+
+	participants, err := sessionRepo.GetParticipants(ctx, after.ID)
+	if err != nil {
+		log.Printf("WARN: Failed to get participants of session %s for update notification: %v", after.ID, err)
+		return
+	}
+	for i := range participants {
+		participant := &participants[i]
+		changes := sessionChangeSummary(before, after, after.TimeLocationFor(participant))
+		newNotif := models.Notification{
+			UserID:      participant.ID,
+			Message:     fmt.Sprintf("Session '%s' has been updated (version %d): %s.", after.Title, after.Version, strings.Join(changes, "; ")),
+			Type:        models.NotificationTypeSessionUpdate,
+			RelatedID:   &after.ID,
+			RelatedType: "session",
+		}
+		if _, errNotif := notifRepo.CreateNotification(ctx, newNotif); errNotif != nil {
+			log.Printf("WARN: Failed to notify user %s about update of session %s: %v", participant.ID, after.ID, errNotif)
+		}
+	}
+}
+
+// notifySeatChanges уведомляет участников, которых уменьшение вместимости перевело в очередь ожидания,
+// и пользователей, которых увеличение вместимости перевело из очереди в участники
+func notifySeatChanges(ctx context.Context, notifRepo *repositories.NotificationRepository, session *models.Session, changes *models.SeatChanges) {
+	if changes == nil {
+		return
+	}
+	for _, userID := range changes.Waitlisted {
+		newNotif := models.Notification{
+			UserID:      userID,
+			Message:     fmt.Sprintf("The number of seats in '%s' was reduced. You have been moved to the front of the waitlist.", session.Title),
+			Type:        models.NotificationTypeMovedToWaitlist,
+			RelatedID:   &session.ID,
+			RelatedType: "session",
+		}
+		if _, errNotif := notifRepo.CreateNotification(ctx, newNotif); errNotif != nil {
+			log.Printf("WARN: Failed to notify user %s about being moved to waitlist of session %s: %v", userID, session.ID, errNotif)
+		}
+	}
+	notifyWaitlistPromotions(ctx, notifRepo, session, changes.Promoted)
+}
+
+// requestReconfirmations просит участников перенесенной сессии подтвердить участие до крайнего срока
+// (см. models.ReconfirmDeadline). Ошибки только логируются: перенос уже сохранен в БД.
+func requestReconfirmations(ctx context.Context, reconfirmRepo *repositories.ReconfirmationRepository, notifRepo *repositories.NotificationRepository, before, after *models.Session) {
+	now := time.Now()
+	if after.Status != models.SessionStatusPublished || before.DateTime.Equal(after.DateTime) || !after.DateTime.After(now) {
+		return
+	}
+	deadline := models.ReconfirmDeadline(now, after.DateTime)
+	userIDs, err := reconfirmRepo.Request(ctx, after.ID, before.DateTime, deadline)
+	if err != nil {
+		log.Printf("WARN: Failed to request reconfirmations for session %s: %v", after.ID, err)
+		return
+	}
+	loc := after.TimeLocation()
+	for _, userID := range userIDs {
+		newNotif := models.Notification{
+			UserID: userID,
+			Message: fmt.Sprintf("Session '%s' has been moved to %s. Please confirm by %s whether you will attend.",
+				after.Title, after.DateTime.In(loc).Format(models.DisplayTimeFormat), deadline.In(loc).Format(models.DisplayTimeFormat)),
+			Type:        models.NotificationTypeReconfirm,
+			RelatedID:   &after.ID,
+			RelatedType: "session",
+		}
+		if _, errNotif := notifRepo.CreateNotification(ctx, newNotif); errNotif != nil {
+			log.Printf("WARN: Failed to ask user %s to reconfirm session %s: %v", userID, after.ID, errNotif)
+		}
+	}
+}
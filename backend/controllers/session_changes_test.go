@@ -0,0 +1,47 @@
+package controllers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/BuzzLyutic/Skill-sharing-web-platform/models"
+)
+
+func TestSessionChangeSummary(t *testing.T) {
+	start := time.Date(2025, 7, 1, 16, 0, 0, 0, time.UTC)
+	before := &models.Session{DateTime: start, Location: "Room 1", MaxParticipants: 10, Title: "Go"}
+
+	t.Run("no relevant changes", func(t *testing.T) {
+		after := *before
+		after.Title = "Go basics" // Название участникам не сообщается
+		if changes := sessionChangeSummary(before, &after, time.UTC); len(changes) != 0 {
+			t.Errorf("expected no changes, got %v", changes)
+		}
+	})
+
+	t.Run("time location and capacity", func(t *testing.T) {
+		after := *before
+		after.DateTime = start.Add(time.Hour)
+		after.Location = "Room 2"
+		after.MaxParticipants = 12
+
+		loc, err := time.LoadLocation("Europe/Berlin")
+		if err != nil {
+			t.Skipf("tzdata unavailable: %v", err)
+		}
+		changes := sessionChangeSummary(before, &after, loc)
+		want := []string{
+			"time: Jul 1, 2025 at 6:00 PM CEST → Jul 1, 2025 at 7:00 PM CEST",
+			"location: Room 1 → Room 2",
+			"capacity: 10 → 12",
+		}
+		if len(changes) != len(want) {
+			t.Fatalf("got %v, want %v", changes, want)
+		}
+		for i := range want {
+			if changes[i] != want[i] {
+				t.Errorf("change %d = %q, want %q", i, changes[i], want[i])
+			}
+		}
+	})
+}
@@ -1,11 +1,14 @@
 package controllers
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"io"
 	"log"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 	"github.com/arran4/golang-ical"
 	"github.com/BuzzLyutic/Skill-sharing-web-platform/middleware"
@@ -20,15 +23,28 @@ type SessionController struct {
 	repo *repositories.SessionRepository
 	userRepo *repositories.UserRepository
 	notifRepo *repositories.NotificationRepository
+	seriesRepo *repositories.SeriesRepository
+	hostRepo *repositories.HostRepository
+	inviteRepo *repositories.InviteRepository
+	reconfirmRepo *repositories.ReconfirmationRepository
+	categoryRepo *repositories.CategoryRepository
+	frontendURL string
 }
 
-// NewSessionController создает новый контроллер сеанса
+// NewSessionController создает новый контроллер сеанса.
+// frontendURL - адрес веб-клиента, из которого строятся ссылки на сессии в календарях.
 func NewSessionController(
 	repo *repositories.SessionRepository,
 	userRepo *repositories.UserRepository,
 	notifRepo *repositories.NotificationRepository,
+	seriesRepo *repositories.SeriesRepository,
+	hostRepo *repositories.HostRepository,
+	inviteRepo *repositories.InviteRepository,
+	reconfirmRepo *repositories.ReconfirmationRepository,
+	categoryRepo *repositories.CategoryRepository,
+	frontendURL string,
 	) *SessionController {
-	return &SessionController{repo: repo, notifRepo: notifRepo, userRepo: userRepo}
+	return &SessionController{repo: repo, notifRepo: notifRepo, userRepo: userRepo, seriesRepo: seriesRepo, hostRepo: hostRepo, inviteRepo: inviteRepo, reconfirmRepo: reconfirmRepo, categoryRepo: categoryRepo, frontendURL: strings.TrimSuffix(frontendURL, "/")}
 }
 
 // getUserIDFromContext извлекает User ID из контекста Gin.
@@ -67,16 +83,43 @@ func (c *SessionController) GetAll(ctx *gin.Context) {
     filters.Query = ctx.Query("q")
     filters.Category = ctx.Query("category")
     filters.Location = ctx.Query("location")
+    filters.Skill = ctx.Query("skill")
+    if minRatingStr := ctx.Query("min_rating"); minRatingStr != "" {
+        if r, err := strconv.ParseFloat(minRatingStr, 64); err == nil && r >= 0 && r <= 5 {
+            filters.MinRating = r
+        } else {
+            log.Printf("WARN: Invalid min_rating: %s", minRatingStr)
+        }
+    }
+    filters.Near = parseGeoFilter(ctx)
+    if availableStr := ctx.Query("available_slots"); availableStr != "" {
+        if available, err := strconv.ParseBool(availableStr); err == nil {
+            filters.AvailableSlots = available
+        } else {
+            log.Printf("WARN: Invalid available_slots: %s", availableStr)
+        }
+    }
 
+    // date_from/date_to: RFC 3339 или дата YYYY-MM-DD. Дата трактуется в часовом поясе ?tz=
+    // (по умолчанию UTC), а date_to включает весь указанный день.
+    filterLoc := time.UTC
+    if tz := ctx.Query("tz"); tz != "" {
+        loc, err := time.LoadLocation(tz)
+        if err != nil {
+            ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid tz, expected an IANA time zone name"})
+            return
+        }
+        filterLoc = loc
+    }
     if dateFromStr := ctx.Query("date_from"); dateFromStr != "" {
-        if t, err := time.Parse(time.RFC3339, dateFromStr); err == nil { // Ожидаем ISO 8601
+        if t, err := parseDateFilter(dateFromStr, filterLoc, false); err == nil {
             filters.DateFrom = &t
         } else {
              log.Printf("WARN: Invalid date_from format: %s", dateFromStr)
         }
     }
     if dateToStr := ctx.Query("date_to"); dateToStr != "" {
-        if t, err := time.Parse(time.RFC3339, dateToStr); err == nil {
+        if t, err := parseDateFilter(dateToStr, filterLoc, true); err == nil {
             filters.DateTo = &t
         } else {
             log.Printf("WARN: Invalid date_to format: %s", dateToStr)
@@ -99,6 +142,25 @@ func (c *SessionController) GetAll(ctx *gin.Context) {
         }
     }
 
+    // По умолчанию - курсоры по (date_time, id). Номер страницы используется, если он передан явно,
+    // а также при полнотекстовом поиске и поиске "рядом": порядок по релевантности или расстоянию
+    // не совпадает с порядком курсора.
+    if !useOffsetPagination(ctx) && filters.Query == "" && filters.Near == nil {
+        page, ok := parsePageRequest(ctx, filters.Limit)
+        if !ok {
+            return
+        }
+        sessions, info, err := c.repo.SearchSessionsPage(ctx.Request.Context(), filters, page)
+        if err != nil {
+            ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve sessions"})
+            return
+        }
+        meta := pageMeta(info)
+        meta["applied_filters"] = filters.Applied()
+        ctx.JSON(http.StatusOK, gin.H{"data": sessions, "meta": meta})
+        return
+    }
+
     sessions, totalCount, err := c.repo.SearchSessions(ctx.Request.Context(), filters)
     if err != nil {
         // Ошибка уже залогирована в репозитории
@@ -114,10 +176,51 @@ func (c *SessionController) GetAll(ctx *gin.Context) {
             "per_page":    filters.Limit,
             "current_page": (filters.Offset / filters.Limit) + 1,
             "total_pages":  (totalCount + filters.Limit - 1) / filters.Limit, // Округление вверх
+            "applied_filters": filters.Applied(), // Некорректные значения параметров игнорируются и сюда не попадают
         },
     })
 }
 
+// parseGeoFilter разбирает параметры поиска "рядом": lat, lng и radius_km (по умолчанию
+// models.DefaultSearchRadiusKm). Некорректные или неполные координаты игнорируются.
+func parseGeoFilter(ctx *gin.Context) *models.GeoFilter {
+    latStr, lngStr := ctx.Query("lat"), ctx.Query("lng")
+    if latStr == "" && lngStr == "" {
+        return nil
+    }
+    lat, errLat := strconv.ParseFloat(latStr, 64)
+    lng, errLng := strconv.ParseFloat(lngStr, 64)
+    if errLat != nil || errLng != nil || lat < -90 || lat > 90 || lng < -180 || lng > 180 {
+        log.Printf("WARN: Invalid lat/lng: %q/%q", latStr, lngStr)
+        return nil
+    }
+    near := &models.GeoFilter{Lat: lat, Lng: lng, RadiusKm: models.DefaultSearchRadiusKm}
+    if radiusStr := ctx.Query("radius_km"); radiusStr != "" {
+        if r, err := strconv.ParseFloat(radiusStr, 64); err == nil && r > 0 {
+            near.RadiusKm = min(r, models.MaxSearchRadiusKm)
+        } else {
+            log.Printf("WARN: Invalid radius_km: %s", radiusStr)
+        }
+    }
+    return near
+}
+
+// parseDateFilter разбирает границу интервала поиска: RFC 3339 или дату YYYY-MM-DD в часовом поясе loc.
+// Для endOfDay дата превращается в начало следующего дня, чтобы интервал включал весь день.
+func parseDateFilter(value string, loc *time.Location, endOfDay bool) (time.Time, error) {
+    if t, err := time.Parse(time.RFC3339, value); err == nil {
+        return t, nil
+    }
+    t, err := time.ParseInLocation(time.DateOnly, value, loc)
+    if err != nil {
+        return time.Time{}, err
+    }
+    if endOfDay {
+        t = t.AddDate(0, 0, 1)
+    }
+    return t, nil
+}
+
 
 // GetByID handles GET /sessions/:id
 func (c *SessionController) GetByID(ctx *gin.Context) {
@@ -140,9 +243,98 @@ func (c *SessionController) GetByID(ctx *gin.Context) {
 		return
 	}
 
+	userID, _ := getUserIDFromContext(ctx)
+	if !c.canViewSession(ctx.Request.Context(), session, userID, ctx.Query("invite")) {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": repositories.ErrSessionNotFound.Error()})
+		return
+	}
+
+	hosts, err := c.hostRepo.ListHosts(ctx.Request.Context(), session.ID, false)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve session hosts"})
+		return
+	}
+	session.Hosts = hosts
+	if !c.canSeeMeetingDetails(ctx.Request.Context(), session, userID) {
+		session.HideMeetingDetails()
+	}
+
 	ctx.JSON(http.StatusOK, session)
 }
 
+// canViewSession проверяет, может ли пользователь видеть сессию: черновики видны только организаторам,
+// сессии invite_only - организаторам, участникам (в том числе из очереди ожидания) и по действующему
+// приглашению inviteCode. Unlisted сессии видны всем, у кого есть ссылка.
+// Ошибки логируются и трактуются как отсутствие доступа.
+func (c *SessionController) canViewSession(ctx context.Context, session *models.Session, userID uuid.UUID, inviteCode string) bool {
+	if session.Status != models.SessionStatusDraft && session.Visibility != models.VisibilityInviteOnly {
+		return true
+	}
+	role, err := c.hostRepo.GetRole(ctx, session.ID, userID)
+	if err != nil {
+		log.Printf("ERROR getting host role of user %s in session %s: %v", userID, session.ID, err)
+		return false
+	}
+	if session.Status == models.SessionStatusDraft {
+		return role.Can(models.PermissionViewDraft)
+	}
+	if role != "" {
+		return true
+	}
+
+	if userID != uuid.Nil {
+		isParticipant, err := c.repo.IsParticipant(ctx, session.ID, userID)
+		if err == nil && !isParticipant {
+			isParticipant, err = c.repo.IsWaitlisted(ctx, session.ID, userID)
+		}
+		if err != nil {
+			log.Printf("ERROR checking participation of user %s in session %s: %v", userID, session.ID, err)
+			return false
+		}
+		if isParticipant {
+			return true
+		}
+	}
+	valid, err := c.inviteRepo.IsValid(ctx, session.ID, inviteCode)
+	if err != nil {
+		log.Printf("ERROR checking invite for session %s: %v", session.ID, err)
+		return false
+	}
+	return valid
+}
+
+// canSeeMeetingDetails проверяет, может ли пользователь видеть ссылку на встречу и инструкции
+// для входа: они доступны организаторам (создателю, соорганизаторам, помощникам) и подтвержденным
+// участникам, но не очереди ожидания. Ошибки логируются и трактуются как отсутствие доступа.
+func (c *SessionController) canSeeMeetingDetails(ctx context.Context, session *models.Session, userID uuid.UUID) bool {
+	if session.MeetingDetailsText() == "" || userID == uuid.Nil {
+		return false
+	}
+	allowed, err := isHostOrParticipant(ctx, c.repo, c.hostRepo, session.ID, userID)
+	if err != nil {
+		log.Printf("ERROR checking access of user %s to session %s: %v", userID, session.ID, err)
+		return false
+	}
+	return allowed
+}
+
+// requirePermission проверяет, что роль пользователя в сессии разрешает действие permission.
+// При отказе или ошибке отвечает клиенту и возвращает false.
+func (c *SessionController) requirePermission(ctx *gin.Context, session *models.Session, userID uuid.UUID, permission models.SessionPermission) bool {
+	role, err := c.hostRepo.GetRole(ctx.Request.Context(), session.ID, userID)
+	if err != nil {
+		log.Printf("ERROR getting host role of user %s in session %s: %v", userID, session.ID, err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check session permissions"})
+		return false
+	}
+	if !role.Can(permission) {
+		log.Printf("WARN: User %s (role %q) lacks %s permission for session %s", userID, role, permission, session.ID)
+		ctx.JSON(http.StatusForbidden, gin.H{"error": fmt.Sprintf("Forbidden: You do not have %s permission for this session", permission)})
+		return false
+	}
+	return true
+}
+
 // Create обрабатывает POST /sessions
 func (c *SessionController) Create(ctx *gin.Context) {
 	var req models.SessionRequest
@@ -152,6 +344,13 @@ func (c *SessionController) Create(ctx *gin.Context) {
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
 		return
 	}
+	if err := req.Normalize(); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if !resolveCategory(ctx, c.categoryRepo, &req.Category) {
+		return
+	}
 
 	creatorID, ok := getUserIDFromContext(ctx)
 	if !ok {
@@ -160,6 +359,10 @@ func (c *SessionController) Create(ctx *gin.Context) {
 		return
 	}
 
+	if !req.AllowConflicts && !checkScheduleConflicts(ctx, c.repo, creatorID, req.DateTime, *req.EndTime, uuid.Nil) {
+		return
+	}
+
 	// Передаем контекст запроса в репозиторий
 	session, err := c.repo.Create(ctx.Request.Context(), creatorID, req)
 	if err != nil {
@@ -205,19 +408,119 @@ func (c *SessionController) Update(ctx *gin.Context) {
 		return
 	}
 
-	if existingSession.CreatorID != userID {
-        log.Printf("WARN: User %s attempted to update session %s owned by %s", userID, sessionID, existingSession.CreatorID)
-		ctx.JSON(http.StatusForbidden, gin.H{"error": "Forbidden: You can only update your own sessions"})
+	if !c.requirePermission(ctx, existingSession, userID, models.PermissionEdit) {
+		return
+	}
+	if existingSession.Status.IsFinal() {
+		ctx.JSON(http.StatusConflict, gin.H{"error": fmt.Sprintf("Session is %s and can no longer be edited", existingSession.Status)})
 		return
 	}
 
+	// Не переданные длительность и часовой пояс сохраняются от текущей версии сессии
+	if req.EndTime == nil && req.DurationMinutes == 0 {
+		req.DurationMinutes = int(existingSession.EndTime.Sub(existingSession.DateTime) / time.Minute)
+	}
+	if req.TimeZone == "" {
+		req.TimeZone = existingSession.TimeZone
+	}
+	if req.Tags == nil {
+		req.Tags = existingSession.Tags
+	}
+	if req.SearchLanguage == "" {
+		req.SearchLanguage = existingSession.SearchLanguage
+	}
+	// Место без координат и адреса сохраняется, если его явно не удаляют флагом clear_location
+	locationGiven := req.Latitude != nil || req.Longitude != nil || req.VenueAddress != nil
+	if req.ClearLocation && locationGiven {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "clear_location cannot be combined with latitude, longitude or venue_address"})
+		return
+	}
+	if !req.ClearLocation && !locationGiven {
+		req.Latitude, req.Longitude, req.VenueAddress = existingSession.Latitude, existingSession.Longitude, existingSession.VenueAddress
+	}
+	if req.MeetingURL == nil {
+		req.MeetingURL = existingSession.MeetingURL
+	}
+	if req.Visibility == "" {
+		req.Visibility = existingSession.Visibility
+	}
+	if req.JoinInstructions == nil {
+		req.JoinInstructions = existingSession.JoinInstructions
+	}
+	if req.RequiresApproval == nil {
+		requiresApproval := existingSession.RequiresApproval
+		req.RequiresApproval = &requiresApproval
+	}
+	if err := req.Normalize(); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if !resolveCategory(ctx, c.categoryRepo, &req.Category) {
+		return
+	}
+
+	// Пересечения проверяются, только если время сессии меняется
+	timeChanged := !req.DateTime.Equal(existingSession.DateTime) || !req.EndTime.Equal(existingSession.EndTime)
+	if timeChanged && !req.AllowConflicts && !checkScheduleConflicts(ctx, c.repo, userID, req.DateTime, *req.EndTime, sessionID) {
+		return
+	}
+
+	// Для занятий серии ?scope=following применяет изменение к этому и всем последующим занятиям
+	scope, ok := parseSeriesScope(ctx)
+	if !ok {
+		return
+	}
+	if scope == models.SeriesScopeFollowing && existingSession.SeriesID != nil {
+		// Для серии вместимость нельзя уменьшить ниже числа участников ни одного из изменяемых занятий
+		if req.MaxParticipants < existingSession.MaxParticipants {
+			joined, err := c.seriesRepo.MaxParticipantsFrom(ctx.Request.Context(), *existingSession.SeriesID, *existingSession.OriginalStart)
+			if err != nil {
+				ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check series participants"})
+				return
+			}
+			if joined > req.MaxParticipants {
+				ctx.JSON(http.StatusConflict, gin.H{"error": fmt.Sprintf("%v: an occurrence of the series already has %d participants", repositories.ErrCapacityBelowParticipants, joined)})
+				return
+			}
+		}
+		// Занятия до изменения нужны, чтобы сообщить участникам, что именно поменялось
+		previous, err := c.seriesRepo.GetOccurrences(ctx.Request.Context(), *existingSession.SeriesID, false)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve series occurrences"})
+			return
+		}
+		series, err := updateSeriesFollowing(ctx.Request.Context(), c.seriesRepo, existingSession, userID, req)
+		if err != nil {
+			respondSeriesError(ctx, sessionID, err)
+			return
+		}
+		occurrences, err := c.seriesRepo.GetOccurrences(ctx.Request.Context(), series.ID, false)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Series updated, but failed to load occurrences"})
+			return
+		}
+		previousByID := make(map[uuid.UUID]*models.Session, len(previous))
+		for i := range previous {
+			previousByID[previous[i].ID] = &previous[i]
+		}
+		for i := range occurrences {
+			if before, ok := previousByID[occurrences[i].ID]; ok {
+				notifySessionUpdated(ctx.Request.Context(), c.repo, c.notifRepo, before, &occurrences[i])
+				requestReconfirmations(ctx.Request.Context(), c.reconfirmRepo, c.notifRepo, before, &occurrences[i])
+			}
+		}
+		ctx.JSON(http.StatusOK, gin.H{"series": series, "occurrences": occurrences})
+		return
+	}
 
 	// Передаем контекст запроса в репозиторий
-	updatedSession, err := c.repo.Update(ctx.Request.Context(), sessionID, req)
+	updatedSession, seatChanges, err := c.repo.Update(ctx.Request.Context(), sessionID, userID, req)
 	if err != nil {
 		if errors.Is(err, repositories.ErrSessionNotFound) {
 			// Это может случиться, если сессия была удалена между GetByID и Update (редко)
 			ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		} else if errors.Is(err, repositories.ErrCapacityBelowParticipants) {
+			ctx.JSON(http.StatusConflict, gin.H{"error": err.Error() + "; set capacity_policy to waitlist to move the latest joiners to the waitlist"})
 		} else {
             log.Printf("ERROR updating session %s by user %s: %v", sessionID, userID, err)
 			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update session"})
@@ -225,9 +528,32 @@ func (c *SessionController) Update(ctx *gin.Context) {
 		return
 	}
 
+	notifySessionUpdated(ctx.Request.Context(), c.repo, c.notifRepo, existingSession, updatedSession)
+	notifySeatChanges(ctx.Request.Context(), c.notifRepo, updatedSession, seatChanges)
+	requestReconfirmations(ctx.Request.Context(), c.reconfirmRepo, c.notifRepo, existingSession, updatedSession)
+
 	ctx.JSON(http.StatusOK, updatedSession)
 }
 
+// checkScheduleConflicts проверяет, что интервал [start, end) не пересекается с другими сессиями
+// пользователя. При пересечениях отвечает 409 со списком сессий и возвращает false.
+func checkScheduleConflicts(ctx *gin.Context, repo *repositories.SessionRepository, userID uuid.UUID, start, end time.Time, excludeID uuid.UUID) bool {
+	conflicts, err := repo.FindConflicts(ctx.Request.Context(), userID, start, end, excludeID)
+	if err != nil {
+		log.Printf("ERROR checking schedule conflicts of user %s: %v", userID, err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check schedule conflicts"})
+		return false
+	}
+	if len(conflicts) > 0 {
+		ctx.JSON(http.StatusConflict, gin.H{
+			"error":     fmt.Sprintf("Session overlaps with %d of your other sessions; set allow_conflicts to proceed anyway", len(conflicts)),
+			"conflicts": conflicts,
+		})
+		return false
+	}
+	return true
+}
+
 // Delete обрабатывает DELETE /sessions/:id
 func (c *SessionController) Delete(ctx *gin.Context) {
 	sessionIDStr := ctx.Param("id")
@@ -243,7 +569,7 @@ func (c *SessionController) Delete(ctx *gin.Context) {
 		return
 	}
 
-	// --- Авторизация: Проверяем, что пользователь - создатель сессии ---
+	// --- Авторизация: черновик удаляется, остальные сессии отменяются - права проверяются для нужного действия ---
 	existingSession, err := c.repo.GetByID(ctx.Request.Context(), sessionID)
 	if err != nil {
 		if errors.Is(err, repositories.ErrSessionNotFound) {
@@ -255,12 +581,37 @@ func (c *SessionController) Delete(ctx *gin.Context) {
 		return
 	}
 
-	if existingSession.CreatorID != userID {
-        log.Printf("WARN: User %s attempted to delete session %s owned by %s", userID, sessionID, existingSession.CreatorID)
-		ctx.JSON(http.StatusForbidden, gin.H{"error": "Forbidden: You can only delete your own sessions"})
+	permission := models.PermissionCancel
+	if existingSession.Status == models.SessionStatusDraft {
+		permission = models.PermissionDelete
+	}
+	if !c.requirePermission(ctx, existingSession, userID, permission) {
 		return
 	}
 
+	// Для занятий серии ?scope=following отменяет это и все последующие занятия
+	scope, ok := parseSeriesScope(ctx)
+	if !ok {
+		return
+	}
+	if scope == models.SeriesScopeFollowing && existingSession.SeriesID != nil {
+		cancelled, err := c.seriesRepo.CancelFollowing(ctx.Request.Context(), *existingSession.SeriesID, userID, *existingSession.OriginalStart, "")
+		if err != nil {
+			respondSeriesError(ctx, sessionID, err)
+			return
+		}
+		for i := range cancelled {
+			c.notifySessionCancelled(ctx.Request.Context(), &cancelled[i], nil)
+		}
+		ctx.JSON(http.StatusOK, gin.H{"message": "Session and all following occurrences cancelled successfully", "cancelled": len(cancelled)})
+		return
+	}
+
+	// Опубликованная сессия не удаляется, а отменяется: участники и отзывы сохраняются
+	if existingSession.Status != models.SessionStatusDraft {
+		c.cancelSession(ctx, sessionID, "")
+		return
+	}
 
 	// Передаем контекст запроса в репозиторий
 	err = c.repo.Delete(ctx.Request.Context(), sessionID)
@@ -278,6 +629,156 @@ func (c *SessionController) Delete(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, gin.H{"message": "Session deleted successfully"})
 }
 
+// Publish обрабатывает POST /api/sessions/:id/publish - публикует черновик
+func (c *SessionController) Publish(ctx *gin.Context) {
+	session, ok := c.loadSessionWithPermission(ctx, models.PermissionPublish)
+	if !ok {
+		return
+	}
+
+	userID, _ := getUserIDFromContext(ctx)
+	published, err := c.repo.Publish(ctx.Request.Context(), session.ID, userID)
+	if err != nil {
+		respondStatusTransitionError(ctx, session.ID, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, published)
+}
+
+// Clone обрабатывает POST /api/sessions/:id/clone - копия сессии (по умолчанию черновиком) без участников,
+// отзывов и соорганизаторов. Создатель копии - текущий пользователь.
+func (c *SessionController) Clone(ctx *gin.Context) {
+	var req models.CloneSessionRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) { // Тело запроса необязательно
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	session, ok := c.loadSessionWithPermission(ctx, models.PermissionEdit)
+	if !ok {
+		return
+	}
+	userID, _ := getUserIDFromContext(ctx)
+
+	cloneReq := session.CloneRequest(req.DateTime, !req.Publish)
+	if err := cloneReq.Normalize(); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	// Копия проверяется на пересечения так же, как новая сессия в Create
+	if !req.AllowConflicts && !checkScheduleConflicts(ctx, c.repo, userID, cloneReq.DateTime, *cloneReq.EndTime, uuid.Nil) {
+		return
+	}
+	clone, err := c.repo.Create(ctx.Request.Context(), userID, cloneReq)
+	if err != nil {
+		log.Printf("ERROR cloning session %s for user %s: %v", session.ID, userID, err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to clone session"})
+		return
+	}
+	ctx.JSON(http.StatusCreated, clone)
+}
+
+// Cancel обрабатывает POST /api/sessions/:id/cancel - отменяет сессию с необязательной причиной
+func (c *SessionController) Cancel(ctx *gin.Context) {
+	var req models.SessionCancelRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) { // Тело запроса необязательно
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	session, ok := c.loadSessionWithPermission(ctx, models.PermissionCancel)
+	if !ok {
+		return
+	}
+	c.cancelSession(ctx, session.ID, req.Reason)
+}
+
+// cancelSession отменяет сессию от имени текущего пользователя, уведомляет участников и отвечает клиенту
+func (c *SessionController) cancelSession(ctx *gin.Context, sessionID uuid.UUID, reason string) {
+	requestContext := ctx.Request.Context()
+	userID, _ := getUserIDFromContext(ctx)
+	session, waitlisted, err := c.repo.Cancel(requestContext, sessionID, userID, reason)
+	if err != nil {
+		respondStatusTransitionError(ctx, sessionID, err)
+		return
+	}
+	c.notifySessionCancelled(requestContext, session, waitlisted)
+	ctx.JSON(http.StatusOK, gin.H{"message": "Session cancelled successfully", "session": session})
+}
+
+// notifySessionCancelled уведомляет об отмене всех участников сессии и пользователей из очереди ожидания.
+// Ошибки только логируются: отмена уже зафиксирована в БД.
+func (c *SessionController) notifySessionCancelled(ctx context.Context, session *models.Session, waitlisted []uuid.UUID) {
+	recipients := append([]uuid.UUID{}, waitlisted...)
+	participants, err := c.repo.GetParticipants(ctx, session.ID)
+	if err != nil {
+		log.Printf("WARN: Failed to get participants of cancelled session %s: %v", session.ID, err)
+	}
+	for _, participant := range participants {
+		recipients = append(recipients, participant.ID)
+	}
+
+	message := fmt.Sprintf("The session '%s' has been cancelled.", session.Title)
+	if session.CancellationReason != nil {
+		message += " Reason: " + *session.CancellationReason
+	}
+	for _, userID := range recipients {
+		newNotif := models.Notification{
+			UserID:      userID,
+			Message:     message,
+			Type:        models.NotificationTypeSessionCancelled,
+			RelatedID:   &session.ID,
+			RelatedType: "session",
+		}
+		if _, errNotif := c.notifRepo.CreateNotification(ctx, newNotif); errNotif != nil {
+			log.Printf("WARN: Failed to notify user %s about cancellation of session %s: %v", userID, session.ID, errNotif)
+		}
+	}
+}
+
+// loadSessionWithPermission разбирает :id и загружает сессию, если роль текущего пользователя
+// разрешает действие permission. При ошибке отвечает клиенту.
+func (c *SessionController) loadSessionWithPermission(ctx *gin.Context, permission models.SessionPermission) (*models.Session, bool) {
+	sessionID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid session ID format"})
+		return nil, false
+	}
+	userID, ok := getUserIDFromContext(ctx)
+	if !ok {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized: User identification failed"})
+		return nil, false
+	}
+
+	session, err := c.repo.GetByID(ctx.Request.Context(), sessionID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrSessionNotFound) {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		} else {
+			log.Printf("ERROR getting session %s: %v", sessionID, err)
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve session"})
+		}
+		return nil, false
+	}
+	if !c.requirePermission(ctx, session, userID, permission) {
+		return nil, false
+	}
+	return session, true
+}
+
+// respondStatusTransitionError преобразует ошибки смены статуса сессии в HTTP-ответ
+func respondStatusTransitionError(ctx *gin.Context, sessionID uuid.UUID, err error) {
+	switch {
+	case errors.Is(err, repositories.ErrSessionNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, repositories.ErrInvalidStatusTransition):
+		ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		log.Printf("ERROR changing status of session %s: %v", sessionID, err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to change session status"})
+	}
+}
+
 // GetParticipants обрабатывает GET /sessions/:id/participants
 func (c *SessionController) GetParticipants(ctx *gin.Context) {
 	sessionIDStr := ctx.Param("id")
@@ -287,6 +788,22 @@ func (c *SessionController) GetParticipants(ctx *gin.Context) {
 		return
 	}
 
+	session, err := c.repo.GetByID(ctx.Request.Context(), sessionID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrSessionNotFound) {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		} else {
+			log.Printf("ERROR getting session %s for participants: %v", sessionID, err)
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve session"})
+		}
+		return
+	}
+	userID, _ := getUserIDFromContext(ctx)
+	if !c.canViewSession(ctx.Request.Context(), session, userID, ctx.Query("invite")) {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": repositories.ErrSessionNotFound.Error()})
+		return
+	}
+
 	// Передаем контекст запроса в репозиторий
 	participants, err := c.repo.GetParticipants(ctx.Request.Context(), sessionID)
 	if err != nil {
@@ -332,30 +849,52 @@ func (c *SessionController) JoinSession(ctx *gin.Context) {
 		return
     }
 
-    // Запрещаем создателю присоединяться к своей сессии как участнику 
-    if session.CreatorID == userID {
-         ctx.JSON(http.StatusBadRequest, gin.H{"error": "Creator cannot join their own session as a participant"})
-         return
+    // Код приглашения передается в теле запроса или, как в ссылке-приглашении, в ?invite=
+    var joinReq models.JoinSessionRequest
+    if ctx.Request.ContentLength > 0 {
+        if err := ctx.ShouldBindJSON(&joinReq); err != nil {
+            ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+            return
+        }
+    }
+    if joinReq.InviteCode == "" {
+        joinReq.InviteCode = ctx.Query("invite")
     }
 
-    // 2. Проверяем количество участников
-    currentParticipants, err := c.repo.CountParticipants(requestContext, sessionID)
+    if !c.canViewSession(requestContext, session, userID, joinReq.InviteCode) {
+        ctx.JSON(http.StatusNotFound, gin.H{"error": repositories.ErrSessionNotFound.Error()})
+        return
+    }
+
+    // Запрещаем организаторам (владельцу, соорганизаторам, помощникам) присоединяться как участникам
+    role, err := c.hostRepo.GetRole(requestContext, sessionID, userID)
     if err != nil {
-        log.Printf("ERROR counting participants for session %s before join: %v", sessionID, err)
-        ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check participant count"})
+        log.Printf("ERROR getting host role of user %s in session %s: %v", userID, sessionID, err)
+        ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check session permissions"})
         return
     }
+    if role != "" {
+         ctx.JSON(http.StatusBadRequest, gin.H{"error": "Session hosts cannot join their own session as participants"})
+         return
+    }
 
-    if currentParticipants >= session.MaxParticipants {
-        ctx.JSON(http.StatusConflict, gin.H{"error": repositories.ErrSessionFull.Error()}) // Используем ошибку репозитория
+    // Пересечение с другими сессиями пользователя можно игнорировать флагом allow_conflicts
+    allowConflicts := joinReq.AllowConflicts || ctx.Query("allow_conflicts") == "true"
+    if !allowConflicts && !checkScheduleConflicts(ctx, c.repo, userID, session.DateTime, session.EndTime, sessionID) {
         return
     }
 
-	// 3. Пытаемся присоединиться (репозиторий проверит, не присоединен ли уже)
-	err = c.repo.JoinSession(requestContext, sessionID, userID)
+	// 2. Присоединяемся или встаем в очередь. Проверка мест и вставка выполняются
+	// в репозитории одной транзакцией, поэтому отдельный CountParticipants не нужен.
+	result, err := c.repo.JoinSession(requestContext, sessionID, userID, joinReq)
 	if err != nil {
-        if errors.Is(err, repositories.ErrAlreadyJoined) {
+        if errors.Is(err, repositories.ErrInviteRequired) || errors.Is(err, repositories.ErrInvalidInvite) {
+            // Приглашение могли отозвать или исчерпать между проверкой и присоединением
+            ctx.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+        } else if errors.Is(err, repositories.ErrAlreadyJoined) || errors.Is(err, repositories.ErrAlreadyWaitlisted) || errors.Is(err, repositories.ErrJoinRequestPending) || errors.Is(err, repositories.ErrSessionNotOpen) {
             ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+        } else if errors.Is(err, repositories.ErrSessionNotFound) {
+            ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
         } else if errors.Is(err, repositories.ErrDatabase){ // Обрабатываем другие возможные ошибки БД
              log.Printf("ERROR joining session %s for user %s: %v", sessionID, userID, err)
              ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to join session due to a database issue"})
@@ -366,14 +905,32 @@ func (c *SessionController) JoinSession(ctx *gin.Context) {
         }
 		return
 	}
-	// Уведомление
-	session, errSession := c.repo.GetByID(requestContext, sessionID)
-	if errSession != nil { /* обработка ошибки */ }
 
-	joiningUser, errUser := c.userRepo.GetByID(requestContext, userID) // Нужен UserRepo в SessionController
-	if errUser != nil { /* обработка ошибки */ }
+	if result.Status == models.ParticipationStatusPending {
+		notifyJoinRequestReviewers(requestContext, c.hostRepo, c.userRepo, c.notifRepo, session, userID)
+		ctx.JSON(http.StatusAccepted, gin.H{
+			"message": "Your join request has been sent to the session hosts for approval",
+			"status":  result.Status,
+		})
+		return
+	}
+
+	if result.Status == models.ParticipationStatusWaitlisted {
+		ctx.JSON(http.StatusOK, gin.H{
+			"message":           "Session is full, you have been added to the waitlist",
+			"status":            result.Status,
+			"waitlist_position": result.WaitlistPosition,
+		})
+		return
+	}
 
-	if session != nil && joiningUser != nil && session.CreatorID != userID { // Не уведомляем, если создатель сам "присоединился"
+	// Уведомление
+	joiningUser, errUser := c.userRepo.GetByID(requestContext, userID)
+	if errUser != nil {
+		log.Printf("WARN: Failed to load user %s for join notification: %v", userID, errUser)
+	}
+
+	if joiningUser != nil && session.CreatorID != userID { // Не уведомляем, если создатель сам "присоединился"
     	notifMsg := fmt.Sprintf("User '%s' joined your session '%s'.", joiningUser.Name, session.Title)
     	newNotif := models.Notification {
         	UserID:      session.CreatorID,
@@ -387,7 +944,7 @@ func (c *SessionController) JoinSession(ctx *gin.Context) {
         log.Printf("WARN: Failed to create notification for new participant: %v", errNotif)
     }
 	}
-	ctx.JSON(http.StatusOK, gin.H{"message": "Successfully joined the session"})
+	ctx.JSON(http.StatusOK, gin.H{"message": "Successfully joined the session", "status": result.Status})
 }
 
 // LeaveSession обрабатывает POST /sessions/:id/leave
@@ -405,11 +962,15 @@ func (c *SessionController) LeaveSession(ctx *gin.Context) {
 		return
 	}
 
-	// Пытаемся покинуть сессию (репозиторий проверит, был ли пользователь участником)
-	err = c.repo.LeaveSession(ctx.Request.Context(), sessionID, userID)
+	requestContext := ctx.Request.Context()
+
+	// Пытаемся покинуть сессию (репозиторий проверит, был ли пользователь участником или в очереди)
+	promotedUserIDs, err := c.repo.LeaveSession(requestContext, sessionID, userID)
 	if err != nil {
         if errors.Is(err, repositories.ErrNotJoined) {
              ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()}) // Или StatusNotFound, если считать "не найден" более подходящим
+        } else if errors.Is(err, repositories.ErrSessionNotFound) {
+             ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
         } else if errors.Is(err, repositories.ErrDatabase){
             log.Printf("ERROR leaving session %s for user %s: %v", sessionID, userID, err)
             ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to leave session due to a database issue"})
@@ -420,9 +981,35 @@ func (c *SessionController) LeaveSession(ctx *gin.Context) {
 		return
 	}
 
+	if len(promotedUserIDs) > 0 {
+		session, errSession := c.repo.GetByID(requestContext, sessionID)
+		if errSession != nil {
+			log.Printf("WARN: Failed to load session %s for waitlist notifications: %v", sessionID, errSession)
+		} else {
+			notifyWaitlistPromotions(requestContext, c.notifRepo, session, promotedUserIDs)
+		}
+	}
+
 	ctx.JSON(http.StatusOK, gin.H{"message": "Successfully left the session"})
 }
 
+// notifyWaitlistPromotions уведомляет пользователей, переведенных из очереди ожидания в участники.
+// Ошибки только логируются: участие уже зафиксировано в БД.
+func notifyWaitlistPromotions(ctx context.Context, notifRepo *repositories.NotificationRepository, session *models.Session, userIDs []uuid.UUID) {
+	for _, promotedID := range userIDs {
+		newNotif := models.Notification{
+			UserID:      promotedID,
+			Message:     fmt.Sprintf("A seat opened up in '%s'. You have been moved from the waitlist to participants.", session.Title),
+			Type:        models.NotificationTypeWaitlistPromoted,
+			RelatedID:   &session.ID,
+			RelatedType: "session",
+		}
+		if _, errNotif := notifRepo.CreateNotification(ctx, newNotif); errNotif != nil {
+			log.Printf("WARN: Failed to notify user %s about waitlist promotion in session %s: %v", promotedID, session.ID, errNotif)
+		}
+	}
+}
+
 
 // GetRecommendedSessions обрабатывает GET /api/sessions/recommended
 func (c *SessionController) GetRecommendedSessions(ctx *gin.Context) {
@@ -442,6 +1029,9 @@ func (c *SessionController) GetRecommendedSessions(ctx *gin.Context) {
 			recommendedSessions, err = c.repo.GetGeneralRecommendedSessions(requestContext, limit)
 		} else {
 			var userSkills []string
+			if user, userErr := c.userRepo.GetByID(requestContext, userID); userErr == nil {
+				userSkills = user.Skills
+			}
 
 			recommendedSessions, err = c.repo.GetRecommendedSessionsForUser(requestContext, userID, userSkills, limit)
 		}
@@ -455,6 +1045,10 @@ func (c *SessionController) GetRecommendedSessions(ctx *gin.Context) {
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve recommended sessions"})
 		return
 	}
+	// Рекомендации - сессии, где пользователь еще не участвует: ссылки на встречи в них не показываются
+	for i := range recommendedSessions {
+		recommendedSessions[i].HideMeetingDetails()
+	}
 
 	ctx.JSON(http.StatusOK, recommendedSessions)
 }
@@ -480,7 +1074,8 @@ func (c *SessionController) ExportSessionICS(ctx *gin.Context) {
         }
         return
     }
-    if session == nil {
+    userID, _ := getUserIDFromContext(ctx)
+    if session == nil || !c.canViewSession(requestContext, session, userID, ctx.Query("invite")) {
          ctx.JSON(http.StatusNotFound, gin.H{"error": "Session not found"})
          return
     }
@@ -489,32 +1084,45 @@ func (c *SessionController) ExportSessionICS(ctx *gin.Context) {
     // --- Генерация ICS ---
     cal := ics.NewCalendar()
     cal.SetMethod(ics.MethodRequest) 
+    zones := newICSTimezones()
 
-    event := cal.AddEvent(session.ID.String()) // Уникальный ID для события
-    event.SetCreatedTime(session.CreatedAt)
-    event.SetDtStampTime(time.Now()) // Время создания ICS файла
-    event.SetModifiedAt(session.UpdatedAt)
-    event.SetStartAt(session.DateTime)
-
-    // Рассчитаем примерную длительность сессии (например, 1.5 часа)
-    assumedDuration := 90 * time.Minute
-    event.SetEndAt(session.DateTime.Add(assumedDuration))
+    // Организаторы попадают в ORGANIZER/ATTENDEE события
+    hosts, err := c.hostRepo.ListHosts(requestContext, session.ID, false)
+    if err != nil {
+        ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve session hosts"})
+        return
+    }
+    session.Hosts = hosts
+    // Ссылка на встречу попадает в календарь только организаторам и участникам
+    if !c.canSeeMeetingDetails(requestContext, session, userID) {
+        session.HideMeetingDetails()
+    }
 
-    event.SetSummary(session.Title)
-    event.SetLocation(session.Location)
-    event.SetDescription(session.Description)
-    event.SetURL("http://localhost:3000/sessions/" + session.ID.String()) // Ссылка на сессию на сайте
+    filename := session.Title
+    if session.SeriesID != nil {
+        // Занятие серии экспортируется как вся серия: RRULE/EXDATE + измененные занятия с RECURRENCE-ID
+        series, err := c.addSeriesEvents(requestContext, cal, *session.SeriesID, userID, zones)
+        if err != nil {
+            log.Printf("Error building ICS for series %s: %v", *session.SeriesID, err)
+            ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve series data"})
+            return
+        }
+        filename = series.Title
+    } else {
+        event := cal.AddEvent(session.ID.String()) // Уникальный ID для события
+        setSessionEventFields(event, session, zones, c.frontendURL)
+    }
+    zones.addTo(cal)
 
     // Установка заголовков для скачивания файла
     ctx.Header("Content-Type", "text/calendar; charset=utf-8")
-    ctx.Header("Content-Disposition", fmt.Sprintf("attachment; filename=\"session-%s.ics\"", session.Title))
+    ctx.Header("Content-Disposition", fmt.Sprintf("attachment; filename=\"session-%s.ics\"", filename))
 
     // Отправка ICS данных
     calString := cal.Serialize()
     ctx.String(http.StatusOK, calString)
 }
 
-
 // GetMySessions обрабатывает GET /api/sessions/my 
 func (c *SessionController) GetMySessions(ctx *gin.Context) {
     userID, ok := getUserIDFromContext(ctx) // Ensure this helper is robust
@@ -544,7 +1152,14 @@ func (c *SessionController) GetMySessions(ctx *gin.Context) {
         }
     }
 
-    filters.CreatorID = &userID 
+    filters.HostID = &userID
+    // Организатор видит свои сессии во всех статусах, включая черновики, и с любой видимостью
+    filters.Statuses = []models.SessionStatus{
+        models.SessionStatusDraft, models.SessionStatusPublished, models.SessionStatusCancelled, models.SessionStatusCompleted,
+    }
+    filters.Visibilities = []models.SessionVisibility{
+        models.VisibilityPublic, models.VisibilityUnlisted, models.VisibilityInviteOnly,
+    }
 
 	if excludePastQuery := ctx.Query("exclude_past"); excludePastQuery == "false" {
          filters.ExcludePast = false
@@ -552,6 +1167,20 @@ func (c *SessionController) GetMySessions(ctx *gin.Context) {
          filters.ExcludePast = true 
     }
 
+    if !useOffsetPagination(ctx) {
+        page, ok := parsePageRequest(ctx, filters.Limit)
+        if !ok {
+            return
+        }
+        sessions, info, err := c.repo.SearchSessionsPage(ctx.Request.Context(), filters, page)
+        if err != nil {
+            ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve your sessions"})
+            return
+        }
+        ctx.JSON(http.StatusOK, gin.H{"data": sessions, "meta": pageMeta(info)})
+        return
+    }
+
     sessions, totalCount, err := c.repo.SearchSessions(ctx.Request.Context(), filters)
     if err != nil {
         // Error already logged in repository
@@ -573,6 +1202,23 @@ func (c *SessionController) GetMySessions(ctx *gin.Context) {
 }
 
 
+// GetMyConflicts обрабатывает GET /api/users/me/conflicts - пары предстоящих сессий
+// пользователя (проводимых или посещаемых), которые пересекаются по времени
+func (c *SessionController) GetMyConflicts(ctx *gin.Context) {
+	userID, ok := getUserIDFromContext(ctx)
+	if !ok {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized: User identification failed"})
+		return
+	}
+	clashes, err := c.repo.ListClashes(ctx.Request.Context(), userID)
+	if err != nil {
+		log.Printf("ERROR listing schedule conflicts of user %s: %v", userID, err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve schedule conflicts"})
+		return
+	}
+	ctx.JSON(http.StatusOK, clashes)
+}
+
 // GetJoinedSessions обрабатывает GET /api/sessions/joined 
 func (c *SessionController) GetJoinedSessions(ctx *gin.Context) {
     userID, ok := getUserIDFromContext(ctx)
@@ -606,6 +1252,20 @@ func (c *SessionController) GetJoinedSessions(ctx *gin.Context) {
 
 	log.Printf("GetJoinedSessions: Applying filters: %+v for userID: %s", filters, userID.String())
 
+	if !useOffsetPagination(ctx) {
+		pageRequest, ok := parsePageRequest(ctx, filters.Limit)
+		if !ok {
+			return
+		}
+		sessions, info, err := c.repo.GetJoinedSessionsPage(ctx.Request.Context(), userID, filters, pageRequest)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve joined sessions"})
+			return
+		}
+		ctx.JSON(http.StatusOK, gin.H{"data": sessions, "meta": pageMeta(info)})
+		return
+	}
+
 	sessions, totalCount, err := c.repo.GetJoinedSessionsByUserID(ctx.Request.Context(), userID, filters)
 	if err != nil {
 		log.Printf("GetJoinedSessions: Error from GetJoinedSessionsByUserID for userID %s: %v", userID, err)
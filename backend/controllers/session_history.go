@@ -0,0 +1,147 @@
+package controllers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"slices"
+	"strconv"
+
+	"github.com/BuzzLyutic/Skill-sharing-web-platform/models"
+	"github.com/BuzzLyutic/Skill-sharing-web-platform/repositories"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// History обрабатывает GET /api/sessions/:id/history - версии сессии (кто, когда и что изменил),
+// начиная с последней. Доступно владельцу, соорганизаторам, модераторам и администраторам.
+func (c *SessionController) History(ctx *gin.Context) {
+	session, _, ok := c.loadSessionForHistory(ctx, models.PermissionViewHistory, models.RoleModerator, models.RoleAdmin)
+	if !ok {
+		return
+	}
+	versions, err := c.repo.ListVersions(ctx.Request.Context(), session.ID)
+	if err != nil {
+		log.Printf("ERROR listing versions of session %s: %v", session.ID, err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve session history"})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"current_version": session.Version, "versions": versions})
+}
+
+// RestoreVersion обрабатывает POST /api/sessions/:id/history/:version/restore - возвращает полям сессии
+// значения указанной версии. Восстановление записывается в историю как новая версия, участники
+// получают обычное уведомление об изменении. Вместимость меньше числа участников не восстанавливается (409).
+// Если восстанавливается другое время, пересечения проверяются как при Update; ?allow_conflicts=true их игнорирует.
+// Доступно владельцу и администраторам.
+func (c *SessionController) RestoreVersion(ctx *gin.Context) {
+	version, err := strconv.Atoi(ctx.Param("version"))
+	if err != nil || version < 1 {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid version number"})
+		return
+	}
+	session, userID, ok := c.loadSessionForHistory(ctx, models.PermissionRestoreVersion, models.RoleAdmin)
+	if !ok {
+		return
+	}
+	if session.Status.IsFinal() {
+		ctx.JSON(http.StatusConflict, gin.H{"error": "Session is " + string(session.Status) + " and can no longer be edited"})
+		return
+	}
+	if version == session.Version {
+		ctx.JSON(http.StatusConflict, gin.H{"error": "This version is already the current one"})
+		return
+	}
+
+	requestContext := ctx.Request.Context()
+	target, err := c.repo.GetVersion(requestContext, session.ID, version)
+	if err != nil {
+		if errors.Is(err, repositories.ErrSessionVersionNotFound) {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		} else {
+			log.Printf("ERROR getting version %d of session %s: %v", version, session.ID, err)
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve session version"})
+		}
+		return
+	}
+	req, err := target.RestoreRequest()
+	if err != nil {
+		log.Printf("ERROR decoding version %d of session %s: %v", version, session.ID, err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to read session version"})
+		return
+	}
+	if err := req.Normalize(); err != nil {
+		ctx.JSON(http.StatusUnprocessableEntity, gin.H{"error": "Version cannot be restored: " + err.Error()})
+		return
+	}
+	// Категорию из снимка могли переименовать или удалить после сохранения версии
+	category, err := c.categoryRepo.Resolve(requestContext, req.Category)
+	if err != nil {
+		if errors.Is(err, repositories.ErrUnknownCategory) {
+			ctx.JSON(http.StatusUnprocessableEntity, gin.H{"error": "Version cannot be restored: " + err.Error()})
+		} else {
+			log.Printf("ERROR resolving category of version %d of session %s: %v", version, session.ID, err)
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to restore session version"})
+		}
+		return
+	}
+	req.Category = category.Slug
+
+	timeChanged := !req.DateTime.Equal(session.DateTime) || !req.EndTime.Equal(session.EndTime)
+	if timeChanged && ctx.Query("allow_conflicts") != "true" &&
+		!checkScheduleConflicts(ctx, c.repo, userID, req.DateTime, *req.EndTime, session.ID) {
+		return
+	}
+
+	restored, seatChanges, err := c.repo.Update(requestContext, session.ID, userID, req)
+	if err != nil {
+		if errors.Is(err, repositories.ErrSessionNotFound) {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		} else if errors.Is(err, repositories.ErrCapacityBelowParticipants) {
+			ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		} else {
+			log.Printf("ERROR restoring version %d of session %s by user %s: %v", version, session.ID, userID, err)
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to restore session version"})
+		}
+		return
+	}
+
+	notifySessionUpdated(requestContext, c.repo, c.notifRepo, session, restored)
+	notifySeatChanges(requestContext, c.notifRepo, restored, seatChanges)
+	requestReconfirmations(requestContext, c.reconfirmRepo, c.notifRepo, session, restored)
+	ctx.JSON(http.StatusOK, restored)
+}
+
+// loadSessionForHistory загружает сессию из :id для работы с ее историей. Доступ есть у организаторов
+// с правом permission и у пользователей с одной из глобальных ролей staff.
+// При отказе или ошибке отвечает клиенту и возвращает false.
+func (c *SessionController) loadSessionForHistory(ctx *gin.Context, permission models.SessionPermission, staff ...models.Role) (*models.Session, uuid.UUID, bool) {
+	sessionID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid session ID format"})
+		return nil, uuid.Nil, false
+	}
+	userID, ok := getUserIDFromContext(ctx)
+	if !ok {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized: User identification failed"})
+		return nil, uuid.Nil, false
+	}
+
+	session, err := c.repo.GetByID(ctx.Request.Context(), sessionID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrSessionNotFound) {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		} else {
+			log.Printf("ERROR getting session %s: %v", sessionID, err)
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve session"})
+		}
+		return nil, uuid.Nil, false
+	}
+	if role, ok := getUserRoleFromContext(ctx); ok && slices.Contains(staff, role) {
+		return session, userID, true
+	}
+	if !c.requirePermission(ctx, session, userID, permission) {
+		return nil, uuid.Nil, false
+	}
+	return session, userID, true
+}
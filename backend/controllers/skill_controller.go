@@ -0,0 +1,152 @@
+package controllers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/BuzzLyutic/Skill-sharing-web-platform/models"
+	"github.com/BuzzLyutic/Skill-sharing-web-platform/repositories"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// defaultSkillSuggestions - сколько подсказок возвращается без ?limit=
+const defaultSkillSuggestions = 10
+
+// SkillController обрабатывает справочник навыков: автодополнение и обслуживание администратором
+type SkillController struct {
+	repo *repositories.SkillRepository
+}
+
+// NewSkillController создает новый контроллер навыков
+func NewSkillController(repo *repositories.SkillRepository) *SkillController {
+	return &SkillController{repo: repo}
+}
+
+// Suggest обрабатывает GET /api/skills/suggest?q=go&limit=10 - подсказки по началу названия
+// или синонима и по похожему написанию. Доступно без авторизации (нужно на форме регистрации).
+func (c *SkillController) Suggest(ctx *gin.Context) {
+	q := models.NormalizeSkillName(ctx.Query("q"))
+	if q == "" {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Query parameter 'q' is required"})
+		return
+	}
+	if len([]rune(q)) > models.MaxSkillLength {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": models.ErrSkillTooLong.Error()})
+		return
+	}
+	limit := defaultSkillSuggestions
+	if value := ctx.Query("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 || parsed > models.MaxSkillSuggests {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "limit must be between 1 and 25"})
+			return
+		}
+		limit = parsed
+	}
+
+	skills, err := c.repo.Suggest(ctx.Request.Context(), q, limit)
+	if err != nil {
+		log.Printf("ERROR suggesting skills for %q: %v", q, err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to suggest skills"})
+		return
+	}
+	ctx.JSON(http.StatusOK, skills)
+}
+
+// List обрабатывает GET /api/admin/skills?q= - справочник навыков с синонимами и числом пользователей
+func (c *SkillController) List(ctx *gin.Context) {
+	skills, err := c.repo.List(ctx.Request.Context(), ctx.Query("q"))
+	if err != nil {
+		log.Printf("ERROR listing skills: %v", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve skills"})
+		return
+	}
+	ctx.JSON(http.StatusOK, skills)
+}
+
+// Update обрабатывает PUT /api/admin/skills/:id - переименование навыка и замена его синонимов
+func (c *SkillController) Update(ctx *gin.Context) {
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid skill ID format"})
+		return
+	}
+	var req models.SkillRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+	if err := req.Normalize(); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	skill, err := c.repo.Update(ctx.Request.Context(), id, req)
+	if err != nil {
+		respondSkillError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, skill)
+}
+
+// Merge обрабатывает POST /api/admin/skills/merge - объединение дубликатов навыка у пользователей,
+// в тегах сессий, серий и шаблонов. Названия дубликатов становятся синонимами оставшегося навыка.
+func (c *SkillController) Merge(ctx *gin.Context) {
+	adminID, ok := getUserIDFromContext(ctx)
+	if !ok {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized: User identification failed"})
+		return
+	}
+	var req models.SkillMergeRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+	if err := req.Normalize(); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := c.repo.Merge(ctx.Request.Context(), adminID, req)
+	if err != nil {
+		respondSkillError(ctx, err)
+		return
+	}
+	log.Printf("INFO: admin %s merged skills %v into %s (%s)", adminID, req.SourceIDs, result.Skill.ID, result.Skill.Name)
+	ctx.JSON(http.StatusOK, result)
+}
+
+// respondSkillError отвечает на ошибку репозитория навыков
+func respondSkillError(ctx *gin.Context, err error) {
+	switch {
+	case errors.Is(err, repositories.ErrSkillNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, repositories.ErrSkillNameTaken), errors.Is(err, repositories.ErrSkillAliasTaken):
+		ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		log.Printf("ERROR changing skills: %v", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save skills"})
+	}
+}
+
+// canonicalizeSkills заменяет навыки из профиля каноническими названиями справочника
+// ("golang" и "GoLang" становятся "Go"), неизвестные навыки добавляются в справочник.
+// При ошибке отвечает клиенту и возвращает false.
+func canonicalizeSkills(ctx *gin.Context, repo repositories.SkillRepositoryInterface, skills *[]string) bool {
+	normalized, err := models.NormalizeSkills(*skills)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return false
+	}
+	canonical, err := repo.Canonicalize(ctx.Request.Context(), normalized)
+	if err != nil {
+		log.Printf("ERROR canonicalizing skills %v: %v", normalized, err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to process skills"})
+		return false
+	}
+	*skills = models.SkillNames(canonical)
+	return true
+}
@@ -0,0 +1,265 @@
+package controllers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/BuzzLyutic/Skill-sharing-web-platform/models"
+	"github.com/BuzzLyutic/Skill-sharing-web-platform/repositories"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// TemplateController обрабатывает шаблоны сессий: сохранение, доступ другим пользователям
+// и создание сессий по шаблону
+type TemplateController struct {
+	repo         *repositories.TemplateRepository
+	sessionRepo  *repositories.SessionRepository
+	userRepo     *repositories.UserRepository
+	categoryRepo *repositories.CategoryRepository
+}
+
+// NewTemplateController создает новый контроллер шаблонов
+func NewTemplateController(
+	repo *repositories.TemplateRepository,
+	sessionRepo *repositories.SessionRepository,
+	userRepo *repositories.UserRepository,
+	categoryRepo *repositories.CategoryRepository,
+) *TemplateController {
+	return &TemplateController{repo: repo, sessionRepo: sessionRepo, userRepo: userRepo, categoryRepo: categoryRepo}
+}
+
+// List обрабатывает GET /api/templates - собственные шаблоны и шаблоны, которыми поделились
+func (c *TemplateController) List(ctx *gin.Context) {
+	userID, ok := getUserIDFromContext(ctx)
+	if !ok {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized: User identification failed"})
+		return
+	}
+	templates, err := c.repo.ListForUser(ctx.Request.Context(), userID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve session templates"})
+		return
+	}
+	ctx.JSON(http.StatusOK, templates)
+}
+
+// Create обрабатывает POST /api/templates
+func (c *TemplateController) Create(ctx *gin.Context) {
+	req, ok := bindTemplateRequest(ctx)
+	if !ok || !resolveCategory(ctx, c.categoryRepo, &req.Category) {
+		return
+	}
+	userID, ok := getUserIDFromContext(ctx)
+	if !ok {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized: User identification failed"})
+		return
+	}
+
+	template, err := c.repo.Create(ctx.Request.Context(), userID, req)
+	if err != nil {
+		log.Printf("ERROR creating session template for user %s: %v", userID, err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create session template"})
+		return
+	}
+	ctx.JSON(http.StatusCreated, template)
+}
+
+// Get обрабатывает GET /api/templates/:id
+func (c *TemplateController) Get(ctx *gin.Context) {
+	template, _, ok := c.loadTemplate(ctx, false)
+	if !ok {
+		return
+	}
+	ctx.JSON(http.StatusOK, template)
+}
+
+// Update обрабатывает PUT /api/templates/:id (только владелец)
+func (c *TemplateController) Update(ctx *gin.Context) {
+	req, ok := bindTemplateRequest(ctx)
+	if !ok || !resolveCategory(ctx, c.categoryRepo, &req.Category) {
+		return
+	}
+	template, userID, ok := c.loadTemplate(ctx, true)
+	if !ok {
+		return
+	}
+
+	updated, err := c.repo.Update(ctx.Request.Context(), template.ID, userID, req)
+	if err != nil {
+		respondTemplateError(ctx, template.ID, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, updated)
+}
+
+// Delete обрабатывает DELETE /api/templates/:id (только владелец). Созданные по шаблону сессии не затрагиваются.
+func (c *TemplateController) Delete(ctx *gin.Context) {
+	template, userID, ok := c.loadTemplate(ctx, true)
+	if !ok {
+		return
+	}
+	if err := c.repo.Delete(ctx.Request.Context(), template.ID, userID); err != nil {
+		respondTemplateError(ctx, template.ID, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"message": "Session template deleted successfully"})
+}
+
+// ListShares обрабатывает GET /api/templates/:id/shares (только владелец)
+func (c *TemplateController) ListShares(ctx *gin.Context) {
+	template, _, ok := c.loadTemplate(ctx, true)
+	if !ok {
+		return
+	}
+	shares, err := c.repo.ListShares(ctx.Request.Context(), template.ID)
+	if err != nil {
+		respondTemplateError(ctx, template.ID, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, shares)
+}
+
+// Share обрабатывает POST /api/templates/:id/shares - владелец открывает шаблон другому пользователю
+func (c *TemplateController) Share(ctx *gin.Context) {
+	var req models.TemplateShareRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		log.Printf("Failed to bind JSON for template share: %v", err)
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+	template, userID, ok := c.loadTemplate(ctx, true)
+	if !ok {
+		return
+	}
+	if req.UserID == userID {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "You already own this template"})
+		return
+	}
+
+	requestContext := ctx.Request.Context()
+	if _, err := c.userRepo.GetByID(requestContext, req.UserID); err != nil {
+		if errors.Is(err, repositories.ErrUserNotFound) {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		} else {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve user"})
+		}
+		return
+	}
+	if err := c.repo.Share(requestContext, template.ID, userID, req.UserID); err != nil {
+		respondTemplateError(ctx, template.ID, err)
+		return
+	}
+	shares, err := c.repo.ListShares(requestContext, template.ID)
+	if err != nil {
+		respondTemplateError(ctx, template.ID, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, shares)
+}
+
+// Unshare обрабатывает DELETE /api/templates/:id/shares/:user_id
+func (c *TemplateController) Unshare(ctx *gin.Context) {
+	targetID, err := uuid.Parse(ctx.Param("user_id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID format"})
+		return
+	}
+	template, userID, ok := c.loadTemplate(ctx, true)
+	if !ok {
+		return
+	}
+	if err := c.repo.Unshare(ctx.Request.Context(), template.ID, userID, targetID); err != nil {
+		respondTemplateError(ctx, template.ID, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"message": "Template access revoked successfully"})
+}
+
+// CreateSession обрабатывает POST /api/templates/:id/sessions - новая сессия по шаблону.
+// Доступно владельцу и тем, кому шаблон открыт; создатель сессии - текущий пользователь.
+func (c *TemplateController) CreateSession(ctx *gin.Context) {
+	var req models.SessionFromTemplateRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		log.Printf("Failed to bind JSON for session from template: %v", err)
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+	template, userID, ok := c.loadTemplate(ctx, false)
+	if !ok {
+		return
+	}
+
+	sessionReq := template.SessionRequest(req.DateTime, req.Draft)
+	if err := sessionReq.Normalize(); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	// Категория шаблона могла быть переименована или удалена после его сохранения
+	if !resolveCategory(ctx, c.categoryRepo, &sessionReq.Category) {
+		return
+	}
+	if !req.AllowConflicts && !checkScheduleConflicts(ctx, c.sessionRepo, userID, sessionReq.DateTime, *sessionReq.EndTime, uuid.Nil) {
+		return
+	}
+	session, err := c.sessionRepo.Create(ctx.Request.Context(), userID, sessionReq)
+	if err != nil {
+		log.Printf("ERROR creating session from template %s for user %s: %v", template.ID, userID, err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create session"})
+		return
+	}
+	ctx.JSON(http.StatusCreated, session)
+}
+
+// loadTemplate разбирает :id и загружает доступный текущему пользователю шаблон.
+// ownerOnly - действие разрешено только владельцу. При ошибке отвечает клиенту.
+func (c *TemplateController) loadTemplate(ctx *gin.Context, ownerOnly bool) (*models.SessionTemplate, uuid.UUID, bool) {
+	templateID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid template ID format"})
+		return nil, uuid.Nil, false
+	}
+	userID, ok := getUserIDFromContext(ctx)
+	if !ok {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized: User identification failed"})
+		return nil, uuid.Nil, false
+	}
+
+	template, err := c.repo.GetForUser(ctx.Request.Context(), templateID, userID)
+	if err != nil {
+		respondTemplateError(ctx, templateID, err)
+		return nil, uuid.Nil, false
+	}
+	if ownerOnly && template.OwnerID != userID {
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "Forbidden: Only the template owner can do this"})
+		return nil, uuid.Nil, false
+	}
+	return template, userID, true
+}
+
+// bindTemplateRequest разбирает и нормализует тело запроса шаблона, отвечая 400 при ошибке
+func bindTemplateRequest(ctx *gin.Context) (models.SessionTemplateRequest, bool) {
+	var req models.SessionTemplateRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		log.Printf("Failed to bind JSON for session template: %v", err)
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return req, false
+	}
+	if err := req.Normalize(); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return req, false
+	}
+	return req, true
+}
+
+// respondTemplateError преобразует ошибки репозитория шаблонов в HTTP-ответ
+func respondTemplateError(ctx *gin.Context, templateID uuid.UUID, err error) {
+	switch {
+	case errors.Is(err, repositories.ErrTemplateNotFound), errors.Is(err, repositories.ErrTemplateShareNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	default:
+		log.Printf("ERROR processing session template %s: %v", templateID, err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to process session template"})
+	}
+}
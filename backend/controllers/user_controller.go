@@ -18,11 +18,12 @@ import (
 // UserController обрабатывает связанные с пользователем HTTP-запросы
 type UserController struct {
         repo repositories.UserRepositoryInterface
+        skillRepo repositories.SkillRepositoryInterface
 }
 
 // NewUserController создает новый пользовательский контроллер
-func NewUserController(repo repositories.UserRepositoryInterface) *UserController {
-        return &UserController{repo: repo}
+func NewUserController(repo repositories.UserRepositoryInterface, skillRepo repositories.SkillRepositoryInterface) *UserController {
+        return &UserController{repo: repo, skillRepo: skillRepo}
 }
 
 
@@ -129,6 +130,9 @@ func (c *UserController) Update(ctx *gin.Context) {
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body", "details": err.Error()})
 		return
 	}
+	if !canonicalizeSkills(ctx, c.skillRepo, &req.Skills) {
+		return
+	}
 
 	// Передаем контекст
 	updatedUser, err := c.repo.Update(ctx.Request.Context(), targetUserID, req)
@@ -262,6 +266,9 @@ func (c *UserController) UpdateMe(ctx *gin.Context) {
         ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body", "details": err.Error()})
         return
     }
+    if !canonicalizeSkills(ctx, c.skillRepo, &req.Skills) {
+        return
+    }
 
     updatedUser, err := c.repo.Update(ctx.Request.Context(), currentUserID, req)
     if err != nil {
@@ -21,6 +21,17 @@ import (
 
 var _ repositories.UserRepositoryInterface = (*mockUserRepository)(nil)
 
+// --- Mock SkillRepository: канонические названия без обращения к БД ---
+type mockSkillRepository struct{}
+
+func (mockSkillRepository) Canonicalize(ctx context.Context, names []string) ([]models.Skill, error) {
+	skills := make([]models.Skill, 0, len(names))
+	for _, name := range names {
+		skills = append(skills, models.Skill{ID: uuid.New(), Name: name})
+	}
+	return skills, nil
+}
+
 // --- Mock UserRepository ---
 type mockUserRepository struct {
     users          map[uuid.UUID]models.User
@@ -151,7 +162,7 @@ func FuzzUserControllerGetByID(f *testing.F) {
 			mockRepo.users[existingUUID] = models.User{ID: existingUUID, Name: "testuser", Email: "test@example.com", Role: string(models.RoleUser)}
 		}
 		
-		userController := NewUserController(mockRepo)
+		userController := NewUserController(mockRepo, mockSkillRepository{})
 
 		w := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(w)
@@ -187,7 +198,7 @@ func FuzzUserControllerUpdate(f *testing.F) {
 		gin.SetMode(gin.TestMode)
 
 		mockRepo := newMockUserRepository()
-		userController := NewUserController(mockRepo)
+		userController := NewUserController(mockRepo, mockSkillRepository{})
 
 		currentUserIDCtx, errParseCurrentID := uuid.Parse(currentUserIDStr)
 		
@@ -0,0 +1,13 @@
+-- Table: Session_Waitlist (очередь ожидания для заполненных сессий)
+CREATE TABLE session_waitlist (
+    session_id UUID NOT NULL REFERENCES sessions(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    queued_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT clock_timestamp(),
+    PRIMARY KEY (session_id, user_id)
+);
+
+-- Index for taking the first user from the queue
+CREATE INDEX idx_session_waitlist_queue ON session_waitlist(session_id, queued_at);
+
+-- Create index on user_id for faster joins
+CREATE INDEX idx_session_waitlist_user_id ON session_waitlist(user_id);
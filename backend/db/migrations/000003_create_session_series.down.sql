@@ -0,0 +1,7 @@
+ALTER TABLE sessions DROP CONSTRAINT IF EXISTS uq_sessions_series_occurrence;
+ALTER TABLE sessions
+    DROP COLUMN IF EXISTS is_override,
+    DROP COLUMN IF EXISTS original_start,
+    DROP COLUMN IF EXISTS series_id;
+DROP TABLE IF EXISTS session_series_exdates;
+DROP TABLE IF EXISTS session_series;
@@ -0,0 +1,39 @@
+-- Table: Session_Series (повторяющиеся сессии, заданные правилом RRULE)
+CREATE TABLE session_series (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    creator_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    rrule TEXT NOT NULL,
+    dtstart TIMESTAMP WITH TIME ZONE NOT NULL,
+    title VARCHAR(255) NOT NULL,
+    description TEXT,
+    category VARCHAR(50) NOT NULL,
+    location VARCHAR(255) NOT NULL,
+    max_participants INTEGER NOT NULL CHECK (max_participants > 0),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX idx_session_series_creator_id ON session_series(creator_id);
+
+CREATE TRIGGER trigger_update_session_series_timestamp
+BEFORE UPDATE ON session_series
+FOR EACH ROW
+EXECUTE FUNCTION update_timestamp();
+
+-- Отмененные занятия серии (EXDATE)
+CREATE TABLE session_series_exdates (
+    series_id UUID NOT NULL REFERENCES session_series(id) ON DELETE CASCADE,
+    original_start TIMESTAMP WITH TIME ZONE NOT NULL,
+    PRIMARY KEY (series_id, original_start) DEFERRABLE INITIALLY DEFERRED
+);
+
+-- Занятия серии хранятся в sessions. original_start - исходное время занятия по правилу (RECURRENCE-ID),
+-- is_override - занятие было изменено отдельно от серии.
+ALTER TABLE sessions
+    ADD COLUMN series_id UUID REFERENCES session_series(id) ON DELETE CASCADE,
+    ADD COLUMN original_start TIMESTAMP WITH TIME ZONE,
+    ADD COLUMN is_override BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- DEFERRABLE: при переносе "этого и последующих" занятия сдвигаются одним UPDATE
+ALTER TABLE sessions
+    ADD CONSTRAINT uq_sessions_series_occurrence UNIQUE (series_id, original_start) DEFERRABLE INITIALLY DEFERRED;
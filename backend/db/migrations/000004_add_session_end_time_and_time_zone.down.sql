@@ -0,0 +1,9 @@
+ALTER TABLE users DROP COLUMN IF EXISTS time_zone;
+ALTER TABLE session_series
+    DROP COLUMN IF EXISTS time_zone,
+    DROP COLUMN IF EXISTS duration_minutes;
+DROP INDEX IF EXISTS idx_sessions_end_time;
+ALTER TABLE sessions DROP CONSTRAINT IF EXISTS chk_sessions_end_after_start;
+ALTER TABLE sessions
+    DROP COLUMN IF EXISTS time_zone,
+    DROP COLUMN IF EXISTS end_time;
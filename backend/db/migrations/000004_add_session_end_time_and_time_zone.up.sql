@@ -0,0 +1,22 @@
+-- Время окончания и часовой пояс сессий (IANA, например "Europe/Moscow").
+-- Существующим сессиям проставляется прежняя условная длительность 90 минут.
+ALTER TABLE sessions
+    ADD COLUMN end_time TIMESTAMP WITH TIME ZONE,
+    ADD COLUMN time_zone VARCHAR(64) NOT NULL DEFAULT 'UTC';
+
+UPDATE sessions SET end_time = date_time + INTERVAL '90 minutes';
+
+ALTER TABLE sessions
+    ALTER COLUMN end_time SET NOT NULL,
+    ADD CONSTRAINT chk_sessions_end_after_start CHECK (end_time > date_time);
+
+CREATE INDEX idx_sessions_end_time ON sessions(end_time);
+
+-- Серии: длительность каждого занятия и часовой пояс, в котором разворачивается RRULE
+ALTER TABLE session_series
+    ADD COLUMN duration_minutes INTEGER NOT NULL DEFAULT 90 CHECK (duration_minutes > 0),
+    ADD COLUMN time_zone VARCHAR(64) NOT NULL DEFAULT 'UTC';
+
+-- Часовой пояс пользователя для отображения времени в уведомлениях (NULL - часовой пояс сессии)
+ALTER TABLE users
+    ADD COLUMN time_zone VARCHAR(64);
@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS idx_sessions_status_date_time;
+-- Отмененные сессии до этой миграции не существовали: удаляем их, как раньше делал DELETE
+DELETE FROM sessions WHERE status = 'cancelled';
+ALTER TABLE sessions
+    DROP COLUMN IF EXISTS cancellation_reason,
+    DROP COLUMN IF EXISTS cancelled_at,
+    DROP COLUMN IF EXISTS status;
@@ -0,0 +1,12 @@
+-- Жизненный цикл сессии: draft -> published -> cancelled | completed.
+-- Отмена больше не удаляет сессию, поэтому участники и отзывы сохраняются.
+ALTER TABLE sessions
+    ADD COLUMN status VARCHAR(20) NOT NULL DEFAULT 'published'
+        CHECK (status IN ('draft', 'published', 'cancelled', 'completed')),
+    ADD COLUMN cancelled_at TIMESTAMP WITH TIME ZONE,
+    ADD COLUMN cancellation_reason TEXT;
+
+-- Уже закончившиеся сессии сразу считаются завершенными
+UPDATE sessions SET status = 'completed' WHERE end_time <= NOW();
+
+CREATE INDEX idx_sessions_status_date_time ON sessions(status, date_time);
@@ -0,0 +1,3 @@
+DROP TRIGGER IF EXISTS trigger_add_session_owner ON sessions;
+DROP FUNCTION IF EXISTS add_session_owner();
+DROP TABLE IF EXISTS session_hosts;
@@ -0,0 +1,34 @@
+-- Table: Session_Hosts (организаторы сессии и их роли)
+CREATE TABLE session_hosts (
+    session_id UUID NOT NULL REFERENCES sessions(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    role VARCHAR(20) NOT NULL CHECK (role IN ('owner', 'co-host', 'assistant')),
+    status VARCHAR(20) NOT NULL DEFAULT 'invited' CHECK (status IN ('invited', 'accepted')),
+    invited_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    invited_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    accepted_at TIMESTAMP WITH TIME ZONE,
+    PRIMARY KEY (session_id, user_id)
+);
+
+CREATE INDEX idx_session_hosts_user_id ON session_hosts(user_id);
+-- У сессии ровно один владелец
+CREATE UNIQUE INDEX uq_session_hosts_owner ON session_hosts(session_id) WHERE role = 'owner';
+
+-- Владелец добавляется автоматически при любом способе создания сессии (в том числе занятий серии)
+CREATE OR REPLACE FUNCTION add_session_owner()
+RETURNS TRIGGER AS $$
+BEGIN
+    INSERT INTO session_hosts (session_id, user_id, role, status, invited_by, accepted_at)
+    VALUES (NEW.id, NEW.creator_id, 'owner', 'accepted', NEW.creator_id, NOW());
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER trigger_add_session_owner
+AFTER INSERT ON sessions
+FOR EACH ROW
+EXECUTE FUNCTION add_session_owner();
+
+-- Существующие сессии
+INSERT INTO session_hosts (session_id, user_id, role, status, invited_by, accepted_at)
+SELECT id, creator_id, 'owner', 'accepted', creator_id, created_at FROM sessions;
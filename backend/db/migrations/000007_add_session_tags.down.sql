@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_sessions_tags;
+
+ALTER TABLE session_series DROP COLUMN IF EXISTS tags;
+ALTER TABLE sessions DROP COLUMN IF EXISTS tags;
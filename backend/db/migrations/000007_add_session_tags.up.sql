@@ -0,0 +1,5 @@
+-- Теги сессии: по ним (и по навыкам создателя) работает фильтр поиска skill
+ALTER TABLE sessions ADD COLUMN tags VARCHAR(50)[] NOT NULL DEFAULT '{}';
+ALTER TABLE session_series ADD COLUMN tags VARCHAR(50)[] NOT NULL DEFAULT '{}';
+
+CREATE INDEX idx_sessions_tags ON sessions USING GIN(tags);
@@ -0,0 +1,11 @@
+-- Полнотекстовый поиск по сессиям. Конфигурация языка хранится в каждой сессии;
+-- 'russian' по умолчанию: она стеммит и русские слова, и слова латиницей (english_stem).
+ALTER TABLE sessions ADD COLUMN search_language REGCONFIG NOT NULL DEFAULT 'russian';
+
+-- Название весит больше описания (A > B)
+ALTER TABLE sessions ADD COLUMN search_vector TSVECTOR GENERATED ALWAYS AS (
+    setweight(to_tsvector(search_language, COALESCE(title, '')), 'A') ||
+    setweight(to_tsvector(search_language, COALESCE(description, '')), 'B')
+) STORED;
+
+CREATE INDEX idx_sessions_search_vector ON sessions USING GIN(search_vector);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_feedback_session_keyset;
+DROP INDEX IF EXISTS idx_notifications_unread_keyset;
+DROP INDEX IF EXISTS idx_sessions_date_time_id;
@@ -0,0 +1,4 @@
+-- Индексы для keyset-пагинации: сравнение строк (время, id) и сортировка по тем же колонкам
+CREATE INDEX idx_sessions_date_time_id ON sessions(date_time, id);
+CREATE INDEX idx_notifications_unread_keyset ON notifications(user_id, created_at DESC, id DESC) WHERE is_read = FALSE;
+CREATE INDEX idx_feedback_session_keyset ON feedback(session_id, created_at DESC, id DESC);
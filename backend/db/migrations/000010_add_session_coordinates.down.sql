@@ -0,0 +1,13 @@
+DROP INDEX IF EXISTS idx_sessions_latitude_longitude;
+
+ALTER TABLE session_series
+    DROP COLUMN IF EXISTS venue_address,
+    DROP COLUMN IF EXISTS longitude,
+    DROP COLUMN IF EXISTS latitude;
+
+ALTER TABLE sessions
+    DROP COLUMN IF EXISTS is_online,
+    DROP CONSTRAINT IF EXISTS sessions_coordinates_pair,
+    DROP COLUMN IF EXISTS venue_address,
+    DROP COLUMN IF EXISTS longitude,
+    DROP COLUMN IF EXISTS latitude;
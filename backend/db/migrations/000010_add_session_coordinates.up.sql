@@ -0,0 +1,20 @@
+-- Координаты и адрес площадки для очных сессий. Расстояние считается формулой гаверсинусов
+-- в обычном SQL, поэтому PostGIS не нужен.
+ALTER TABLE sessions
+    ADD COLUMN latitude DOUBLE PRECISION CHECK (latitude BETWEEN -90 AND 90),
+    ADD COLUMN longitude DOUBLE PRECISION CHECK (longitude BETWEEN -180 AND 180),
+    ADD COLUMN venue_address TEXT,
+    ADD CONSTRAINT sessions_coordinates_pair CHECK ((latitude IS NULL) = (longitude IS NULL));
+
+-- Онлайн-сессии определяются по полю location и в поиске "рядом" показываются отдельно
+ALTER TABLE sessions ADD COLUMN is_online BOOLEAN GENERATED ALWAYS AS (
+    lower(btrim(location)) IN ('online', 'онлайн')
+) STORED;
+
+ALTER TABLE session_series
+    ADD COLUMN latitude DOUBLE PRECISION,
+    ADD COLUMN longitude DOUBLE PRECISION,
+    ADD COLUMN venue_address TEXT;
+
+-- Предварительный отбор по широте перед точным расчетом расстояния
+CREATE INDEX idx_sessions_latitude_longitude ON sessions(latitude, longitude) WHERE latitude IS NOT NULL;
@@ -0,0 +1,7 @@
+ALTER TABLE session_series
+    DROP COLUMN IF EXISTS join_instructions,
+    DROP COLUMN IF EXISTS meeting_url;
+
+ALTER TABLE sessions
+    DROP COLUMN IF EXISTS join_instructions,
+    DROP COLUMN IF EXISTS meeting_url;
@@ -0,0 +1,9 @@
+-- Ссылка на онлайн-встречу и инструкции для входа. Показываются только организаторам
+-- и подтвержденным участникам, поэтому не попадают в списки и поиск сессий.
+ALTER TABLE sessions
+    ADD COLUMN meeting_url TEXT,
+    ADD COLUMN join_instructions TEXT;
+
+ALTER TABLE session_series
+    ADD COLUMN meeting_url TEXT,
+    ADD COLUMN join_instructions TEXT;
@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS session_invites;
+
+DROP INDEX IF EXISTS idx_sessions_visibility;
+
+ALTER TABLE session_series DROP COLUMN IF EXISTS visibility;
+
+ALTER TABLE sessions DROP COLUMN IF EXISTS visibility;
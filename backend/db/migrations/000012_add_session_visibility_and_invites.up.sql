@@ -0,0 +1,27 @@
+-- Видимость сессии: public - в списках и поиске, unlisted - только по прямой ссылке,
+-- invite_only - только по приглашению (коду) от организаторов
+ALTER TABLE sessions
+    ADD COLUMN visibility VARCHAR(20) NOT NULL DEFAULT 'public'
+        CHECK (visibility IN ('public', 'unlisted', 'invite_only'));
+
+ALTER TABLE session_series
+    ADD COLUMN visibility VARCHAR(20) NOT NULL DEFAULT 'public'
+        CHECK (visibility IN ('public', 'unlisted', 'invite_only'));
+
+CREATE INDEX idx_sessions_visibility ON sessions(visibility) WHERE visibility <> 'public';
+
+-- Приглашения в сессию. Отозванное, истекшее или исчерпанное приглашение не дает доступа,
+-- но остается в таблице, чтобы организаторы видели историю использования.
+CREATE TABLE session_invites (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    session_id UUID NOT NULL REFERENCES sessions(id) ON DELETE CASCADE,
+    code VARCHAR(32) NOT NULL UNIQUE,
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    expires_at TIMESTAMP WITH TIME ZONE,
+    max_uses INTEGER CHECK (max_uses > 0),
+    use_count INTEGER NOT NULL DEFAULT 0,
+    revoked_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_session_invites_session_id ON session_invites(session_id, created_at DESC);
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS session_join_requests;
+
+ALTER TABLE session_series DROP COLUMN IF EXISTS requires_approval;
+
+ALTER TABLE sessions DROP COLUMN IF EXISTS requires_approval;
@@ -0,0 +1,22 @@
+-- Сессии с предварительным одобрением: заявка на участие ждет решения организаторов
+-- и до одобрения не занимает место (max_participants)
+ALTER TABLE sessions ADD COLUMN requires_approval BOOLEAN NOT NULL DEFAULT FALSE;
+
+ALTER TABLE session_series ADD COLUMN requires_approval BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE TABLE session_join_requests (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    session_id UUID NOT NULL REFERENCES sessions(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending'
+        CHECK (status IN ('pending', 'approved', 'rejected', 'withdrawn')),
+    message TEXT,          -- Сообщение заявителя организаторам
+    decision_message TEXT, -- Комментарий организатора к решению
+    decided_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    decided_at TIMESTAMP WITH TIME ZONE
+);
+
+-- Одна активная заявка пользователя на сессию; решенные заявки остаются в истории
+CREATE UNIQUE INDEX idx_session_join_requests_pending ON session_join_requests(session_id, user_id) WHERE status = 'pending';
+CREATE INDEX idx_session_join_requests_session_id ON session_join_requests(session_id, created_at);
@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS idx_session_participants_attendance;
+
+ALTER TABLE session_participants
+    DROP COLUMN IF EXISTS attendance_marked_by,
+    DROP COLUMN IF EXISTS check_in_method,
+    DROP COLUMN IF EXISTS checked_in_at,
+    DROP COLUMN IF EXISTS attendance;
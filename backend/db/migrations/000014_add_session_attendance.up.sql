@@ -0,0 +1,12 @@
+-- Отметка присутствия участников: организатор отмечает вручную,
+-- либо участник вводит меняющийся код (или сканирует QR с ним)
+ALTER TABLE session_participants
+    ADD COLUMN attendance VARCHAR(20) NOT NULL DEFAULT 'unknown'
+        CHECK (attendance IN ('unknown', 'attended', 'no_show')),
+    ADD COLUMN checked_in_at TIMESTAMP WITH TIME ZONE,
+    ADD COLUMN check_in_method VARCHAR(10) CHECK (check_in_method IN ('host', 'code')),
+    ADD COLUMN attendance_marked_by UUID REFERENCES users(id) ON DELETE SET NULL;
+
+-- Index for per-user attendance rates
+CREATE INDEX idx_session_participants_attendance ON session_participants(user_id, attendance)
+    WHERE attendance <> 'unknown';
@@ -0,0 +1,13 @@
+-- Материалы сессий: метаданные файлов, сами файлы лежат во внешнем хранилище (диск или S3)
+CREATE TABLE session_attachments (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    session_id UUID NOT NULL REFERENCES sessions(id) ON DELETE CASCADE,
+    uploaded_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    file_name VARCHAR(255) NOT NULL,
+    content_type VARCHAR(255) NOT NULL,
+    size_bytes BIGINT NOT NULL CHECK (size_bytes > 0),
+    storage_key TEXT NOT NULL UNIQUE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_session_attachments_session_id ON session_attachments(session_id, created_at);
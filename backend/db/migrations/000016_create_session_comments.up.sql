@@ -0,0 +1,15 @@
+-- Обсуждение сессии: комментарии с ответами (parent_id), редактированием и мягким удалением
+CREATE TABLE session_comments (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    session_id UUID NOT NULL REFERENCES sessions(id) ON DELETE CASCADE,
+    parent_id UUID REFERENCES session_comments(id) ON DELETE CASCADE,
+    user_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    body TEXT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    edited_at TIMESTAMP WITH TIME ZONE,
+    deleted_at TIMESTAMP WITH TIME ZONE,
+    deleted_by UUID REFERENCES users(id) ON DELETE SET NULL -- Автор или модератор
+);
+
+CREATE INDEX idx_session_comments_session_id ON session_comments(session_id, created_at);
+CREATE INDEX idx_session_comments_parent_id ON session_comments(parent_id);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS session_question_votes;
+DROP TABLE IF EXISTS session_questions;
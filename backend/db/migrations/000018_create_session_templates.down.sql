@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS session_template_shares;
+DROP TABLE IF EXISTS session_templates;
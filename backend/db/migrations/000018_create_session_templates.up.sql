@@ -0,0 +1,36 @@
+-- Шаблоны сессий: сохраненные описание, место и вместимость, из которых создаются новые сессии
+CREATE TABLE session_templates (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    owner_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    title VARCHAR(255) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    category VARCHAR(50) NOT NULL,
+    duration_minutes INTEGER NOT NULL CHECK (duration_minutes BETWEEN 1 AND 1440),
+    time_zone VARCHAR(64) NOT NULL DEFAULT 'UTC',
+    location VARCHAR(255) NOT NULL,
+    latitude DOUBLE PRECISION CHECK (latitude BETWEEN -90 AND 90),
+    longitude DOUBLE PRECISION CHECK (longitude BETWEEN -180 AND 180),
+    venue_address TEXT,
+    max_participants INTEGER NOT NULL CHECK (max_participants > 0),
+    tags VARCHAR(50)[] NOT NULL DEFAULT '{}',
+    search_language VARCHAR(20) NOT NULL DEFAULT 'russian',
+    visibility VARCHAR(20) NOT NULL DEFAULT 'public'
+        CHECK (visibility IN ('public', 'unlisted', 'invite_only')),
+    requires_approval BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    CONSTRAINT session_templates_coordinates_pair CHECK ((latitude IS NULL) = (longitude IS NULL))
+);
+
+CREATE INDEX idx_session_templates_owner_id ON session_templates(owner_id);
+
+-- Пользователи, с которыми владелец поделился шаблоном: они могут создавать по нему сессии
+CREATE TABLE session_template_shares (
+    template_id UUID NOT NULL REFERENCES session_templates(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    shared_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (template_id, user_id)
+);
+
+CREATE INDEX idx_session_template_shares_user_id ON session_template_shares(user_id);
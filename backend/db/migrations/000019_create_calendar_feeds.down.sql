@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS calendar_feeds;
+
+DROP TRIGGER IF EXISTS trigger_bump_session_ics_sequence ON sessions;
+DROP FUNCTION IF EXISTS bump_session_ics_sequence();
+
+ALTER TABLE sessions DROP COLUMN IF EXISTS ics_sequence;
@@ -0,0 +1,30 @@
+-- Номер редакции сессии для календарей (SEQUENCE в iCalendar): увеличивается при изменении полей,
+-- которые попадают в событие, чтобы клиенты подписки применяли обновления и отмены
+ALTER TABLE sessions ADD COLUMN ics_sequence INTEGER NOT NULL DEFAULT 0;
+
+CREATE OR REPLACE FUNCTION bump_session_ics_sequence()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF (NEW.title, NEW.description, NEW.date_time, NEW.end_time, NEW.time_zone, NEW.location,
+        NEW.venue_address, NEW.meeting_url, NEW.join_instructions, NEW.status)
+       IS DISTINCT FROM
+       (OLD.title, OLD.description, OLD.date_time, OLD.end_time, OLD.time_zone, OLD.location,
+        OLD.venue_address, OLD.meeting_url, OLD.join_instructions, OLD.status) THEN
+        NEW.ics_sequence := OLD.ics_sequence + 1;
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER trigger_bump_session_ics_sequence
+BEFORE UPDATE ON sessions
+FOR EACH ROW
+EXECUTE FUNCTION bump_session_ics_sequence();
+
+-- Секретная ссылка на персональную подписку на календарь (webcal). Хранится только хэш токена;
+-- новая ссылка заменяет старую, удаление записи отзывает подписку.
+CREATE TABLE calendar_feeds (
+    user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    token_hash CHAR(64) NOT NULL UNIQUE, -- SHA-256 токена в hex
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
@@ -0,0 +1,30 @@
+DROP TRIGGER IF EXISTS trigger_record_session_version ON sessions;
+DROP FUNCTION IF EXISTS record_session_version();
+DROP TRIGGER IF EXISTS trigger_record_session_created ON sessions;
+DROP FUNCTION IF EXISTS record_session_created();
+
+DROP TABLE IF EXISTS session_versions;
+DROP FUNCTION IF EXISTS session_version_snapshot(sessions);
+
+UPDATE sessions SET version = GREATEST(version - 1, 0); -- Отменяем нумерацию версий с 1
+ALTER TABLE sessions ALTER COLUMN version SET DEFAULT 0;
+ALTER TABLE sessions RENAME COLUMN version TO ics_sequence;
+
+CREATE OR REPLACE FUNCTION bump_session_ics_sequence()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF (NEW.title, NEW.description, NEW.date_time, NEW.end_time, NEW.time_zone, NEW.location,
+        NEW.venue_address, NEW.meeting_url, NEW.join_instructions, NEW.status)
+       IS DISTINCT FROM
+       (OLD.title, OLD.description, OLD.date_time, OLD.end_time, OLD.time_zone, OLD.location,
+        OLD.venue_address, OLD.meeting_url, OLD.join_instructions, OLD.status) THEN
+        NEW.ics_sequence := OLD.ics_sequence + 1;
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER trigger_bump_session_ics_sequence
+BEFORE UPDATE ON sessions
+FOR EACH ROW
+EXECUTE FUNCTION bump_session_ics_sequence();
@@ -0,0 +1,94 @@
+-- История изменений сессий. Номер версии заменяет ics_sequence: он же служит SEQUENCE
+-- событий в календарях и растет при любом изменении отслеживаемых полей.
+DROP TRIGGER IF EXISTS trigger_bump_session_ics_sequence ON sessions;
+DROP FUNCTION IF EXISTS bump_session_ics_sequence();
+
+ALTER TABLE sessions RENAME COLUMN ics_sequence TO version;
+UPDATE sessions SET version = version + 1; -- Версии нумеруются с 1
+ALTER TABLE sessions ALTER COLUMN version SET DEFAULT 1;
+
+CREATE TABLE session_versions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    session_id UUID NOT NULL REFERENCES sessions(id) ON DELETE CASCADE,
+    version INTEGER NOT NULL,
+    changed_by UUID REFERENCES users(id) ON DELETE SET NULL, -- NULL: изменение системой (например, завершение по времени)
+    changes JSONB NOT NULL DEFAULT '{}', -- {"поле": {"old": ..., "new": ...}}
+    snapshot JSONB NOT NULL, -- Значения отслеживаемых полей после изменения, используются для восстановления
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    UNIQUE (session_id, version)
+);
+
+-- Отслеживаемые поля сессии. Ключи совпадают с полями JSON запроса на изменение сессии.
+CREATE OR REPLACE FUNCTION session_version_snapshot(s sessions)
+RETURNS JSONB AS $$
+    SELECT jsonb_build_object(
+        'title', s.title,
+        'description', s.description,
+        'category', s.category,
+        'date_time', s.date_time,
+        'end_time', s.end_time,
+        'time_zone', s.time_zone,
+        'location', s.location,
+        'latitude', s.latitude,
+        'longitude', s.longitude,
+        'venue_address', s.venue_address,
+        'meeting_url', s.meeting_url,
+        'join_instructions', s.join_instructions,
+        'max_participants', s.max_participants,
+        'tags', s.tags,
+        'search_language', s.search_language,
+        'visibility', s.visibility,
+        'requires_approval', s.requires_approval,
+        'status', s.status,
+        'cancellation_reason', s.cancellation_reason
+    )
+$$ LANGUAGE sql STABLE;
+
+-- Текущее состояние существующих сессий становится их первой записанной версией
+INSERT INTO session_versions (session_id, version, snapshot, created_at)
+SELECT s.id, s.version, session_version_snapshot(s), s.updated_at FROM sessions s;
+
+-- Новая сессия получает версию 1, автор - создатель
+CREATE OR REPLACE FUNCTION record_session_created()
+RETURNS TRIGGER AS $$
+BEGIN
+    INSERT INTO session_versions (session_id, version, changed_by, snapshot)
+    VALUES (NEW.id, NEW.version, NEW.creator_id, session_version_snapshot(NEW));
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER trigger_record_session_created
+AFTER INSERT ON sessions
+FOR EACH ROW
+EXECUTE FUNCTION record_session_created();
+
+-- Изменение отслеживаемых полей увеличивает версию и записывает разницу. Автор изменения
+-- передается приложением через SET LOCAL skillshare.actor_id в той же транзакции.
+CREATE OR REPLACE FUNCTION record_session_version()
+RETURNS TRIGGER AS $$
+DECLARE
+    new_snapshot JSONB := session_version_snapshot(NEW);
+    diff JSONB;
+BEGIN
+    SELECT COALESCE(jsonb_object_agg(n.key, jsonb_build_object('old', o.value, 'new', n.value)), '{}'::jsonb)
+    INTO diff
+    FROM jsonb_each(new_snapshot) n
+    JOIN jsonb_each(session_version_snapshot(OLD)) o ON o.key = n.key
+    WHERE o.value IS DISTINCT FROM n.value;
+
+    IF diff = '{}'::jsonb THEN
+        RETURN NEW;
+    END IF;
+
+    NEW.version := OLD.version + 1;
+    INSERT INTO session_versions (session_id, version, changed_by, changes, snapshot)
+    VALUES (NEW.id, NEW.version, NULLIF(current_setting('skillshare.actor_id', true), '')::uuid, diff, new_snapshot);
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER trigger_record_session_version
+BEFORE UPDATE ON sessions
+FOR EACH ROW
+EXECUTE FUNCTION record_session_version();
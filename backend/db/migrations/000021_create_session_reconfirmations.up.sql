@@ -0,0 +1,16 @@
+-- Подтверждение участия после переноса сессии: участник до крайнего срока подтверждает,
+-- что придет в новое время, или отказывается (и освобождает место)
+CREATE TABLE session_reconfirmations (
+    session_id UUID NOT NULL REFERENCES sessions(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'confirmed', 'declined', 'expired')),
+    previous_date_time TIMESTAMP WITH TIME ZONE NOT NULL, -- Время начала до переноса
+    deadline TIMESTAMP WITH TIME ZONE NOT NULL,
+    requested_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    responded_at TIMESTAMP WITH TIME ZONE,
+    PRIMARY KEY (session_id, user_id)
+);
+
+-- Для фоновой задачи, закрывающей просроченные запросы
+CREATE INDEX idx_session_reconfirmations_pending ON session_reconfirmations(deadline) WHERE status = 'pending';
+CREATE INDEX idx_session_reconfirmations_user_id ON session_reconfirmations(user_id);
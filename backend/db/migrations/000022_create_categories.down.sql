@@ -0,0 +1,6 @@
+ALTER TABLE session_templates DROP CONSTRAINT IF EXISTS fk_session_templates_category;
+ALTER TABLE session_series DROP CONSTRAINT IF EXISTS fk_session_series_category;
+ALTER TABLE sessions DROP CONSTRAINT IF EXISTS fk_sessions_category;
+
+-- Значения category остаются slug'ами: исходный свободный текст не восстанавливается
+DROP TABLE IF EXISTS categories;
@@ -0,0 +1,119 @@
+-- Дерево категорий, управляемое администраторами. Сессии, серии и шаблоны ссылаются на категорию по slug.
+CREATE TABLE categories (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    parent_id UUID REFERENCES categories(id) ON DELETE RESTRICT,
+    slug VARCHAR(50) NOT NULL UNIQUE,
+    names JSONB NOT NULL DEFAULT '{}', -- Локализованные названия: {"en": "Programming", "ru": "Программирование"}
+    icon VARCHAR(100),
+    position INTEGER NOT NULL DEFAULT 0, -- Порядок среди категорий одного родителя
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    CHECK (parent_id IS NULL OR parent_id <> id)
+);
+
+CREATE INDEX idx_categories_parent_id ON categories(parent_id);
+
+INSERT INTO categories (slug, names, icon, position) VALUES
+    ('programming', '{"en": "Programming", "ru": "Программирование"}', 'code', 1),
+    ('design', '{"en": "Design", "ru": "Дизайн"}', 'palette', 2),
+    ('languages', '{"en": "Languages", "ru": "Языки"}', 'translate', 3),
+    ('music', '{"en": "Music", "ru": "Музыка"}', 'music', 4),
+    ('business', '{"en": "Business", "ru": "Бизнес"}', 'briefcase', 5),
+    ('science', '{"en": "Science", "ru": "Наука"}', 'flask', 6),
+    ('sports', '{"en": "Sports", "ru": "Спорт"}', 'dumbbell', 7),
+    ('cooking', '{"en": "Cooking", "ru": "Кулинария"}', 'chef-hat', 8),
+    ('other', '{"en": "Other", "ru": "Другое"}', 'dots', 100);
+
+INSERT INTO categories (parent_id, slug, names, icon, position)
+SELECT p.id, c.slug, c.names::jsonb, c.icon, c.position
+FROM (VALUES
+    ('programming', 'web-development', '{"en": "Web development", "ru": "Веб-разработка"}', 'globe', 1),
+    ('programming', 'data-science', '{"en": "Data science", "ru": "Анализ данных"}', 'chart', 2),
+    ('programming', 'mobile-development', '{"en": "Mobile development", "ru": "Мобильная разработка"}', 'smartphone', 3),
+    ('design', 'ui-ux', '{"en": "UI/UX", "ru": "UI/UX"}', 'layout', 1),
+    ('languages', 'english', '{"en": "English", "ru": "Английский"}', NULL, 1),
+    ('business', 'marketing', '{"en": "Marketing", "ru": "Маркетинг"}', 'megaphone', 1)
+) AS c(parent_slug, slug, names, icon, position)
+JOIN categories p ON p.slug = c.parent_slug;
+
+-- Перенос свободного текста на дерево: известные синонимы, совпадения со slug и названиями
+-- без учета регистра, остальные значения становятся новыми категориями верхнего уровня
+CREATE TEMPORARY TABLE category_synonyms (synonym TEXT PRIMARY KEY, slug TEXT NOT NULL) ON COMMIT DROP;
+INSERT INTO category_synonyms (synonym, slug) VALUES
+    ('coding', 'programming'), ('development', 'programming'), ('software', 'programming'), ('it', 'programming'),
+    ('web', 'web-development'), ('frontend', 'web-development'), ('backend', 'web-development'),
+    ('data', 'data-science'), ('machine learning', 'data-science'), ('ml', 'data-science'),
+    ('ux', 'ui-ux'), ('ui', 'ui-ux'), ('graphic design', 'design'),
+    ('language', 'languages'), ('foreign languages', 'languages'),
+    ('sport', 'sports'), ('fitness', 'sports'), ('food', 'cooking');
+
+CREATE TEMPORARY TABLE category_mapping (raw TEXT PRIMARY KEY, slug TEXT, is_new BOOLEAN NOT NULL DEFAULT FALSE) ON COMMIT DROP;
+INSERT INTO category_mapping (raw)
+SELECT category FROM sessions
+UNION SELECT category FROM session_series
+UNION SELECT category FROM session_templates;
+
+UPDATE category_mapping m SET slug = COALESCE(
+    (SELECT s.slug FROM category_synonyms s WHERE s.synonym = lower(trim(m.raw))),
+    (SELECT c.slug FROM categories c
+     WHERE lower(c.slug) = lower(trim(m.raw))
+        OR EXISTS (SELECT 1 FROM jsonb_each_text(c.names) n WHERE lower(n.value) = lower(trim(m.raw)))
+     ORDER BY c.position LIMIT 1)
+);
+
+-- Для оставшихся значений slug строится из текста; для нелатинских названий - из хэша
+UPDATE category_mapping SET is_new = TRUE, slug = CASE
+    WHEN trim(both '-' from regexp_replace(lower(trim(raw)), '[^a-z0-9]+', '-', 'g')) = '' THEN 'category-' || substr(md5(lower(trim(raw))), 1, 8)
+    ELSE left(trim(both '-' from regexp_replace(lower(trim(raw)), '[^a-z0-9]+', '-', 'g')), 50)
+END
+WHERE slug IS NULL;
+
+-- Разные значения с одинаковым построенным slug (например, "C++" и "C#") не объединяются:
+-- первое по алфавиту сохраняет slug, если он свободен, остальные получают суффикс из хэша.
+-- Объединяются только значения, отличающиеся регистром и пробелами по краям.
+CREATE TEMPORARY TABLE category_renames ON COMMIT DROP AS
+SELECT raw, slug AS base_slug, left(slug, 43) || '-' || substr(md5(lower(trim(raw))), 1, 6) AS slug
+FROM (
+    SELECT m.raw, m.slug,
+           dense_rank() OVER (PARTITION BY m.slug ORDER BY lower(trim(m.raw))) AS n,
+           EXISTS (SELECT 1 FROM categories c WHERE c.slug = m.slug) AS taken
+    FROM category_mapping m
+    WHERE m.is_new
+) ranked
+WHERE n > 1 OR taken;
+
+UPDATE category_mapping m SET slug = r.slug FROM category_renames r WHERE m.raw = r.raw;
+
+DO $$
+DECLARE
+    collision RECORD;
+BEGIN
+    FOR collision IN SELECT raw, base_slug, slug FROM category_renames ORDER BY raw LOOP
+        RAISE NOTICE 'category "%" collides with slug "%", created as "%"', collision.raw, collision.base_slug, collision.slug;
+    END LOOP;
+END $$;
+
+INSERT INTO categories (slug, names, position)
+SELECT DISTINCT ON (m.slug) m.slug, jsonb_build_object('en', trim(m.raw)), 50
+FROM category_mapping m
+WHERE m.is_new
+ORDER BY m.slug, m.raw;
+
+-- Перенос не считается правкой сессии: версии не создаются, но снимки в истории тоже переводятся на slug,
+-- чтобы восстановление старой версии не ссылалось на удаленное значение
+ALTER TABLE sessions DISABLE TRIGGER trigger_record_session_version;
+UPDATE sessions s SET category = m.slug FROM category_mapping m WHERE s.category = m.raw AND s.category <> m.slug;
+ALTER TABLE sessions ENABLE TRIGGER trigger_record_session_version;
+
+UPDATE session_series s SET category = m.slug FROM category_mapping m WHERE s.category = m.raw AND s.category <> m.slug;
+UPDATE session_templates t SET category = m.slug FROM category_mapping m WHERE t.category = m.raw AND t.category <> m.slug;
+UPDATE session_versions v SET snapshot = jsonb_set(v.snapshot, '{category}', to_jsonb(m.slug))
+FROM category_mapping m WHERE v.snapshot->>'category' = m.raw AND m.raw <> m.slug;
+
+-- Переименование slug распространяется на сессии; удалить используемую категорию нельзя
+ALTER TABLE sessions ADD CONSTRAINT fk_sessions_category
+    FOREIGN KEY (category) REFERENCES categories(slug) ON UPDATE CASCADE ON DELETE RESTRICT;
+ALTER TABLE session_series ADD CONSTRAINT fk_session_series_category
+    FOREIGN KEY (category) REFERENCES categories(slug) ON UPDATE CASCADE ON DELETE RESTRICT;
+ALTER TABLE session_templates ADD CONSTRAINT fk_session_templates_category
+    FOREIGN KEY (category) REFERENCES categories(slug) ON UPDATE CASCADE ON DELETE RESTRICT;
@@ -0,0 +1,7 @@
+-- users.skills остается с каноническими названиями: исходное написание не восстанавливается
+DROP TRIGGER IF EXISTS trigger_sync_user_skills ON users;
+DROP FUNCTION IF EXISTS sync_user_skills();
+
+DROP TABLE IF EXISTS user_skills;
+DROP TABLE IF EXISTS skill_aliases;
+DROP TABLE IF EXISTS skills;
@@ -0,0 +1,106 @@
+-- Справочник навыков с синонимами. users.skills хранит канонические названия (для отображения и поиска),
+-- user_skills - связи пользователей с навыками по ID и поддерживается триггером.
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+CREATE TABLE skills (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name VARCHAR(50) NOT NULL CHECK (btrim(name) <> ''),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX idx_skills_name_lower ON skills (lower(name));
+CREATE INDEX idx_skills_name_prefix ON skills (lower(name) text_pattern_ops);
+CREATE INDEX idx_skills_name_trgm ON skills USING GIN (lower(name) gin_trgm_ops);
+
+-- Синонимы хранятся в нижнем регистре; синоним принадлежит ровно одному навыку
+CREATE TABLE skill_aliases (
+    alias VARCHAR(50) PRIMARY KEY CHECK (alias = lower(alias) AND btrim(alias) <> ''),
+    skill_id UUID NOT NULL REFERENCES skills(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_skill_aliases_skill_id ON skill_aliases(skill_id);
+CREATE INDEX idx_skill_aliases_prefix ON skill_aliases (alias text_pattern_ops);
+CREATE INDEX idx_skill_aliases_trgm ON skill_aliases USING GIN (alias gin_trgm_ops);
+
+CREATE TABLE user_skills (
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    skill_id UUID NOT NULL REFERENCES skills(id) ON DELETE CASCADE,
+    PRIMARY KEY (user_id, skill_id)
+);
+
+CREATE INDEX idx_user_skills_skill_id ON user_skills(skill_id);
+
+INSERT INTO skills (name) VALUES
+    ('Go'), ('JavaScript'), ('TypeScript'), ('Python'), ('Java'), ('C++'), ('C#'), ('Rust'),
+    ('PostgreSQL'), ('Kubernetes'), ('Docker'), ('React'), ('Vue.js'), ('Node.js'),
+    ('Machine Learning'), ('UI/UX Design'), ('English');
+
+INSERT INTO skill_aliases (alias, skill_id)
+SELECT a.alias, s.id
+FROM (VALUES
+    ('golang', 'Go'), ('go lang', 'Go'),
+    ('js', 'JavaScript'), ('ecmascript', 'JavaScript'),
+    ('ts', 'TypeScript'),
+    ('py', 'Python'), ('python3', 'Python'),
+    ('cpp', 'C++'), ('csharp', 'C#'), ('c sharp', 'C#'),
+    ('postgres', 'PostgreSQL'), ('psql', 'PostgreSQL'),
+    ('k8s', 'Kubernetes'),
+    ('reactjs', 'React'), ('react.js', 'React'),
+    ('vue', 'Vue.js'), ('vuejs', 'Vue.js'),
+    ('node', 'Node.js'), ('nodejs', 'Node.js'),
+    ('ml', 'Machine Learning'),
+    ('ux', 'UI/UX Design'), ('ui', 'UI/UX Design'), ('ui/ux', 'UI/UX Design')
+) AS a(alias, name)
+JOIN skills s ON s.name = a.name;
+
+-- Связи пользователей с навыками пересчитываются при каждом изменении users.skills
+CREATE OR REPLACE FUNCTION sync_user_skills()
+RETURNS TRIGGER AS $$
+BEGIN
+    DELETE FROM user_skills WHERE user_id = NEW.id;
+    INSERT INTO user_skills (user_id, skill_id)
+    SELECT DISTINCT NEW.id, s.id
+    FROM unnest(NEW.skills) AS u(name)
+    JOIN skills s ON lower(s.name) = lower(btrim(u.name))
+    ON CONFLICT DO NOTHING;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER trigger_sync_user_skills
+AFTER INSERT OR UPDATE OF skills ON users
+FOR EACH ROW EXECUTE FUNCTION sync_user_skills();
+
+-- Перенос свободного текста: значения сравниваются без учета регистра и лишних пробелов,
+-- совпадения с названиями и синонимами заменяются каноническим названием,
+-- остальные становятся новыми навыками с самым частым написанием
+CREATE TEMPORARY TABLE skill_mapping ON COMMIT DROP AS
+SELECT lower(regexp_replace(btrim(u.skill), '\s+', ' ', 'g')) AS key,
+       mode() WITHIN GROUP (ORDER BY regexp_replace(btrim(u.skill), '\s+', ' ', 'g')) AS spelling,
+       NULL::VARCHAR(50) AS name
+FROM users, unnest(users.skills) AS u(skill)
+WHERE btrim(u.skill) <> ''
+GROUP BY 1;
+
+UPDATE skill_mapping m SET name = COALESCE(
+    (SELECT s.name FROM skills s WHERE lower(s.name) = m.key),
+    (SELECT s.name FROM skill_aliases a JOIN skills s ON s.id = a.skill_id WHERE a.alias = m.key)
+);
+
+INSERT INTO skills (name)
+SELECT spelling FROM skill_mapping WHERE name IS NULL
+ON CONFLICT DO NOTHING;
+
+UPDATE skill_mapping SET name = spelling WHERE name IS NULL;
+
+-- Порядок навыков пользователя сохраняется, дубликаты после нормализации убираются
+UPDATE users u SET skills = ARRAY(
+    SELECT d.name FROM (
+        SELECT DISTINCT ON (m.name) m.name, x.ord
+        FROM unnest(u.skills) WITH ORDINALITY AS x(skill, ord)
+        JOIN skill_mapping m ON m.key = lower(regexp_replace(btrim(x.skill), '\s+', ' ', 'g'))
+        ORDER BY m.name, x.ord
+    ) d
+    ORDER BY d.ord
+)
+WHERE cardinality(u.skills) > 0;
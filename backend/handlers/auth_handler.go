@@ -21,12 +21,14 @@ import (
 // AuthHandler обрабатывает запросы аутентификации
 type AuthHandler struct {
     userRepo *repositories.UserRepository
+    skillRepo *repositories.SkillRepository
     jwtCfg   config.JWTConfig
 }
 
 func NewAuthHandler(db *sqlx.DB, jwtCfg config.JWTConfig) *AuthHandler {
 	return &AuthHandler{
 		userRepo: repositories.NewUserRepository(db),
+		skillRepo: repositories.NewSkillRepository(db),
 		jwtCfg:   jwtCfg,
 	}
 }
@@ -70,6 +72,20 @@ func (h *AuthHandler) Register(c *gin.Context) {
 		return
 	}
 
+	// Навыки приводятся к каноническим названиям справочника ("golang" -> "Go")
+	skills, err := models.NormalizeSkills(req.Skills)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	canonical, err := h.skillRepo.Canonicalize(requestContext, skills)
+	if err != nil {
+        log.Printf("Register: Failed to canonicalize skills for %s: %v", req.Email, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to process registration"})
+		return
+	}
+	req.Skills = models.SkillNames(canonical)
+
 	// Создаем пользователя
 	// Репозиторий вернет *models.User
 	user, err := h.userRepo.CreateUser(requestContext, req, string(passwordHash))
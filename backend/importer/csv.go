@@ -0,0 +1,217 @@
+package importer
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/BuzzLyutic/Skill-sharing-web-platform/models"
+)
+
+// CSVColumns - допустимые столбцы CSV. Первая строка файла - заголовок с названиями столбцов
+// (регистр и пробелы по краям не важны, порядок любой); столбцы соответствуют полям SessionRequest:
+//
+//	title             - название (обязательно)
+//	description       - описание
+//	category          - категория (по умолчанию - из параметров импорта)
+//	date_time         - начало (обязательно): RFC 3339 или "YYYY-MM-DD HH:MM" в поясе time_zone
+//	end_time          - окончание в том же формате; либо end_time, либо duration_minutes
+//	duration_minutes  - длительность в минутах (по умолчанию 90)
+//	time_zone         - часовой пояс IANA, например Europe/Moscow (по умолчанию - из параметров импорта)
+//	location          - место или "online" (по умолчанию - из параметров импорта)
+//	latitude, longitude, venue_address - координаты и адрес площадки
+//	meeting_url, join_instructions     - ссылка на встречу и инструкции для входа
+//	max_participants  - вместимость (по умолчанию - из параметров импорта)
+//	tags              - теги через ";" или ","
+//	search_language   - russian, english или simple
+//	visibility        - public, unlisted или invite_only
+//	requires_approval, draft - true/false (также yes/no, 1/0, да/нет)
+//
+// Разделитель полей - запятая или точка с запятой (определяется по заголовку).
+var CSVColumns = []string{
+	"title", "description", "category", "date_time", "end_time", "duration_minutes", "time_zone",
+	"location", "latitude", "longitude", "venue_address", "meeting_url", "join_instructions",
+	"max_participants", "tags", "search_language", "visibility", "requires_approval", "draft",
+}
+
+// ParseCSV разбирает CSV с расписанием. Ошибка возвращается только для файла целиком
+// (нет заголовка, неизвестные столбцы, слишком много строк); ошибки значений - в Row.Errors.
+func ParseCSV(data []byte, defaults Defaults) ([]Row, error) {
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf")) // BOM, который добавляет Excel
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.Comma = detectDelimiter(data)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, ErrEmptyFile
+		}
+		return nil, fmt.Errorf("invalid CSV header: %w", err)
+	}
+	columns, err := parseHeader(header)
+	if err != nil {
+		return nil, err
+	}
+
+	rows := []Row{}
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		line, _ := reader.FieldPos(0)
+		if err != nil {
+			var parseErr *csv.ParseError
+			if !errors.As(err, &parseErr) {
+				return nil, fmt.Errorf("failed to read CSV: %w", err)
+			}
+			row := newRow(parseErr.StartLine, defaults)
+			row.addError("malformed CSV line: %v", parseErr.Err)
+			rows = append(rows, row)
+		} else {
+			rows = append(rows, parseCSVRecord(line, columns, record, defaults))
+		}
+		if len(rows) > MaxRows {
+			return nil, ErrTooManyRows
+		}
+	}
+	if len(rows) == 0 {
+		return nil, ErrEmptyFile
+	}
+	return rows, nil
+}
+
+// detectDelimiter выбирает ';', если в первой строке точек с запятой больше, чем запятых
+func detectDelimiter(data []byte) rune {
+	firstLine := data
+	if i := bytes.IndexByte(data, '\n'); i >= 0 {
+		firstLine = data[:i]
+	}
+	if bytes.Count(firstLine, []byte(";")) > bytes.Count(firstLine, []byte(",")) {
+		return ';'
+	}
+	return ','
+}
+
+// parseHeader сопоставляет столбцы файла с CSVColumns
+func parseHeader(header []string) ([]string, error) {
+	known := make(map[string]bool, len(CSVColumns))
+	for _, column := range CSVColumns {
+		known[column] = true
+	}
+	columns := make([]string, len(header))
+	seen := map[string]bool{}
+	var unknown []string
+	for i, name := range header {
+		name = strings.ReplaceAll(strings.ToLower(strings.TrimSpace(name)), " ", "_")
+		switch {
+		case !known[name]:
+			unknown = append(unknown, name)
+		case seen[name]:
+			return nil, fmt.Errorf("duplicate CSV column %q", name)
+		}
+		seen[name] = true
+		columns[i] = name
+	}
+	if len(unknown) > 0 {
+		return nil, fmt.Errorf("unknown CSV columns: %s (allowed: %s)", strings.Join(unknown, ", "), strings.Join(CSVColumns, ", "))
+	}
+	for _, required := range []string{"title", "date_time"} {
+		if !seen[required] {
+			return nil, fmt.Errorf("CSV column %q is required", required)
+		}
+	}
+	return columns, nil
+}
+
+// parseCSVRecord заполняет запрос значениями строки. Пустые ячейки оставляют значения по умолчанию.
+func parseCSVRecord(line int, columns []string, record []string, defaults Defaults) Row {
+	row := newRow(line, defaults)
+	if len(record) > len(columns) {
+		row.addError("line has %d fields, header has %d", len(record), len(columns))
+		return row
+	}
+	values := make(map[string]string, len(columns))
+	for i, value := range record {
+		if value = strings.TrimSpace(value); value != "" {
+			values[columns[i]] = value
+		}
+	}
+
+	req := &row.Request
+	if tz, ok := values["time_zone"]; ok {
+		req.TimeZone = tz
+	}
+	loc, err := loadLocation(req.TimeZone)
+	if err != nil {
+		row.addError("time_zone: unknown time zone %q", req.TimeZone)
+		loc = nil
+	}
+
+	for _, column := range columns {
+		value, ok := values[column]
+		if !ok {
+			continue
+		}
+		var err error
+		switch column {
+		case "title":
+			req.Title = value
+		case "description":
+			req.Description = value
+		case "category":
+			req.Category = value
+		case "date_time", "end_time":
+			if loc == nil {
+				continue // Без часового пояса время не разобрать, ошибка уже записана
+			}
+			t, errTime := parseTime(value, loc)
+			if err = errTime; err == nil {
+				if column == "date_time" {
+					req.DateTime = t
+				} else {
+					req.EndTime = &t
+				}
+			}
+		case "duration_minutes":
+			req.DurationMinutes, err = strconv.Atoi(value)
+		case "location":
+			req.Location = value
+		case "latitude":
+			req.Latitude, err = parseFloat(value)
+		case "longitude":
+			req.Longitude, err = parseFloat(value)
+		case "venue_address":
+			req.VenueAddress = stringPtr(value)
+		case "meeting_url":
+			req.MeetingURL = stringPtr(value)
+		case "join_instructions":
+			req.JoinInstructions = stringPtr(value)
+		case "max_participants":
+			req.MaxParticipants, err = strconv.Atoi(value)
+		case "tags":
+			req.Tags = splitList(value)
+		case "search_language":
+			req.SearchLanguage = value
+		case "visibility":
+			req.Visibility = models.SessionVisibility(value)
+		case "requires_approval":
+			var requiresApproval bool
+			if requiresApproval, err = parseBool(value); err == nil {
+				req.RequiresApproval = &requiresApproval
+			}
+		case "draft":
+			req.Draft, err = parseBool(value)
+		}
+		if err != nil {
+			row.addError("%s: %v", column, err)
+		}
+	}
+	return row
+}
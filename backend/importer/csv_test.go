@@ -0,0 +1,72 @@
+package importer
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseCSV(t *testing.T) {
+	data := "\xef\xbb\xbfTitle;Date_Time;Time_Zone;Duration_Minutes;Tags;Requires_Approval;Max_Participants\n" +
+		"Go basics;2025-03-10 18:00;Europe/Moscow;120;go, backend;yes;\n" +
+		"Broken;tomorrow;Mars/Olympus;abc;;maybe;10\n"
+
+	rows, err := ParseCSV([]byte(data), Defaults{Category: "programming", Location: "online", MaxParticipants: 8, TimeZone: "UTC"})
+	if err != nil {
+		t.Fatalf("ParseCSV() error = %v", err)
+	}
+	if len(rows) != 2 {
+		t.Fatalf("got %d rows, want 2", len(rows))
+	}
+
+	first := rows[0]
+	if len(first.Errors) != 0 {
+		t.Fatalf("unexpected errors: %v", first.Errors)
+	}
+	moscow, _ := time.LoadLocation("Europe/Moscow")
+	req := first.Request
+	if first.Line != 2 || req.Title != "Go basics" || !req.DateTime.Equal(time.Date(2025, 3, 10, 18, 0, 0, 0, moscow)) {
+		t.Errorf("unexpected first row: line %d, %+v", first.Line, req)
+	}
+	if req.Category != "programming" || req.Location != "online" || req.MaxParticipants != 8 || req.DurationMinutes != 120 {
+		t.Errorf("defaults or values not applied: %+v", req)
+	}
+	if len(req.Tags) != 2 || req.RequiresApproval == nil || !*req.RequiresApproval {
+		t.Errorf("tags = %v, requires_approval = %v", req.Tags, req.RequiresApproval)
+	}
+
+	second := rows[1]
+	if second.Line != 3 || len(second.Errors) != 3 {
+		t.Fatalf("second row: line %d, errors %v, want 3 errors", second.Line, second.Errors)
+	}
+	if !strings.HasPrefix(second.Errors[0], "time_zone:") {
+		t.Errorf("first error = %q, want time_zone error", second.Errors[0])
+	}
+}
+
+func TestParseCSV_FileErrors(t *testing.T) {
+	cases := []struct {
+		name string
+		data string
+		want string
+	}{
+		{"empty", "", ErrEmptyFile.Error()},
+		{"header only", "title,date_time\n", ErrEmptyFile.Error()},
+		{"unknown column", "title,date_time,price\nA,2025-01-01 10:00,5\n", "unknown CSV columns: price"},
+		{"missing date", "title,category\nA,b\n", `CSV column "date_time" is required`},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := ParseCSV([]byte(tc.data), Defaults{})
+			if err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Errorf("ParseCSV() error = %v, want %q", err, tc.want)
+			}
+		})
+	}
+
+	many := "title,date_time\n" + strings.Repeat("A,2025-01-01 10:00\n", MaxRows+1)
+	if _, err := ParseCSV([]byte(many), Defaults{}); !errors.Is(err, ErrTooManyRows) {
+		t.Errorf("ParseCSV() error = %v, want %v", err, ErrTooManyRows)
+	}
+}
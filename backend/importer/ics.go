@@ -0,0 +1,189 @@
+package importer
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/arran4/golang-ical"
+)
+
+const (
+	icsUTCLayout   = "20060102T150405Z"
+	icsLocalLayout = "20060102T150405"
+)
+
+// ParseICS разбирает календарь iCalendar: каждое событие VEVENT становится строкой отчета.
+// Соответствие свойств полям SessionRequest:
+//
+//	SUMMARY -> title, DESCRIPTION -> description, LOCATION -> location, URL -> meeting_url,
+//	DTSTART -> date_time (TZID -> time_zone), DTEND или DURATION -> end_time,
+//	CATEGORIES -> category (первая) и tags (все), GEO -> latitude/longitude.
+//
+// Вместимости в iCalendar нет, она берется из Defaults. Повторяющиеся события (RRULE, RDATE,
+// RECURRENCE-ID) и события на весь день не импортируются, отмененные (STATUS:CANCELLED) пропускаются.
+func ParseICS(data []byte, defaults Defaults) ([]Row, error) {
+	cal, err := ics.ParseCalendar(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("invalid iCalendar file: %w", err)
+	}
+	events := cal.Events()
+	if len(events) == 0 {
+		return nil, ErrEmptyFile
+	}
+	if len(events) > MaxRows {
+		return nil, ErrTooManyRows
+	}
+
+	rows := make([]Row, 0, len(events))
+	for i, event := range events {
+		rows = append(rows, parseEvent(i+1, event, defaults))
+	}
+	return rows, nil
+}
+
+// parseEvent заполняет запрос свойствами события
+func parseEvent(number int, event *ics.VEvent, defaults Defaults) Row {
+	row := newRow(number, defaults)
+	req := &row.Request
+
+	text := func(property ics.ComponentProperty) string {
+		if p := event.GetProperty(property); p != nil {
+			return strings.TrimSpace(p.Value)
+		}
+		return ""
+	}
+	req.Title = text(ics.ComponentPropertySummary)
+	if strings.EqualFold(text(ics.ComponentPropertyStatus), "CANCELLED") {
+		row.Skipped = "event is cancelled"
+		return row
+	}
+	for _, property := range []ics.ComponentProperty{ics.ComponentPropertyRrule, ics.ComponentPropertyRdate, ics.ComponentPropertyRecurrenceId} {
+		if event.GetProperty(property) != nil {
+			row.addError("recurring events are not supported (%s); create a session series instead", property)
+			return row
+		}
+	}
+
+	req.Description = text(ics.ComponentPropertyDescription)
+	if location := text(ics.ComponentPropertyLocation); location != "" {
+		req.Location = location
+	}
+	req.MeetingURL = stringPtr(text(ics.ComponentPropertyUrl))
+
+	var categories []string
+	for _, p := range event.GetProperties(ics.ComponentPropertyCategories) {
+		for _, category := range strings.Split(p.Value, ",") {
+			if category = strings.TrimSpace(category); category != "" {
+				categories = append(categories, category)
+			}
+		}
+	}
+	if len(categories) > 0 {
+		req.Category = categories[0]
+		req.Tags = categories
+	}
+
+	if geo := text(ics.ComponentPropertyGeo); geo != "" {
+		parts := strings.Split(geo, ";")
+		var errLat, errLng error
+		if len(parts) == 2 {
+			req.Latitude, errLat = parseFloat(strings.TrimSpace(parts[0]))
+			req.Longitude, errLng = parseFloat(strings.TrimSpace(parts[1]))
+		}
+		if len(parts) != 2 || errLat != nil || errLng != nil {
+			req.Latitude, req.Longitude = nil, nil
+			row.addError("GEO: expected \"latitude;longitude\", got %q", geo)
+		}
+	}
+
+	start, tzid, err := eventTime(event.GetProperty(ics.ComponentPropertyDtStart), defaults.TimeZone)
+	if err != nil {
+		row.addError("DTSTART: %v", err)
+		return row
+	}
+	req.DateTime = start
+	if tzid != "" {
+		req.TimeZone = tzid
+	}
+
+	if end := event.GetProperty(ics.ComponentPropertyDtEnd); end != nil {
+		endTime, _, err := eventTime(end, req.TimeZone)
+		if err != nil {
+			row.addError("DTEND: %v", err)
+		} else {
+			req.EndTime = &endTime
+		}
+	} else if duration := text(ics.ComponentPropertyDuration); duration != "" {
+		d, err := parseDuration(duration)
+		if err != nil {
+			row.addError("DURATION: %v", err)
+		} else {
+			endTime := start.Add(d)
+			req.EndTime = &endTime
+		}
+	}
+	return row
+}
+
+// eventTime разбирает DTSTART/DTEND. Время с TZID - в этом поясе (он же возвращается вторым значением),
+// с суффиксом Z - UTC, "плавающее" время без пояса - в поясе defaultZone.
+func eventTime(p *ics.IANAProperty, defaultZone string) (time.Time, string, error) {
+	if p == nil {
+		return time.Time{}, "", fmt.Errorf("property is missing")
+	}
+	value := strings.TrimSpace(p.Value)
+	if p.GetValueType() == ics.ValueDataTypeDate || len(value) == len("20060102") {
+		return time.Time{}, "", fmt.Errorf("all-day events are not supported")
+	}
+	if strings.HasSuffix(value, "Z") {
+		t, err := time.Parse(icsUTCLayout, value)
+		if err != nil {
+			return time.Time{}, "", fmt.Errorf("invalid date-time %q", value)
+		}
+		return t, "", nil
+	}
+
+	zone := defaultZone
+	tzid := ""
+	if params := p.ICalParameters[string(ics.ParameterTzid)]; len(params) > 0 {
+		tzid = strings.Trim(params[0], `"`)
+		zone = tzid
+	}
+	loc, err := loadLocation(zone)
+	if err != nil {
+		return time.Time{}, "", fmt.Errorf("unknown time zone %q", zone)
+	}
+	t, err := time.ParseInLocation(icsLocalLayout, value, loc)
+	if err != nil {
+		return time.Time{}, "", fmt.Errorf("invalid date-time %q", value)
+	}
+	return t, tzid, nil
+}
+
+// durationPattern - длительность RFC 5545 (P[n]W или P[n]DT[n]H[n]M[n]S); отрицательная не допускается
+var durationPattern = regexp.MustCompile(`^\+?P(?:(\d+)W|(?:(\d+)D)?(?:T(?:(\d+)H)?(?:(\d+)M)?(?:(\d+)S)?)?)$`)
+
+// parseDuration разбирает значение DURATION
+func parseDuration(value string) (time.Duration, error) {
+	match := durationPattern.FindStringSubmatch(strings.ToUpper(value))
+	if match == nil || value == "P" || strings.HasSuffix(value, "T") {
+		return 0, fmt.Errorf("invalid duration %q", value)
+	}
+	units := []time.Duration{7 * 24 * time.Hour, 24 * time.Hour, time.Hour, time.Minute, time.Second}
+	var total time.Duration
+	for i, unit := range units {
+		if match[i+1] == "" {
+			continue
+		}
+		n, err := strconv.Atoi(match[i+1])
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration %q", value)
+		}
+		total += time.Duration(n) * unit
+	}
+	return total, nil
+}
@@ -0,0 +1,110 @@
+package importer
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+const testCalendar = "BEGIN:VCALENDAR\r\n" +
+	"VERSION:2.0\r\n" +
+	"PRODID:-//test//EN\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"UID:1@test\r\n" +
+	"SUMMARY:Watercolor\\, basics\r\n" +
+	"DTSTART;TZID=Europe/Berlin:20250310T180000\r\n" +
+	"DURATION:PT1H30M\r\n" +
+	"LOCATION:Studio 4\r\n" +
+	"CATEGORIES:art,painting\r\n" +
+	"GEO:52.52;13.405\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"UID:2@test\r\n" +
+	"SUMMARY:Floating time\r\n" +
+	"DTSTART:20250311T090000\r\n" +
+	"DTEND:20250311T100000\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"UID:3@test\r\n" +
+	"SUMMARY:Weekly\r\n" +
+	"DTSTART:20250312T090000Z\r\n" +
+	"RRULE:FREQ=WEEKLY;COUNT=3\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"UID:4@test\r\n" +
+	"SUMMARY:Cancelled\r\n" +
+	"STATUS:CANCELLED\r\n" +
+	"DTSTART:20250313T090000Z\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"UID:5@test\r\n" +
+	"SUMMARY:Holiday\r\n" +
+	"DTSTART;VALUE=DATE:20250314\r\n" +
+	"END:VEVENT\r\n" +
+	"END:VCALENDAR\r\n"
+
+func TestParseICS(t *testing.T) {
+	rows, err := ParseICS([]byte(testCalendar), Defaults{MaxParticipants: 10, TimeZone: "Europe/Moscow", Location: "online"})
+	if err != nil {
+		t.Fatalf("ParseICS() error = %v", err)
+	}
+	if len(rows) != 5 {
+		t.Fatalf("got %d rows, want 5", len(rows))
+	}
+
+	berlin, _ := time.LoadLocation("Europe/Berlin")
+	first := rows[0]
+	start := time.Date(2025, 3, 10, 18, 0, 0, 0, berlin)
+	if len(first.Errors) != 0 {
+		t.Fatalf("unexpected errors: %v", first.Errors)
+	}
+	req := first.Request
+	if req.Title != "Watercolor, basics" || req.TimeZone != "Europe/Berlin" || !req.DateTime.Equal(start) {
+		t.Errorf("unexpected first event: %+v", req)
+	}
+	if req.EndTime == nil || !req.EndTime.Equal(start.Add(90*time.Minute)) {
+		t.Errorf("EndTime = %v, want %v", req.EndTime, start.Add(90*time.Minute))
+	}
+	if req.Category != "art" || len(req.Tags) != 2 || req.Location != "Studio 4" || req.MaxParticipants != 10 {
+		t.Errorf("unexpected fields: %+v", req)
+	}
+	if req.Latitude == nil || *req.Latitude != 52.52 || req.Longitude == nil || *req.Longitude != 13.405 {
+		t.Errorf("coordinates = %v, %v", req.Latitude, req.Longitude)
+	}
+
+	moscow, _ := time.LoadLocation("Europe/Moscow")
+	floating := rows[1].Request
+	if !floating.DateTime.Equal(time.Date(2025, 3, 11, 9, 0, 0, 0, moscow)) || floating.TimeZone != "Europe/Moscow" || floating.Location != "online" {
+		t.Errorf("floating time not placed in default zone: %+v", floating)
+	}
+
+	if len(rows[2].Errors) != 1 || !strings.Contains(rows[2].Errors[0], "recurring") {
+		t.Errorf("recurring event errors = %v", rows[2].Errors)
+	}
+	if rows[3].Skipped == "" {
+		t.Error("cancelled event must be skipped")
+	}
+	if len(rows[4].Errors) != 1 || !strings.Contains(rows[4].Errors[0], "all-day") {
+		t.Errorf("all-day event errors = %v", rows[4].Errors)
+	}
+}
+
+func TestParseDuration(t *testing.T) {
+	cases := map[string]time.Duration{
+		"PT1H30M": 90 * time.Minute,
+		"P1D":     24 * time.Hour,
+		"P1W":     7 * 24 * time.Hour,
+		"PT45S":   45 * time.Second,
+		"P1DT2H":  26 * time.Hour,
+	}
+	for value, want := range cases {
+		if got, err := parseDuration(value); err != nil || got != want {
+			t.Errorf("parseDuration(%q) = %v, %v; want %v", value, got, err, want)
+		}
+	}
+	for _, value := range []string{"P", "PT", "-PT1H", "1H", "P1H"} {
+		if _, err := parseDuration(value); err == nil {
+			t.Errorf("parseDuration(%q) must fail", value)
+		}
+	}
+}
@@ -0,0 +1,116 @@
+// Package importer разбирает файлы с расписанием (CSV и iCalendar) в запросы на создание сессий.
+// Проверка запросов по правилам SessionRequest выполняется вызывающим кодом: здесь фиксируются
+// только ошибки разбора отдельных строк, чтобы вернуть отчет по каждой строке.
+package importer
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/BuzzLyutic/Skill-sharing-web-platform/models"
+)
+
+// MaxRows ограничивает количество сессий в одном файле импорта
+const MaxRows = 500
+
+var (
+	ErrEmptyFile   = errors.New("file contains no sessions")
+	ErrTooManyRows = fmt.Errorf("file contains more than %d sessions", MaxRows)
+)
+
+// Defaults - значения для полей, которые не заданы в файле (или в формате их нет, как
+// вместимости в iCalendar). TimeZone также задает пояс для времени без смещения.
+type Defaults struct {
+	Category        string
+	Location        string
+	MaxParticipants int
+	TimeZone        string
+	Draft           bool
+}
+
+// Row - одна сессия из файла. Line - номер строки CSV (с учетом заголовка) или порядковый номер
+// VEVENT. Если Errors не пусто, Request заполнен частично.
+type Row struct {
+	Line    int
+	Request models.SessionRequest
+	Errors  []string
+	Skipped string // Причина, по которой строка пропущена (например, отмененное событие)
+}
+
+func (r *Row) addError(format string, args ...interface{}) {
+	r.Errors = append(r.Errors, fmt.Sprintf(format, args...))
+}
+
+// newRow создает строку с запросом, заполненным значениями по умолчанию
+func newRow(line int, defaults Defaults) Row {
+	return Row{
+		Line: line,
+		Request: models.SessionRequest{
+			Category:        defaults.Category,
+			Location:        defaults.Location,
+			MaxParticipants: defaults.MaxParticipants,
+			TimeZone:        defaults.TimeZone,
+			Draft:           defaults.Draft,
+		},
+	}
+}
+
+// loadLocation возвращает часовой пояс по имени IANA; пустое имя - UTC
+func loadLocation(name string) (*time.Location, error) {
+	if name == "" {
+		return time.UTC, nil
+	}
+	return time.LoadLocation(name)
+}
+
+// localTimeLayouts - форматы времени без смещения, которое тогда берется из часового пояса строки
+var localTimeLayouts = []string{"2006-01-02 15:04", "2006-01-02T15:04", "2006-01-02 15:04:05", "2006-01-02T15:04:05"}
+
+// parseTime разбирает время в RFC 3339 или локальное время в поясе loc
+func parseTime(value string, loc *time.Location) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	for _, layout := range localTimeLayouts {
+		if t, err := time.ParseInLocation(layout, value, loc); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid time %q: use RFC 3339 or YYYY-MM-DD HH:MM", value)
+}
+
+// parseBool разбирает логическое значение в привычных для таблиц вариантах
+func parseBool(value string) (bool, error) {
+	switch strings.ToLower(value) {
+	case "true", "yes", "y", "1", "да":
+		return true, nil
+	case "false", "no", "n", "0", "нет":
+		return false, nil
+	}
+	return false, fmt.Errorf("invalid boolean %q", value)
+}
+
+// parseFloat разбирает координату
+func parseFloat(value string) (*float64, error) {
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid number %q", value)
+	}
+	return &f, nil
+}
+
+// splitList делит список тегов по ';' или ','
+func splitList(value string) []string {
+	return strings.FieldsFunc(value, func(r rune) bool { return r == ';' || r == ',' })
+}
+
+// stringPtr возвращает указатель на непустую строку
+func stringPtr(value string) *string {
+	if value == "" {
+		return nil
+	}
+	return &value
+}
@@ -4,6 +4,7 @@ import (
 	"log"
     "os"
     "fmt"
+	_ "time/tzdata" // База часовых поясов IANA встраивается в бинарник: в образе alpine ее нет
 
 	"github.com/BuzzLyutic/Skill-sharing-web-platform/config"
 	"github.com/BuzzLyutic/Skill-sharing-web-platform/repositories"
@@ -83,9 +84,12 @@ func main() {
     sessionRepo := repositories.NewSessionRepository(db)
     userRepo := repositories.NewUserRepository(db)
     notifRepo := repositories.NewNotificationRepository(db)
+    reconfirmRepo := repositories.NewReconfirmationRepository(db)
 
     // Запуск фоновой задачи для проверки напоминаний
     go tasks.CheckSessionReminders(db, sessionRepo, userRepo, notifRepo) // Передаем зависимости
+    go tasks.CompleteEndedSessions(sessionRepo)
+    go tasks.ExpireReconfirmations(reconfirmRepo)
     log.Printf("Server starting on port %s", cfg.ServerPort)
     if err := r.Run(":" + cfg.ServerPort); err != nil {
         log.Fatalf("Failed to start server: %v", err)
@@ -0,0 +1,128 @@
+package models
+
+import (
+	"errors"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrAttachmentTypeNotAllowed = errors.New("file type is not allowed")
+	ErrAttachmentTypeMismatch   = errors.New("file content does not match its type")
+)
+
+// SessionAttachment - материал сессии (слайды, раздаточные материалы, запись).
+// Сам файл лежит в хранилище под ключом StorageKey, который клиенту не показывается.
+type SessionAttachment struct {
+	ID          uuid.UUID  `json:"id" db:"id"`
+	SessionID   uuid.UUID  `json:"session_id" db:"session_id"`
+	UploadedBy  *uuid.UUID `json:"uploaded_by,omitempty" db:"uploaded_by"`
+	FileName    string     `json:"file_name" db:"file_name"`
+	ContentType string     `json:"content_type" db:"content_type"`
+	SizeBytes   int64      `json:"size_bytes" db:"size_bytes"`
+	StorageKey  string     `json:"-" db:"storage_key"`
+	CreatedAt   time.Time  `json:"created_at" db:"created_at"`
+}
+
+// AttachmentDownload - подписанная ссылка на скачивание вложения
+type AttachmentDownload struct {
+	URL       string    `json:"url"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// allowedAttachmentTypes сопоставляет разрешенные MIME-типы с результатом http.DetectContentType
+// для их содержимого. Офисные документы OOXML и ODF - это zip-архивы, старые форматы Office
+// распознаются только как application/octet-stream.
+var allowedAttachmentTypes = map[string]string{
+	"application/pdf": "application/pdf",
+	"image/png":       "image/png",
+	"image/jpeg":      "image/jpeg",
+	"image/gif":       "image/gif",
+	"image/webp":      "image/webp",
+	"text/plain":      "text/plain",
+	"text/markdown":   "text/plain",
+	"text/csv":        "text/plain",
+	"application/zip": "application/zip",
+	"application/vnd.openxmlformats-officedocument.presentationml.presentation": "application/zip",
+	"application/vnd.openxmlformats-officedocument.wordprocessingml.document":   "application/zip",
+	"application/vnd.openxmlformats-officedocument.spreadsheetml.sheet":         "application/zip",
+	"application/vnd.oasis.opendocument.presentation":                           "application/zip",
+	"application/vnd.oasis.opendocument.text":                                   "application/zip",
+	"application/vnd.oasis.opendocument.spreadsheet":                            "application/zip",
+	"application/vnd.ms-powerpoint":                                             "application/octet-stream",
+	"application/msword":                                                        "application/octet-stream",
+	"application/vnd.ms-excel":                                                  "application/octet-stream",
+	"video/mp4":                                                                 "video/mp4",
+	"video/webm":                                                                "video/webm",
+	"audio/mpeg":                                                                "audio/mpeg",
+}
+
+// attachmentExtensions - типы по расширению для клиентов, не передающих Content-Type.
+// Таблица mime.TypeByExtension зависит от системы (в образе alpine ее почти нет), поэтому задана явно.
+var attachmentExtensions = map[string]string{
+	".pdf":  "application/pdf",
+	".png":  "image/png",
+	".jpg":  "image/jpeg",
+	".jpeg": "image/jpeg",
+	".gif":  "image/gif",
+	".webp": "image/webp",
+	".txt":  "text/plain",
+	".md":   "text/markdown",
+	".csv":  "text/csv",
+	".zip":  "application/zip",
+	".pptx": "application/vnd.openxmlformats-officedocument.presentationml.presentation",
+	".docx": "application/vnd.openxmlformats-officedocument.wordprocessingml.document",
+	".xlsx": "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+	".odp":  "application/vnd.oasis.opendocument.presentation",
+	".odt":  "application/vnd.oasis.opendocument.text",
+	".ods":  "application/vnd.oasis.opendocument.spreadsheet",
+	".ppt":  "application/vnd.ms-powerpoint",
+	".doc":  "application/msword",
+	".xls":  "application/vnd.ms-excel",
+	".mp4":  "video/mp4",
+	".webm": "video/webm",
+	".mp3":  "audio/mpeg",
+}
+
+// AttachmentExtension возвращает расширение файла для разрешенного MIME-типа (самое короткое из
+// attachmentExtensions, например ".jpg" для image/jpeg). Расширение имени от клиента не используется:
+// по нему файл могли бы отдать с другим Content-Type.
+func AttachmentExtension(contentType string) string {
+	extension := ""
+	for candidate, candidateType := range attachmentExtensions {
+		if candidateType != contentType {
+			continue
+		}
+		if extension == "" || len(candidate) < len(extension) || (len(candidate) == len(extension) && candidate < extension) {
+			extension = candidate
+		}
+	}
+	return extension
+}
+
+// DetectAttachmentType определяет MIME-тип загружаемого файла: берется заявленный клиентом тип,
+// а если его нет - тип по расширению. Тип должен быть разрешен, а начало содержимого head
+// (до 512 байт) - соответствовать ему.
+func DetectAttachmentType(fileName, declared string, head []byte) (string, error) {
+	contentType := ""
+	if mediaType, _, err := mime.ParseMediaType(declared); err == nil && mediaType != "application/octet-stream" {
+		contentType = mediaType
+	} else {
+		contentType = attachmentExtensions[strings.ToLower(filepath.Ext(fileName))]
+	}
+
+	expected, ok := allowedAttachmentTypes[contentType]
+	if !ok {
+		return "", ErrAttachmentTypeNotAllowed
+	}
+	sniffed, _, _ := mime.ParseMediaType(http.DetectContentType(head))
+	if sniffed != expected {
+		return "", ErrAttachmentTypeMismatch
+	}
+	return contentType, nil
+}
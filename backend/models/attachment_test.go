@@ -0,0 +1,47 @@
+package models
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestDetectAttachmentType(t *testing.T) {
+	pdf := []byte("%PDF-1.7\n%\xe2\xe3\xcf\xd3\n")
+	zip := []byte("PK\x03\x04\x14\x00\x06\x00")
+	cases := []struct {
+		name     string
+		fileName string
+		declared string
+		head     []byte
+		want     string
+		wantErr  error
+	}{
+		{"declared pdf", "slides.pdf", "application/pdf", pdf, "application/pdf", nil},
+		{"type from extension", "slides.PDF", "application/octet-stream", pdf, "application/pdf", nil},
+		{"pptx is zip", "deck.pptx", "", zip, "application/vnd.openxmlformats-officedocument.presentationml.presentation", nil},
+		{"markdown", "notes.md", "text/markdown; charset=utf-8", []byte("# Notes\n"), "text/markdown", nil},
+		{"executable", "setup.exe", "application/x-msdownload", []byte("MZ\x90\x00"), "", ErrAttachmentTypeNotAllowed},
+		{"html", "page.html", "text/html", []byte("<html></html>"), "", ErrAttachmentTypeNotAllowed},
+		{"disguised html", "slides.pdf", "application/pdf", []byte("<html><script>"), "", ErrAttachmentTypeMismatch},
+	}
+	for _, tc := range cases {
+		got, err := DetectAttachmentType(tc.fileName, tc.declared, tc.head)
+		if !errors.Is(err, tc.wantErr) || got != tc.want {
+			t.Errorf("%s: DetectAttachmentType() = %q, %v; want %q, %v", tc.name, got, err, tc.want, tc.wantErr)
+		}
+	}
+}
+
+func TestAttachmentExtension(t *testing.T) {
+	cases := map[string]string{
+		"application/pdf": ".pdf",
+		"image/jpeg":      ".jpg",
+		"text/plain":      ".txt",
+		"text/html":       "",
+	}
+	for contentType, want := range cases {
+		if got := AttachmentExtension(contentType); got != want {
+			t.Errorf("AttachmentExtension(%q) = %q, want %q", contentType, got, want)
+		}
+	}
+}
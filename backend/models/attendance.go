@@ -0,0 +1,83 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AttendanceStatus - отметка присутствия участника сессии
+type AttendanceStatus string
+
+const (
+	AttendanceUnknown  AttendanceStatus = "unknown"  // Присутствие еще не отмечено
+	AttendanceAttended AttendanceStatus = "attended" // Участник пришел
+	AttendanceNoShow   AttendanceStatus = "no_show"  // Участник записался, но не пришел
+)
+
+// CheckInMethod - способ, которым отмечено присутствие
+type CheckInMethod string
+
+const (
+	CheckInMethodHost CheckInMethod = "host" // Отметил организатор
+	CheckInMethodCode CheckInMethod = "code" // Участник ввел код или отсканировал QR
+)
+
+// CheckInLeadTime - за сколько до начала сессии открывается отметка присутствия
+const CheckInLeadTime = 30 * time.Minute
+
+// Attendee - участник сессии с отметкой присутствия и посещаемостью по всем его сессиям.
+// AttendanceRate - доля посещенных сессий среди тех, где присутствие отмечено; nil, если отметок нет.
+type Attendee struct {
+	UserID         uuid.UUID        `json:"user_id" db:"user_id"`
+	Name           string           `json:"name" db:"name"`
+	Email          string           `json:"email" db:"email"`
+	JoinedAt       *time.Time       `json:"joined_at,omitempty" db:"joined_at"`
+	Attendance     AttendanceStatus `json:"attendance" db:"attendance"`
+	CheckedInAt    *time.Time       `json:"checked_in_at,omitempty" db:"checked_in_at"`
+	CheckInMethod  *CheckInMethod   `json:"check_in_method,omitempty" db:"check_in_method"`
+	AttendedCount  int              `json:"attended_count" db:"attended_count"`
+	NoShowCount    int              `json:"no_show_count" db:"no_show_count"`
+	AttendanceRate *float64         `json:"attendance_rate" db:"attendance_rate"`
+}
+
+// AttendanceEntry - отметка присутствия одного участника организатором
+type AttendanceEntry struct {
+	UserID uuid.UUID        `json:"user_id" binding:"required"`
+	Status AttendanceStatus `json:"status" binding:"required,oneof=attended no_show unknown"`
+}
+
+// AttendanceUpdateRequest для PUT /api/sessions/:id/attendance.
+// MarkRemainingNoShow помечает неявившимися всех, чье присутствие так и не отмечено.
+type AttendanceUpdateRequest struct {
+	Entries             []AttendanceEntry `json:"entries" binding:"dive"`
+	MarkRemainingNoShow bool              `json:"mark_remaining_no_show"`
+}
+
+// CheckInRequest для самостоятельной отметки участника кодом (или кодом из QR)
+type CheckInRequest struct {
+	Code string `json:"code" binding:"required"`
+}
+
+// CheckInCode - текущий код отметки для показа участникам.
+// QRPayload - ссылка на страницу отметки с кодом, которую клиент отображает в виде QR.
+type CheckInCode struct {
+	Code      string    `json:"code"`
+	ExpiresAt time.Time `json:"expires_at"`
+	QRPayload string    `json:"qr_payload"`
+}
+
+// CheckInOpen сообщает, могут ли участники отмечаться сами в момент now:
+// сессия опубликована, до начала осталось не больше CheckInLeadTime и она еще не закончилась.
+func (s *Session) CheckInOpen(now time.Time) bool {
+	return s.Status == SessionStatusPublished && !now.Before(s.DateTime.Add(-CheckInLeadTime)) && now.Before(s.EndTime)
+}
+
+// AttendanceMarkable сообщает, может ли организатор отмечать присутствие в момент now:
+// сессия опубликована или завершена и отметка уже открылась.
+func (s *Session) AttendanceMarkable(now time.Time) bool {
+	if s.Status != SessionStatusPublished && s.Status != SessionStatusCompleted {
+		return false
+	}
+	return !now.Before(s.DateTime.Add(-CheckInLeadTime))
+}
@@ -0,0 +1,38 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSessionCheckInWindow(t *testing.T) {
+	start := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	session := Session{DateTime: start, EndTime: start.Add(time.Hour), Status: SessionStatusPublished}
+	completed := session
+	completed.Status = SessionStatusCompleted
+	cancelled := session
+	cancelled.Status = SessionStatusCancelled
+
+	cases := []struct {
+		name         string
+		session      Session
+		now          time.Time
+		wantOpen     bool
+		wantMarkable bool
+	}{
+		{"too early", session, start.Add(-time.Hour), false, false},
+		{"lead time", session, start.Add(-CheckInLeadTime), true, true},
+		{"in progress", session, start.Add(30 * time.Minute), true, true},
+		{"ended", session, start.Add(time.Hour), false, true},
+		{"completed", completed, start.Add(2 * time.Hour), false, true},
+		{"cancelled", cancelled, start, false, false},
+	}
+	for _, tc := range cases {
+		if got := tc.session.CheckInOpen(tc.now); got != tc.wantOpen {
+			t.Errorf("%s: CheckInOpen() = %v, want %v", tc.name, got, tc.wantOpen)
+		}
+		if got := tc.session.AttendanceMarkable(tc.now); got != tc.wantMarkable {
+			t.Errorf("%s: AttendanceMarkable() = %v, want %v", tc.name, got, tc.wantMarkable)
+		}
+	}
+}
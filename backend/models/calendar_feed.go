@@ -0,0 +1,31 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CalendarFeedWindow - насколько в прошлое подписка на календарь показывает сессии.
+// Недавно прошедшие сессии остаются в ленте, чтобы календари не удаляли их сразу после окончания.
+const CalendarFeedWindow = 30 * 24 * time.Hour
+
+// CalendarFeed - персональная подписка пользователя на календарь (сам токен не хранится)
+type CalendarFeed struct {
+	UserID    uuid.UUID `json:"user_id" db:"user_id"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
+
+// CalendarFeedStatus для GET /api/users/me/calendar-feed
+type CalendarFeedStatus struct {
+	Active    bool       `json:"active"`
+	CreatedAt *time.Time `json:"created_at,omitempty"`
+}
+
+// CalendarFeedLink - ссылки на подписку; возвращаются только при создании,
+// т.к. в БД хранится лишь хэш токена
+type CalendarFeedLink struct {
+	URL       string    `json:"url"`        // https-ссылка для добавления календаря по URL
+	WebcalURL string    `json:"webcal_url"` // Та же ссылка со схемой webcal:// для подписки одним кликом
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -0,0 +1,146 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DefaultLocale - язык названия категории, если нужного перевода нет
+const DefaultLocale = "en"
+
+var (
+	ErrInvalidSlug   = errors.New("slug must contain only lowercase latin letters, digits and single hyphens")
+	ErrMissingName   = errors.New("names must include an English (en) name")
+	ErrInvalidLocale = errors.New("name locales must be 2-10 letter codes such as en or pt-br")
+	slugPattern      = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+	localePattern    = regexp.MustCompile(`^[a-z]{2,3}(-[a-z0-9]{2,8})?$`)
+)
+
+// LocalizedNames - названия категории по языкам: {"en": "Programming", "ru": "Программирование"}.
+// Хранится в JSONB.
+type LocalizedNames map[string]string
+
+// Scan реализует sql.Scanner
+func (n *LocalizedNames) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case nil:
+		*n = LocalizedNames{}
+		return nil
+	case []byte:
+		return json.Unmarshal(v, n)
+	case string:
+		return json.Unmarshal([]byte(v), n)
+	default:
+		return fmt.Errorf("unsupported type %T for localized names", src)
+	}
+}
+
+// Value реализует driver.Valuer
+func (n LocalizedNames) Value() (driver.Value, error) {
+	if n == nil {
+		return []byte("{}"), nil
+	}
+	return json.Marshal(n)
+}
+
+// Category - узел дерева категорий сессий. Сессии ссылаются на категорию по slug.
+type Category struct {
+	ID        uuid.UUID      `json:"id" db:"id"`
+	ParentID  *uuid.UUID     `json:"parent_id,omitempty" db:"parent_id"`
+	Slug      string         `json:"slug" db:"slug"`
+	Names     LocalizedNames `json:"names" db:"names"`
+	Name      string         `json:"name" db:"-"` // Название на запрошенном языке, заполняется контроллером
+	Icon      *string        `json:"icon,omitempty" db:"icon"`
+	Position  int            `json:"position" db:"position"`
+	CreatedAt time.Time      `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at" db:"updated_at"`
+	Children  []Category     `json:"children,omitempty" db:"-"`
+}
+
+// LocalizedName возвращает название на языке locale (например, "ru" или "pt-BR"), затем на языке
+// без региона, затем английское; если перевода нет - slug
+func (c *Category) LocalizedName(locale string) string {
+	locale = strings.ToLower(locale)
+	base, _, _ := strings.Cut(locale, "-")
+	for _, candidate := range []string{locale, base, DefaultLocale} {
+		if name := c.Names[candidate]; name != "" {
+			return name
+		}
+	}
+	return c.Slug
+}
+
+// CategoryRequest для создания и изменения категории администратором
+type CategoryRequest struct {
+	ParentID *uuid.UUID        `json:"parent_id"` // nil - категория верхнего уровня
+	Slug     string            `json:"slug" binding:"required,max=50"`
+	Names    map[string]string `json:"names" binding:"required,max=20,dive,max=100"`
+	Icon     *string           `json:"icon,omitempty" binding:"omitempty,max=100"`
+	Position int               `json:"position"`
+}
+
+// Normalize приводит slug и коды языков к нижнему регистру, убирает пустые названия и проверяет формат
+func (r *CategoryRequest) Normalize() error {
+	r.Slug = strings.ToLower(strings.TrimSpace(r.Slug))
+	if !slugPattern.MatchString(r.Slug) {
+		return ErrInvalidSlug
+	}
+	names := make(map[string]string, len(r.Names))
+	for locale, name := range r.Names {
+		locale = strings.ToLower(strings.TrimSpace(locale))
+		if !localePattern.MatchString(locale) {
+			return ErrInvalidLocale
+		}
+		if name = strings.TrimSpace(name); name != "" {
+			names[locale] = name
+		}
+	}
+	if names[DefaultLocale] == "" {
+		return ErrMissingName
+	}
+	r.Names = names
+	r.Icon = trimToNil(r.Icon)
+	return nil
+}
+
+// BuildCategoryTree собирает дерево из плоского списка категорий. Названия заполняются на языке locale,
+// категории одного уровня упорядочены по position, затем по slug.
+func BuildCategoryTree(categories []Category, locale string) []Category {
+	children := make(map[uuid.UUID][]Category)
+	var roots []Category
+	for _, category := range categories {
+		category.Name = category.LocalizedName(locale)
+		if category.ParentID == nil {
+			roots = append(roots, category)
+		} else {
+			children[*category.ParentID] = append(children[*category.ParentID], category)
+		}
+	}
+
+	var attach func(level []Category) []Category
+	attach = func(level []Category) []Category {
+		sort.SliceStable(level, func(i, j int) bool {
+			if level[i].Position != level[j].Position {
+				return level[i].Position < level[j].Position
+			}
+			return level[i].Slug < level[j].Slug
+		})
+		for i := range level {
+			level[i].Children = attach(children[level[i].ID])
+		}
+		return level
+	}
+	tree := attach(roots)
+	if tree == nil {
+		tree = []Category{}
+	}
+	return tree
+}
@@ -0,0 +1,71 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestCategoryRequestNormalize(t *testing.T) {
+	icon := "  "
+	req := CategoryRequest{Slug: " Web-Development ", Names: map[string]string{"EN": " Web development ", "ru": "Веб-разработка", "de": " "}, Icon: &icon}
+	if err := req.Normalize(); err != nil {
+		t.Fatalf("Normalize() error = %v", err)
+	}
+	if req.Slug != "web-development" || req.Names["en"] != "Web development" || len(req.Names) != 2 || req.Icon != nil {
+		t.Errorf("unexpected normalized request: %+v", req)
+	}
+
+	cases := map[string]struct {
+		req  CategoryRequest
+		want error
+	}{
+		"spaces in slug":  {CategoryRequest{Slug: "web development", Names: map[string]string{"en": "Web"}}, ErrInvalidSlug},
+		"double hyphen":   {CategoryRequest{Slug: "web--dev", Names: map[string]string{"en": "Web"}}, ErrInvalidSlug},
+		"no english name": {CategoryRequest{Slug: "web", Names: map[string]string{"ru": "Веб"}}, ErrMissingName},
+		"invalid locale":  {CategoryRequest{Slug: "web", Names: map[string]string{"en": "Web", "english": "Web"}}, ErrInvalidLocale},
+		"blank english":   {CategoryRequest{Slug: "web", Names: map[string]string{"en": " "}}, ErrMissingName},
+	}
+	for name, tc := range cases {
+		if err := tc.req.Normalize(); err != tc.want {
+			t.Errorf("%s: Normalize() error = %v, want %v", name, err, tc.want)
+		}
+	}
+}
+
+func TestCategoryLocalizedName(t *testing.T) {
+	c := Category{Slug: "programming", Names: LocalizedNames{"en": "Programming", "pt": "Programação", "pt-br": "Programação (BR)"}}
+	for locale, want := range map[string]string{"pt-BR": "Programação (BR)", "pt-PT": "Programação", "ru": "Programming"} {
+		if got := c.LocalizedName(locale); got != want {
+			t.Errorf("LocalizedName(%q) = %q, want %q", locale, got, want)
+		}
+	}
+	if got := (&Category{Slug: "other"}).LocalizedName("ru"); got != "other" {
+		t.Errorf("LocalizedName without names = %q, want slug", got)
+	}
+}
+
+func TestBuildCategoryTree(t *testing.T) {
+	programming, design := uuid.New(), uuid.New()
+	categories := []Category{
+		{ID: uuid.New(), ParentID: &programming, Slug: "web-development", Names: LocalizedNames{"en": "Web development"}, Position: 1},
+		{ID: design, Slug: "design", Names: LocalizedNames{"en": "Design"}, Position: 2},
+		{ID: programming, Slug: "programming", Names: LocalizedNames{"en": "Programming", "ru": "Программирование"}, Position: 1},
+		{ID: uuid.New(), ParentID: &programming, Slug: "data-science", Names: LocalizedNames{"en": "Data science"}, Position: 1},
+	}
+
+	tree := BuildCategoryTree(categories, "ru")
+	if len(tree) != 2 || tree[0].Slug != "programming" || tree[1].Slug != "design" {
+		t.Fatalf("unexpected roots: %+v", tree)
+	}
+	if tree[0].Name != "Программирование" || tree[1].Name != "Design" {
+		t.Errorf("names = %q, %q", tree[0].Name, tree[1].Name)
+	}
+	children := tree[0].Children
+	if len(children) != 2 || children[0].Slug != "data-science" || children[1].Slug != "web-development" {
+		t.Errorf("children must be sorted by position, then slug: %+v", children)
+	}
+	if empty := BuildCategoryTree(nil, "en"); empty == nil || len(empty) != 0 {
+		t.Errorf("empty tree must be an empty slice, got %#v", empty)
+	}
+}
@@ -0,0 +1,86 @@
+package models
+
+import (
+	"regexp"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SessionComment - комментарий в обсуждении сессии. Удаленные комментарии остаются в дереве,
+// чтобы не терять ответы, но их текст не возвращается (Body пустой, DeletedAt задан).
+type SessionComment struct {
+	ID         uuid.UUID        `json:"id" db:"id"`
+	SessionID  uuid.UUID        `json:"session_id" db:"session_id"`
+	ParentID   *uuid.UUID       `json:"parent_id,omitempty" db:"parent_id"`
+	UserID     *uuid.UUID       `json:"user_id,omitempty" db:"user_id"` // NULL, если автор удалил аккаунт
+	AuthorName *string          `json:"author_name,omitempty" db:"author_name"`
+	Body       string           `json:"body" db:"body"`
+	CreatedAt  time.Time        `json:"created_at" db:"created_at"`
+	EditedAt   *time.Time       `json:"edited_at,omitempty" db:"edited_at"`
+	DeletedAt  *time.Time       `json:"deleted_at,omitempty" db:"deleted_at"`
+	Replies    []SessionComment `json:"replies" db:"-"`
+}
+
+// IsDeleted сообщает, удален ли комментарий
+func (c *SessionComment) IsDeleted() bool {
+	return c.DeletedAt != nil
+}
+
+// CommentRequest для создания комментария или ответа (ParentID) и для редактирования (только Body)
+type CommentRequest struct {
+	Body     string     `json:"body" binding:"required,max=5000"`
+	ParentID *uuid.UUID `json:"parent_id"`
+}
+
+// mentionPattern - упоминание в формате @[Имя](id пользователя), который вставляет клиент
+// при выборе пользователя из подсказки. Имя только отображается, адресат определяется по id.
+var mentionPattern = regexp.MustCompile(`@\[[^\]\n]{1,100}\]\(([0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12})\)`)
+
+// ParseMentions возвращает ID упомянутых в тексте пользователей без повторов, в порядке появления
+func ParseMentions(body string) []uuid.UUID {
+	mentioned := []uuid.UUID{}
+	seen := map[uuid.UUID]bool{}
+	for _, match := range mentionPattern.FindAllStringSubmatch(body, -1) {
+		id, err := uuid.Parse(match[1])
+		if err != nil || seen[id] {
+			continue
+		}
+		seen[id] = true
+		mentioned = append(mentioned, id)
+	}
+	return mentioned
+}
+
+// BuildCommentTree собирает плоский список комментариев (упорядоченный по времени) в дерево.
+// Комментарии, чей родитель отсутствует в списке, становятся корневыми.
+func BuildCommentTree(flat []SessionComment) []SessionComment {
+	children := map[uuid.UUID][]int{}
+	present := make(map[uuid.UUID]bool, len(flat))
+	for _, c := range flat {
+		present[c.ID] = true
+	}
+	roots := []int{}
+	for i, c := range flat {
+		if c.ParentID != nil && present[*c.ParentID] && *c.ParentID != c.ID {
+			children[*c.ParentID] = append(children[*c.ParentID], i)
+		} else {
+			roots = append(roots, i)
+		}
+	}
+
+	var build func(i int) SessionComment
+	build = func(i int) SessionComment {
+		node := flat[i]
+		node.Replies = make([]SessionComment, 0, len(children[node.ID]))
+		for _, child := range children[node.ID] {
+			node.Replies = append(node.Replies, build(child))
+		}
+		return node
+	}
+	tree := make([]SessionComment, 0, len(roots))
+	for _, i := range roots {
+		tree = append(tree, build(i))
+	}
+	return tree
+}
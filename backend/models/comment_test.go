@@ -0,0 +1,49 @@
+package models
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestParseMentions(t *testing.T) {
+	anna, boris := uuid.New(), uuid.New()
+	body := "Thanks @[Anna K](" + anna.String() + ")! @[Boris](" + boris.String() + ") and again @[Anna](" + anna.String() + "). " +
+		"Not mentions: @Anna, [x](" + anna.String() + "), @[Bad](not-a-uuid)"
+
+	got := ParseMentions(body)
+
+	if want := []uuid.UUID{anna, boris}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseMentions() = %v, want %v", got, want)
+	}
+	if got := ParseMentions("no mentions"); len(got) != 0 {
+		t.Errorf("ParseMentions() = %v, want empty", got)
+	}
+}
+
+func TestBuildCommentTree(t *testing.T) {
+	root, reply, nested, other := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	missing := uuid.New()
+	flat := []SessionComment{
+		{ID: root},
+		{ID: reply, ParentID: &root},
+		{ID: other, ParentID: &missing},
+		{ID: nested, ParentID: &reply},
+	}
+
+	tree := BuildCommentTree(flat)
+
+	if len(tree) != 2 || tree[0].ID != root || tree[1].ID != other {
+		t.Fatalf("unexpected roots: %+v", tree)
+	}
+	if len(tree[0].Replies) != 1 || tree[0].Replies[0].ID != reply {
+		t.Fatalf("unexpected replies: %+v", tree[0].Replies)
+	}
+	if len(tree[0].Replies[0].Replies) != 1 || tree[0].Replies[0].Replies[0].ID != nested {
+		t.Fatalf("unexpected nested replies: %+v", tree[0].Replies[0].Replies)
+	}
+	if tree[1].Replies == nil {
+		t.Error("Replies must be an empty slice, not nil")
+	}
+}
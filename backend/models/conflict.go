@@ -0,0 +1,30 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Роль пользователя в пересекающейся сессии
+const (
+	ConflictRoleHost        = "host"
+	ConflictRoleParticipant = "participant"
+)
+
+// SessionConflict - сессия пользователя, пересекающаяся по времени с другой
+type SessionConflict struct {
+	SessionID uuid.UUID     `json:"session_id" db:"session_id"`
+	Title     string        `json:"title" db:"title"`
+	DateTime  time.Time     `json:"date_time" db:"date_time"`
+	EndTime   time.Time     `json:"end_time" db:"end_time"`
+	TimeZone  string        `json:"time_zone" db:"time_zone"`
+	Status    SessionStatus `json:"status" db:"status"`
+	Role      string        `json:"role" db:"role"` // host или participant
+}
+
+// ScheduleClash - пара уже существующих пересекающихся сессий пользователя
+type ScheduleClash struct {
+	First  SessionConflict `json:"first" db:"first"`
+	Second SessionConflict `json:"second" db:"second"`
+}
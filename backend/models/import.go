@@ -0,0 +1,38 @@
+package models
+
+import "github.com/google/uuid"
+
+// ImportRowStatus - результат обработки строки файла импорта
+type ImportRowStatus string
+
+const (
+	ImportRowValid   ImportRowStatus = "valid"   // Прошла проверку (в пробном режиме или если в файле есть ошибки)
+	ImportRowInvalid ImportRowStatus = "invalid" // Содержит ошибки, см. Errors
+	ImportRowSkipped ImportRowStatus = "skipped" // Пропущена, например отмененное событие календаря
+	ImportRowCreated ImportRowStatus = "created" // Сессия создана
+)
+
+// SessionImportRow - отчет по одной строке CSV или событию iCalendar
+type SessionImportRow struct {
+	Row       int               `json:"row"` // Номер строки CSV или порядковый номер VEVENT
+	Title     string            `json:"title"`
+	Status    ImportRowStatus   `json:"status"`
+	Errors    []string          `json:"errors,omitempty"`
+	Preview   *SessionRequest   `json:"preview,omitempty"` // Нормализованный запрос, только в пробном режиме
+	SessionID *uuid.UUID        `json:"session_id,omitempty"`
+	Conflicts []SessionConflict `json:"conflicts,omitempty"` // Сессии пользователя, с которыми пересекается строка
+}
+
+// SessionImportReport - результат импорта. Сессии создаются, только если все строки корректны
+// и, без allow_conflicts, не пересекаются с другими сессиями пользователя, и все вместе в одной транзакции.
+type SessionImportReport struct {
+	Format      string             `json:"format"` // csv или ics
+	DryRun      bool               `json:"dry_run"`
+	Total       int                `json:"total"`
+	Valid       int                `json:"valid"`
+	Invalid     int                `json:"invalid"`
+	Skipped     int                `json:"skipped"`
+	Conflicting int                `json:"conflicting"` // Корректные строки, пересекающиеся с другими сессиями пользователя
+	Created     int                `json:"created"`
+	Rows        []SessionImportRow `json:"rows"`
+}
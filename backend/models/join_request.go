@@ -0,0 +1,38 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// JoinRequestStatus - состояние заявки на участие в сессии с одобрением
+type JoinRequestStatus string
+
+const (
+	JoinRequestStatusPending   JoinRequestStatus = "pending"   // Ждет решения организаторов
+	JoinRequestStatusApproved  JoinRequestStatus = "approved"  // Одобрена: заявитель стал участником или попал в очередь
+	JoinRequestStatusRejected  JoinRequestStatus = "rejected"  // Отклонена организатором
+	JoinRequestStatusWithdrawn JoinRequestStatus = "withdrawn" // Отозвана заявителем
+)
+
+// SessionJoinRequest - заявка на участие вместе с именем и email заявителя
+type SessionJoinRequest struct {
+	ID              uuid.UUID         `json:"id" db:"id"`
+	SessionID       uuid.UUID         `json:"session_id" db:"session_id"`
+	UserID          uuid.UUID         `json:"user_id" db:"user_id"`
+	Status          JoinRequestStatus `json:"status" db:"status"`
+	Message         *string           `json:"message,omitempty" db:"message"`
+	DecisionMessage *string           `json:"decision_message,omitempty" db:"decision_message"`
+	DecidedBy       *uuid.UUID        `json:"decided_by,omitempty" db:"decided_by"`
+	CreatedAt       time.Time         `json:"created_at" db:"created_at"`
+	DecidedAt       *time.Time        `json:"decided_at,omitempty" db:"decided_at"`
+	Name            string            `json:"name" db:"name"`
+	Email           string            `json:"email" db:"email"`
+}
+
+// JoinRequestDecision - тело запроса на одобрение или отклонение заявки
+type JoinRequestDecision struct {
+	Message        string `json:"message" binding:"max=500"` // Необязательный комментарий для заявителя
+	AllowConflicts bool   `json:"allow_conflicts"`           // Одобрить, даже если сессия пересекается с другими сессиями заявителя
+}
@@ -11,6 +11,17 @@ const (
     NotificationTypeNewParticipant NotificationType = "new_participant"
     NotificationTypeSessionReminder NotificationType = "session_reminder"
     NotificationTypeSessionUpdate  NotificationType = "session_update" // Если сессия изменена
+    NotificationTypeWaitlistPromoted NotificationType = "waitlist_promoted" // Пользователь переведен из очереди в участники
+    NotificationTypeSessionCancelled NotificationType = "session_cancelled" // Сессия отменена создателем
+    NotificationTypeHostInvitation   NotificationType = "host_invitation"   // Приглашение стать соорганизатором
+    NotificationTypeHostAccepted     NotificationType = "host_accepted"     // Приглашенный соорганизатор принял приглашение
+    NotificationTypeJoinRequest      NotificationType = "join_request"      // Новая заявка на участие (организаторам)
+    NotificationTypeJoinApproved     NotificationType = "join_approved"     // Заявка на участие одобрена
+    NotificationTypeJoinRejected     NotificationType = "join_rejected"     // Заявка на участие отклонена
+    NotificationTypeNewComment       NotificationType = "new_comment"       // Новое обсуждение в сессии (организаторам)
+    NotificationTypeCommentMention   NotificationType = "comment_mention"   // Пользователя упомянули в комментарии
+    NotificationTypeMovedToWaitlist  NotificationType = "moved_to_waitlist" // Вместимость уменьшена, участник переведен в очередь ожидания
+    NotificationTypeReconfirm        NotificationType = "reconfirm"         // Сессия перенесена, нужно подтвердить участие
 )
 
 // Notification представляет уведомление для пользователя
@@ -0,0 +1,92 @@
+package models
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ErrInvalidCursor - курсор пагинации поврежден или выдан не этим API
+var ErrInvalidCursor = errors.New("invalid pagination cursor")
+
+// Cursor - позиция в списке для keyset-пагинации: значение ключа сортировки (date_time сессии
+// или created_at уведомления/отзыва) и ID, который разрешает совпадения времени.
+// Клиент получает курсор как непрозрачную строку (Encode) и передает ее обратно без изменений.
+type Cursor struct {
+	Time     time.Time `json:"t"`
+	ID       uuid.UUID `json:"id"`
+	Backward bool      `json:"b,omitempty"` // Курсор ведет на предыдущую страницу
+}
+
+// Encode возвращает непрозрачное строковое представление курсора
+func (c Cursor) Encode() string {
+	data, _ := json.Marshal(c) // Структура из фиксированных типов всегда сериализуется
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodeCursor разбирает строку, полученную из Encode
+func DecodeCursor(value string) (*Cursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	var cursor Cursor
+	if err := json.Unmarshal(data, &cursor); err != nil || cursor.ID == uuid.Nil || cursor.Time.IsZero() {
+		return nil, ErrInvalidCursor
+	}
+	return &cursor, nil
+}
+
+// PageRequest - параметры keyset-пагинации
+type PageRequest struct {
+	Limit     int
+	Cursor    *Cursor // nil - первая страница
+	WithCount bool    // Считать общее количество записей (отдельный COUNT-запрос)
+}
+
+// PageInfo - метаданные страницы, возвращаемые клиенту в блоке meta
+type PageInfo struct {
+	PerPage    int     `json:"per_page"`
+	NextCursor *string `json:"next_cursor"`           // nil - следующей страницы нет
+	PrevCursor *string `json:"prev_cursor"`           // nil - это первая страница
+	TotalItems *int    `json:"total_items,omitempty"` // Только по запросу (count=true)
+}
+
+// NewPageInfo собирает метаданные страницы. fetched - строки, выбранные с запасом в одну
+// (Limit+1) в порядке обхода; key возвращает ключ курсора для элемента.
+// Возвращает элементы страницы в порядке отображения.
+func NewPageInfo[T any](fetched []T, page PageRequest, key func(*T) (time.Time, uuid.UUID)) ([]T, PageInfo) {
+	info := PageInfo{PerPage: page.Limit}
+	hasMore := len(fetched) > page.Limit
+	if hasMore {
+		fetched = fetched[:page.Limit]
+	}
+	backward := page.Cursor != nil && page.Cursor.Backward
+	if backward {
+		// Предыдущая страница выбирается в обратном порядке
+		for i, j := 0, len(fetched)-1; i < j; i, j = i+1, j-1 {
+			fetched[i], fetched[j] = fetched[j], fetched[i]
+		}
+	}
+	if len(fetched) == 0 {
+		return fetched, info
+	}
+
+	cursorFor := func(item *T, backward bool) *string {
+		t, id := key(item)
+		encoded := Cursor{Time: t, ID: id, Backward: backward}.Encode()
+		return &encoded
+	}
+	// Вперед: следующая страница есть, если выбрана лишняя строка, предыдущая - если пришли по курсору.
+	// Назад: наоборот, следующая страница есть всегда (с нее пришли).
+	if hasMore || backward {
+		info.NextCursor = cursorFor(&fetched[len(fetched)-1], false)
+	}
+	if (backward && hasMore) || (!backward && page.Cursor != nil) {
+		info.PrevCursor = cursorFor(&fetched[0], true)
+	}
+	return fetched, info
+}
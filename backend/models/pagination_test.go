@@ -0,0 +1,95 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type pageItem struct {
+	at time.Time
+	id uuid.UUID
+}
+
+func pageItems(n int) []pageItem {
+	base := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	items := make([]pageItem, n)
+	for i := range items {
+		items[i] = pageItem{at: base.Add(time.Duration(i) * time.Hour), id: uuid.New()}
+	}
+	return items
+}
+
+func pageItemKey(item *pageItem) (time.Time, uuid.UUID) { return item.at, item.id }
+
+func TestCursorRoundTrip(t *testing.T) {
+	cursor := Cursor{Time: time.Date(2025, 3, 30, 1, 0, 0, 0, time.UTC), ID: uuid.New(), Backward: true}
+
+	decoded, err := DecodeCursor(cursor.Encode())
+	if err != nil {
+		t.Fatalf("DecodeCursor: %v", err)
+	}
+	if !decoded.Time.Equal(cursor.Time) || decoded.ID != cursor.ID || !decoded.Backward {
+		t.Errorf("decoded %+v, want %+v", decoded, cursor)
+	}
+	for _, invalid := range []string{"not base64!", "bm90IGpzb24", Cursor{}.Encode()} {
+		if _, err := DecodeCursor(invalid); err != ErrInvalidCursor {
+			t.Errorf("DecodeCursor(%q) error = %v, want ErrInvalidCursor", invalid, err)
+		}
+	}
+}
+
+func TestNewPageInfo_FirstPage(t *testing.T) {
+	items := pageItems(3) // Limit+1 строк: следующая страница есть
+
+	page, info := NewPageInfo(items, PageRequest{Limit: 2}, pageItemKey)
+
+	if len(page) != 2 {
+		t.Fatalf("page has %d items, want 2", len(page))
+	}
+	if info.PrevCursor != nil {
+		t.Errorf("first page must not have prev_cursor")
+	}
+	if info.NextCursor == nil {
+		t.Fatalf("expected next_cursor")
+	}
+	next, _ := DecodeCursor(*info.NextCursor)
+	if next.ID != items[1].id || next.Backward {
+		t.Errorf("next cursor points to %+v, want last item of the page", next)
+	}
+}
+
+func TestNewPageInfo_BackwardPage(t *testing.T) {
+	items := pageItems(4)
+	// Страница назад от items[3]: строки выбираются в обратном порядке, с запасом в одну
+	fetched := []pageItem{items[2], items[1], items[0]}
+	cursor := &Cursor{Time: items[3].at, ID: items[3].id, Backward: true}
+
+	page, info := NewPageInfo(fetched, PageRequest{Limit: 2, Cursor: cursor}, pageItemKey)
+
+	if len(page) != 2 || page[0].id != items[1].id || page[1].id != items[2].id {
+		t.Fatalf("unexpected backward page order: %+v", page)
+	}
+	if info.NextCursor == nil || info.PrevCursor == nil {
+		t.Fatalf("expected both cursors, got next=%v prev=%v", info.NextCursor, info.PrevCursor)
+	}
+	prev, _ := DecodeCursor(*info.PrevCursor)
+	if prev.ID != items[1].id || !prev.Backward {
+		t.Errorf("prev cursor %+v, want backward cursor at first item", prev)
+	}
+}
+
+func TestNewPageInfo_LastPage(t *testing.T) {
+	items := pageItems(1)
+	cursor := &Cursor{Time: items[0].at.Add(-time.Hour), ID: uuid.New()}
+
+	_, info := NewPageInfo(items, PageRequest{Limit: 2, Cursor: cursor}, pageItemKey)
+
+	if info.NextCursor != nil {
+		t.Errorf("last page must not have next_cursor")
+	}
+	if info.PrevCursor == nil {
+		t.Errorf("page reached by cursor must have prev_cursor")
+	}
+}
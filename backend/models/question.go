@@ -0,0 +1,34 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SessionQuestion - вопрос участника к сессии (Q&A) с числом голосов
+type SessionQuestion struct {
+	ID         uuid.UUID  `json:"id" db:"id"`
+	SessionID  uuid.UUID  `json:"session_id" db:"session_id"`
+	UserID     *uuid.UUID `json:"user_id,omitempty" db:"user_id"` // NULL, если автор удалил аккаунт
+	AuthorName *string    `json:"author_name,omitempty" db:"author_name"`
+	Body       string     `json:"body" db:"body"`
+	IsAnswered bool       `json:"is_answered" db:"is_answered"`
+	IsPinned   bool       `json:"is_pinned" db:"is_pinned"`
+	AnsweredAt *time.Time `json:"answered_at,omitempty" db:"answered_at"`
+	CreatedAt  time.Time  `json:"created_at" db:"created_at"`
+	Votes      int        `json:"votes" db:"votes"`
+	HasVoted   bool       `json:"has_voted" db:"has_voted"` // Голосовал ли текущий пользователь
+}
+
+// QuestionRequest для создания вопроса
+type QuestionRequest struct {
+	Body string `json:"body" binding:"required,max=1000"`
+}
+
+// QuestionUpdateRequest для организаторов: отметить вопрос отвеченным и/или закрепить.
+// Не переданные поля не меняются.
+type QuestionUpdateRequest struct {
+	IsAnswered *bool `json:"is_answered"`
+	IsPinned   *bool `json:"is_pinned"`
+}
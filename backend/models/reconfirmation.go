@@ -0,0 +1,48 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ReconfirmWindow - сколько времени у участников есть, чтобы подтвердить участие после переноса сессии.
+// Срок не может быть позже нового начала сессии.
+const ReconfirmWindow = 72 * time.Hour
+
+// ReconfirmationStatus - ответ участника на перенос сессии
+type ReconfirmationStatus string
+
+const (
+	ReconfirmationPending   ReconfirmationStatus = "pending"   // Ждет ответа
+	ReconfirmationConfirmed ReconfirmationStatus = "confirmed" // Придет в новое время
+	ReconfirmationDeclined  ReconfirmationStatus = "declined"  // Отказался, место освобождено
+	ReconfirmationExpired   ReconfirmationStatus = "expired"   // Не ответил до срока, участие сохранено
+)
+
+// SessionReconfirmation - запрос подтверждения участия после переноса сессии
+type SessionReconfirmation struct {
+	SessionID        uuid.UUID            `json:"session_id" db:"session_id"`
+	UserID           uuid.UUID            `json:"user_id" db:"user_id"`
+	UserName         string               `json:"user_name,omitempty" db:"user_name"` // Заполняется в списке для организаторов
+	Status           ReconfirmationStatus `json:"status" db:"status"`
+	PreviousDateTime time.Time            `json:"previous_date_time" db:"previous_date_time"`
+	Deadline         time.Time            `json:"deadline" db:"deadline"`
+	RequestedAt      time.Time            `json:"requested_at" db:"requested_at"`
+	RespondedAt      *time.Time           `json:"responded_at,omitempty" db:"responded_at"`
+}
+
+// ReconfirmationRequest - ответ участника: attend=true подтверждает участие, false - отказ
+type ReconfirmationRequest struct {
+	Attend *bool `json:"attend" binding:"required"`
+}
+
+// ReconfirmDeadline возвращает крайний срок ответа на перенос, сделанный в момент now, для сессии,
+// которая теперь начинается в start
+func ReconfirmDeadline(now, start time.Time) time.Time {
+	deadline := now.Add(ReconfirmWindow)
+	if start.Before(deadline) {
+		return start
+	}
+	return deadline
+}
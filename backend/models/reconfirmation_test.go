@@ -0,0 +1,18 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestReconfirmDeadline(t *testing.T) {
+	now := time.Date(2025, 5, 1, 12, 0, 0, 0, time.UTC)
+	later := now.Add(10 * 24 * time.Hour)
+	if got := ReconfirmDeadline(now, later); !got.Equal(now.Add(ReconfirmWindow)) {
+		t.Errorf("deadline for a distant session = %v, want %v", got, now.Add(ReconfirmWindow))
+	}
+	soon := now.Add(5 * time.Hour)
+	if got := ReconfirmDeadline(now, soon); !got.Equal(soon) {
+		t.Errorf("deadline must not be after the new start: got %v, want %v", got, soon)
+	}
+}
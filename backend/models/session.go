@@ -1,9 +1,12 @@
 package models
 
 import (
+	"errors"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 )
 
 // Сессия представляет собой сеанс обмена навыками
@@ -13,23 +16,361 @@ type Session struct {
 	Description     string    `json:"description" db:"description"`
 	Category        string    `json:"category" db:"category"`
 	DateTime        time.Time `json:"date_time" db:"date_time"`
+	EndTime         time.Time `json:"end_time" db:"end_time"`
+	TimeZone        string    `json:"time_zone" db:"time_zone"` // IANA, например "Europe/Moscow"
 	Location        string    `json:"location" db:"location"`
+	Latitude        *float64  `json:"latitude,omitempty" db:"latitude"`
+	Longitude       *float64  `json:"longitude,omitempty" db:"longitude"`
+	VenueAddress    *string   `json:"venue_address,omitempty" db:"venue_address"`
+	IsOnline        bool      `json:"is_online" db:"is_online"` // Вычисляется БД по location
+	// Ссылка на встречу и инструкции для входа видны только организаторам и участникам (HideMeetingDetails)
+	MeetingURL       *string `json:"meeting_url,omitempty" db:"meeting_url"`
+	JoinInstructions *string `json:"join_instructions,omitempty" db:"join_instructions"`
 	MaxParticipants int       `json:"max_participants" db:"max_participants"`
+	Tags            pq.StringArray `json:"tags" db:"tags"`
 	CreatorID       uuid.UUID `json:"creator_id" db:"creator_id"`
+	SeriesID        *uuid.UUID `json:"series_id,omitempty" db:"series_id"`           // Серия, если сессия - занятие повторяющейся серии
+	OriginalStart   *time.Time `json:"original_start,omitempty" db:"original_start"` // Время занятия по правилу серии (RECURRENCE-ID)
+	IsOverride      bool       `json:"is_override" db:"is_override"`                 // Занятие изменено отдельно от серии
+	Status             SessionStatus `json:"status" db:"status"`
+	Visibility         SessionVisibility `json:"visibility" db:"visibility"`
+	RequiresApproval   bool          `json:"requires_approval" db:"requires_approval"` // Присоединение через заявку, одобряемую организаторами
+	CancelledAt        *time.Time    `json:"cancelled_at,omitempty" db:"cancelled_at"`
+	CancellationReason *string       `json:"cancellation_reason,omitempty" db:"cancellation_reason"`
+	Version            int           `json:"version" db:"version"` // Номер версии (см. SessionVersion), он же SEQUENCE события в календарях
+	Hosts              []SessionHost `json:"hosts,omitempty" db:"-"` // Заполняется только в GetByID
+	SearchLanguage     string        `json:"search_language" db:"search_language"` // Конфигурация полнотекстового поиска PostgreSQL
+	SearchVector       string        `json:"-" db:"search_vector"`                 // Генерируется БД из title и description
+	// Заполняются только в результатах полнотекстового поиска (SearchSessions с Query)
+	SearchRank           *float64 `json:"search_rank,omitempty" db:"search_rank"`
+	TitleHighlight       *string  `json:"title_highlight,omitempty" db:"title_highlight"`
+	DescriptionHighlight *string  `json:"description_highlight,omitempty" db:"description_highlight"`
+	// Заполняется только в результатах поиска "рядом" (SearchSessions с Near), км
+	DistanceKm *float64 `json:"distance_km,omitempty" db:"distance_km"`
 	CreatedAt       time.Time `json:"created_at" db:"created_at"`
 	UpdatedAt       time.Time `json:"updated_at" db:"updated_at"`
 }
 
 // SessionRequest для создания/обновления сеансов
 type SessionRequest struct {
-	Title           string    `json:"title" binding:"required"`
-	Description     string    `json:"description"`
-	Category        string    `json:"category" binding:"required"`
-	DateTime        time.Time `json:"date_time" binding:"required"`
-	Location        string    `json:"location" binding:"required"`
-	MaxParticipants int       `json:"max_participants" binding:"required,min=1"`
+	Title           string     `json:"title" binding:"required"`
+	Description     string     `json:"description"`
+	Category        string     `json:"category" binding:"required"`
+	DateTime        time.Time  `json:"date_time" binding:"required"`
+	EndTime         *time.Time `json:"end_time,omitempty"` // Либо end_time, либо duration_minutes
+	DurationMinutes int        `json:"duration_minutes,omitempty" binding:"omitempty,min=1,max=1440"`
+	TimeZone        string     `json:"time_zone,omitempty" binding:"omitempty,timezone"`
+	Location        string     `json:"location" binding:"required"`
+	Latitude        *float64   `json:"latitude,omitempty" binding:"omitempty,min=-90,max=90"` // Вместе с longitude
+	Longitude       *float64   `json:"longitude,omitempty" binding:"omitempty,min=-180,max=180"`
+	VenueAddress    *string    `json:"venue_address,omitempty" binding:"omitempty,max=500"`
+	ClearLocation   bool       `json:"clear_location,omitempty"` // Только при изменении: удалить координаты и адрес места
+	MeetingURL       *string   `json:"meeting_url,omitempty" binding:"omitempty,url,max=2048"`
+	JoinInstructions *string   `json:"join_instructions,omitempty" binding:"omitempty,max=2000"`
+	MaxParticipants int        `json:"max_participants" binding:"required,min=1"`
+	Tags            []string   `json:"tags,omitempty" binding:"omitempty,max=20,dive,max=50"`
+	SearchLanguage  string     `json:"search_language,omitempty" binding:"omitempty,oneof=russian english simple"`
+	Visibility      SessionVisibility `json:"visibility,omitempty" binding:"omitempty,oneof=public unlisted invite_only"`
+	RequiresApproval *bool     `json:"requires_approval,omitempty"`
+	Draft           bool       `json:"draft,omitempty"` // Только при создании: сессия видна лишь создателю до публикации
+	AllowConflicts  bool       `json:"allow_conflicts,omitempty"` // Сохранить, даже если время пересекается с другими сессиями пользователя
+	CapacityPolicy  CapacityPolicy `json:"capacity_policy,omitempty" binding:"omitempty,oneof=reject waitlist"` // Только при изменении: если max_participants меньше числа участников
 }
 
+// CapacityPolicy - что делать, если новая вместимость меньше числа уже присоединившихся участников
+type CapacityPolicy string
+
+const (
+	CapacityPolicyReject   CapacityPolicy = "reject"   // Отклонить изменение (по умолчанию)
+	CapacityPolicyWaitlist CapacityPolicy = "waitlist" // Перевести последних присоединившихся в начало очереди ожидания
+)
+
+// SeatChanges - участники, которых изменение вместимости перевело в очередь ожидания или из нее
+type SeatChanges struct {
+	Waitlisted []uuid.UUID // Не поместились в новую вместимость
+	Promoted   []uuid.UUID // Заняли освободившиеся места из очереди
+}
+
+// SessionStatus - этап жизненного цикла сессии
+type SessionStatus string
+
+const (
+	SessionStatusDraft     SessionStatus = "draft"     // Черновик, виден только создателю
+	SessionStatusPublished SessionStatus = "published" // Опубликована, к ней можно присоединиться
+	SessionStatusCancelled SessionStatus = "cancelled" // Отменена создателем, запись и участники сохраняются
+	SessionStatusCompleted SessionStatus = "completed" // Завершилась (проставляется фоновой задачей)
+)
+
+// sessionStatusTransitions - допустимые переходы между статусами.
+// cancelled и completed - конечные состояния.
+var sessionStatusTransitions = map[SessionStatus][]SessionStatus{
+	SessionStatusDraft:     {SessionStatusPublished},
+	SessionStatusPublished: {SessionStatusCancelled, SessionStatusCompleted},
+}
+
+// CanTransitionTo проверяет, допустим ли переход из статуса s в next
+func (s SessionStatus) CanTransitionTo(next SessionStatus) bool {
+	for _, allowed := range sessionStatusTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+// IsFinal возвращает true для статусов, после которых сессию нельзя изменять
+func (s SessionStatus) IsFinal() bool {
+	return len(sessionStatusTransitions[s]) == 0
+}
+
+// StatusesBefore возвращает статусы, из которых разрешен переход в next
+func StatusesBefore(next SessionStatus) []string {
+	var statuses []string
+	for from := range sessionStatusTransitions {
+		if from.CanTransitionTo(next) {
+			statuses = append(statuses, string(from))
+		}
+	}
+	return statuses
+}
+
+// SessionVisibility - кому видна сессия и кто может к ней присоединиться
+type SessionVisibility string
+
+const (
+	VisibilityPublic     SessionVisibility = "public"      // Видна в списках, поиске и рекомендациях
+	VisibilityUnlisted   SessionVisibility = "unlisted"    // Доступна только по прямой ссылке
+	VisibilityInviteOnly SessionVisibility = "invite_only" // Видна и доступна только по приглашению
+)
+
+// SessionCancelRequest для отмены сессии
+type SessionCancelRequest struct {
+	Reason string `json:"reason" binding:"max=500"`
+}
+
+// DefaultSessionDuration - длительность сессии, если не указаны ни end_time, ни duration_minutes
+const DefaultSessionDuration = 90 * time.Minute
+
+// DefaultTimeZone - часовой пояс сессии по умолчанию
+const DefaultTimeZone = "UTC"
+
+var (
+	ErrEndTimeAndDuration = errors.New("specify either end_time or duration_minutes, not both")
+	ErrEndBeforeStart     = errors.New("end_time must be after date_time")
+	ErrIncompleteCoordinates = errors.New("latitude and longitude must be specified together")
+)
+
+// Normalize проверяет согласованность времени сессии и заполняет значения по умолчанию:
+// после вызова EndTime и DurationMinutes заданы оба, TimeZone не пустой.
+// Вызывается после binding, т.к. валидатор не умеет сравнивать время с date_time.
+func (r *SessionRequest) Normalize() error {
+	if r.EndTime != nil && r.DurationMinutes > 0 {
+		return ErrEndTimeAndDuration
+	}
+	if r.EndTime == nil {
+		duration := DefaultSessionDuration
+		if r.DurationMinutes > 0 {
+			duration = time.Duration(r.DurationMinutes) * time.Minute
+		}
+		end := r.DateTime.Add(duration)
+		r.EndTime = &end
+	}
+	if !r.EndTime.After(r.DateTime) {
+		return ErrEndBeforeStart
+	}
+	if (r.Latitude == nil) != (r.Longitude == nil) {
+		return ErrIncompleteCoordinates
+	}
+	r.DurationMinutes = int(r.EndTime.Sub(r.DateTime) / time.Minute)
+	if r.TimeZone == "" {
+		r.TimeZone = DefaultTimeZone
+	}
+	r.Tags = NormalizeTags(r.Tags)
+	r.MeetingURL = trimToNil(r.MeetingURL)
+	r.JoinInstructions = trimToNil(r.JoinInstructions)
+	if r.SearchLanguage == "" {
+		r.SearchLanguage = DefaultSearchLanguage
+	}
+	if r.Visibility == "" {
+		r.Visibility = VisibilityPublic
+	}
+	if r.RequiresApproval == nil {
+		requiresApproval := false
+		r.RequiresApproval = &requiresApproval
+	}
+	return nil
+}
+
+// trimToNil убирает пробелы по краям строки; пустая строка становится nil (NULL в БД),
+// чтобы при обновлении поле можно было очистить, передав ""
+func trimToNil(value *string) *string {
+	if value == nil {
+		return nil
+	}
+	trimmed := strings.TrimSpace(*value)
+	if trimmed == "" {
+		return nil
+	}
+	return &trimmed
+}
+
+// SearchLanguages - конфигурации полнотекстового поиска PostgreSQL, которые можно выбрать для сессии
+var SearchLanguages = []string{"russian", "english", "simple"}
+
+// DefaultSearchLanguage - конфигурация поиска по умолчанию. Конфигурация russian
+// обрабатывает и русские словоформы, и английские (слова латиницей стеммятся english_stem).
+const DefaultSearchLanguage = "russian"
+
+// NormalizeTags приводит теги к нижнему регистру, убирает пробелы по краям, пустые значения и дубликаты.
+// Всегда возвращает не-nil срез, т.к. колонка tags - NOT NULL.
+func NormalizeTags(tags []string) []string {
+	normalized := make([]string, 0, len(tags))
+	seen := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		normalized = append(normalized, tag)
+	}
+	return normalized
+}
+
+// HideMeetingDetails убирает ссылку на встречу и инструкции для входа из сессии,
+// которую видит пользователь, не являющийся ее организатором или участником
+func (s *Session) HideMeetingDetails() {
+	s.MeetingURL = nil
+	s.JoinInstructions = nil
+}
+
+// MeetingDetailsText возвращает ссылку на встречу и инструкции для входа в виде текста
+// для напоминаний и описания события ICS. Пустая строка, если ни то ни другое не задано.
+func (s *Session) MeetingDetailsText() string {
+	return MeetingDetailsText(s.MeetingURL, s.JoinInstructions)
+}
+
+// MeetingDetailsText форматирует ссылку на встречу и инструкции для входа (сессии или серии)
+func MeetingDetailsText(meetingURL, joinInstructions *string) string {
+	var lines []string
+	if meetingURL != nil && *meetingURL != "" {
+		lines = append(lines, "Meeting link: "+*meetingURL)
+	}
+	if joinInstructions != nil && *joinInstructions != "" {
+		lines = append(lines, "How to join: "+*joinInstructions)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// TimeLocation возвращает часовой пояс запроса (UTC, если он не задан)
+func (r *SessionRequest) TimeLocation() *time.Location {
+	return LoadLocation(r.TimeZone)
+}
+
+// TimeLocation возвращает часовой пояс сессии (UTC, если он неизвестен)
+func (s *Session) TimeLocation() *time.Location {
+	return LoadLocation(s.TimeZone)
+}
+
+// DisplayTimeFormat - формат времени сессии в текстах уведомлений
+const DisplayTimeFormat = "Jan 2, 2006 at 3:04 PM MST"
+
+// TimeLocationFor возвращает часовой пояс, в котором время сессии показывается пользователю:
+// часовой пояс из профиля, а если он не указан - часовой пояс сессии
+func (s *Session) TimeLocationFor(recipient *User) *time.Location {
+	if recipient != nil && recipient.TimeZone != nil && *recipient.TimeZone != "" {
+		return LoadLocation(*recipient.TimeZone)
+	}
+	return s.TimeLocation()
+}
+
+// LoadLocation возвращает часовой пояс по имени IANA. Пустое или неизвестное имя дает UTC:
+// значения проверяются при записи, а отображение времени не должно ломаться из-за них.
+func LoadLocation(name string) *time.Location {
+	if name == "" {
+		return time.UTC
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// SessionSeries - повторяющаяся сессия, заданная правилом RFC 5545 RRULE.
+// Title, Description и т.д. - шаблон, из которого создаются занятия серии.
+type SessionSeries struct {
+	ID              uuid.UUID `json:"id" db:"id"`
+	CreatorID       uuid.UUID `json:"creator_id" db:"creator_id"`
+	RRule           string    `json:"rrule" db:"rrule"`
+	DTStart         time.Time `json:"dtstart" db:"dtstart"`
+	DurationMinutes int       `json:"duration_minutes" db:"duration_minutes"`
+	TimeZone        string    `json:"time_zone" db:"time_zone"` // Часовой пояс, в котором разворачивается RRULE
+	Title           string    `json:"title" db:"title"`
+	Description     string    `json:"description" db:"description"`
+	Category        string    `json:"category" db:"category"`
+	Location        string    `json:"location" db:"location"`
+	MaxParticipants int       `json:"max_participants" db:"max_participants"`
+	Tags            pq.StringArray `json:"tags" db:"tags"`
+	Latitude        *float64  `json:"latitude,omitempty" db:"latitude"`
+	Longitude       *float64  `json:"longitude,omitempty" db:"longitude"`
+	VenueAddress    *string   `json:"venue_address,omitempty" db:"venue_address"`
+	MeetingURL       *string  `json:"-" db:"meeting_url"` // Копируется в занятия, наружу отдается только через них
+	JoinInstructions *string  `json:"-" db:"join_instructions"`
+	Visibility      SessionVisibility `json:"visibility" db:"visibility"`
+	RequiresApproval bool     `json:"requires_approval" db:"requires_approval"`
+	CreatedAt       time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// Duration возвращает длительность одного занятия серии
+func (s *SessionSeries) Duration() time.Duration {
+	return time.Duration(s.DurationMinutes) * time.Minute
+}
+
+// TimeLocation возвращает часовой пояс серии
+func (s *SessionSeries) TimeLocation() *time.Location {
+	return LoadLocation(s.TimeZone)
+}
+
+// SessionSeriesRequest для создания серии. DateTime из SessionRequest - начало первого занятия (DTSTART).
+type SessionSeriesRequest struct {
+	SessionRequest
+	RRule string `json:"rrule" binding:"required"`
+}
+
+// Область применения изменения занятия серии
+const (
+	SeriesScopeThis      = "this"      // Только это занятие
+	SeriesScopeFollowing = "following" // Это и все последующие занятия
+)
+
+// ParticipationStatus - результат попытки присоединиться к сессии
+type ParticipationStatus string
+
+const (
+	ParticipationStatusJoined     ParticipationStatus = "joined"
+	ParticipationStatusWaitlisted ParticipationStatus = "waitlisted"
+	ParticipationStatusPending    ParticipationStatus = "pending" // Заявка ждет одобрения организаторов
+)
+
+// JoinResult описывает, куда попал пользователь после JoinSession
+type JoinResult struct {
+	Status           ParticipationStatus `json:"status"`
+	WaitlistPosition int                 `json:"waitlist_position,omitempty"` // Позиция в очереди (с 1), только для waitlisted
+}
+
+// JoinSessionRequest - необязательное тело запроса на присоединение к сессии
+type JoinSessionRequest struct {
+	InviteCode string `json:"invite_code"` // Обязателен для сессий invite_only
+	Message    string `json:"message" binding:"max=500"` // Сообщение организаторам для сессий с одобрением заявок
+	AllowConflicts bool `json:"allow_conflicts"` // Присоединиться, даже если время пересекается с другими сессиями пользователя
+}
+
+// JoinSeriesRequest - необязательное тело запроса на присоединение ко всем предстоящим занятиям серии
+type JoinSeriesRequest struct {
+	AllowConflicts bool `json:"allow_conflicts"` // Присоединиться, даже если занятия пересекаются с другими сессиями пользователя
+}
 
 // SessionSearchFilters - структура для параметров поиска
 type SessionSearchFilters struct {
@@ -39,11 +380,64 @@ type SessionSearchFilters struct {
     DateFrom        *time.Time 
     DateTo          *time.Time
     MinRating       float64   // Для фильтрации по среднему рейтингу сессии
-    CreatorID       *uuid.UUID
+    HostID          *uuid.UUID // Только сессии, где пользователь - организатор с любой ролью
     Location        string
     AvailableSlots  bool      // Только сессии, где есть свободные места
     ExcludePast     bool      // Исключать прошедшие сессии (по умолчанию true)
+    Statuses        []SessionStatus // Пусто - только опубликованные
+    Visibilities    []SessionVisibility // Пусто - только публичные
+    Near            *GeoFilter      // Поиск "рядом": сортировка по расстоянию
     // Пагинация
     Limit           int
     Offset          int
-}
\ No newline at end of file
+}
+
+// Applied возвращает фильтры поиска, которые действительно участвуют в запросе, в виде
+// "имя query-параметра" -> значение. Пагинация и статусы не включаются.
+func (f *SessionSearchFilters) Applied() map[string]interface{} {
+    applied := map[string]interface{}{}
+    if f.Query != "" {
+        applied["q"] = f.Query
+    }
+    if f.Category != "" {
+        applied["category"] = f.Category
+    }
+    if f.Skill != "" {
+        applied["skill"] = f.Skill
+    }
+    if f.MinRating > 0 {
+        applied["min_rating"] = f.MinRating
+    }
+    if f.Location != "" {
+        applied["location"] = f.Location
+    }
+    if f.AvailableSlots {
+        applied["available_slots"] = true
+    }
+    if f.DateFrom != nil {
+        applied["date_from"] = *f.DateFrom
+    }
+    if f.DateTo != nil {
+        applied["date_to"] = *f.DateTo
+    }
+    if f.ExcludePast {
+        applied["exclude_past"] = true
+    }
+    if f.Near != nil {
+        applied["near"] = *f.Near
+    }
+    return applied
+}
+
+// GeoFilter - точка и радиус поиска сессий "рядом"
+type GeoFilter struct {
+    Lat      float64 `json:"lat"`
+    Lng      float64 `json:"lng"`
+    RadiusKm float64 `json:"radius_km"`
+}
+
+// Ограничения радиуса поиска "рядом", км
+const (
+    DefaultSearchRadiusKm = 25
+    MaxSearchRadiusKm     = 500
+)
\ No newline at end of file
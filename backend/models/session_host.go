@@ -0,0 +1,77 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// HostRole - роль организатора сессии
+type HostRole string
+
+const (
+	HostRoleOwner     HostRole = "owner"     // Создатель сессии, единственный на сессию
+	HostRoleCoHost    HostRole = "co-host"   // Соорганизатор: редактирует, публикует и отменяет сессию
+	HostRoleAssistant HostRole = "assistant" // Помощник: видит черновики и участников, отмечает присутствие и ведет вопросы, но сессию не меняет
+)
+
+// HostStatus - состояние приглашения организатора
+type HostStatus string
+
+const (
+	HostStatusInvited  HostStatus = "invited"
+	HostStatusAccepted HostStatus = "accepted"
+)
+
+// SessionPermission - действие над сессией, доступное организаторам
+type SessionPermission string
+
+const (
+	PermissionViewDraft   SessionPermission = "view_draft"
+	PermissionEdit        SessionPermission = "edit"
+	PermissionPublish     SessionPermission = "publish"
+	PermissionCancel      SessionPermission = "cancel"
+	PermissionDelete      SessionPermission = "delete"
+	PermissionManageHosts SessionPermission = "manage_hosts"
+	PermissionManageInvites SessionPermission = "manage_invites"
+	PermissionReviewRequests SessionPermission = "review_requests"
+	PermissionCheckIn        SessionPermission = "check_in" // Отметка присутствия и просмотр посещаемости участников
+	PermissionModerateQA     SessionPermission = "moderate_qa" // Отметка вопросов отвеченными, закрепление и удаление вопросов
+	PermissionViewHistory    SessionPermission = "view_history"    // Просмотр истории изменений сессии
+	PermissionRestoreVersion SessionPermission = "restore_version" // Восстановление прежней версии сессии
+)
+
+var hostRolePermissions = map[HostRole][]SessionPermission{
+	HostRoleOwner:     {PermissionViewDraft, PermissionEdit, PermissionPublish, PermissionCancel, PermissionDelete, PermissionManageHosts, PermissionManageInvites, PermissionReviewRequests, PermissionCheckIn, PermissionModerateQA, PermissionViewHistory, PermissionRestoreVersion},
+	HostRoleCoHost:    {PermissionViewDraft, PermissionEdit, PermissionPublish, PermissionCancel, PermissionManageInvites, PermissionReviewRequests, PermissionCheckIn, PermissionModerateQA, PermissionViewHistory},
+	HostRoleAssistant: {PermissionViewDraft, PermissionCheckIn, PermissionModerateQA},
+}
+
+// Can проверяет, разрешено ли роли действие permission. Пустая роль (не организатор) не может ничего.
+func (r HostRole) Can(permission SessionPermission) bool {
+	for _, p := range hostRolePermissions[r] {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// SessionHost - организатор сессии вместе с именем и email пользователя
+type SessionHost struct {
+	SessionID  uuid.UUID  `json:"session_id" db:"session_id"`
+	UserID     uuid.UUID  `json:"user_id" db:"user_id"`
+	Role       HostRole   `json:"role" db:"role"`
+	Status     HostStatus `json:"status" db:"status"`
+	InvitedBy  *uuid.UUID `json:"invited_by,omitempty" db:"invited_by"`
+	InvitedAt  time.Time  `json:"invited_at" db:"invited_at"`
+	AcceptedAt *time.Time `json:"accepted_at,omitempty" db:"accepted_at"`
+	Name       string     `json:"name" db:"name"`
+	Email      string     `json:"email" db:"email"`
+}
+
+// HostInviteRequest для приглашения соорганизатора. Владельца назначить нельзя.
+type HostInviteRequest struct {
+	UserID uuid.UUID `json:"user_id" binding:"required"`
+	Role   HostRole  `json:"role" binding:"required,oneof=co-host assistant"`
+}
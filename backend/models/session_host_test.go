@@ -0,0 +1,35 @@
+package models
+
+import "testing"
+
+func TestHostRoleCan(t *testing.T) {
+	cases := []struct {
+		role       HostRole
+		permission SessionPermission
+		want       bool
+	}{
+		{HostRoleOwner, PermissionManageHosts, true},
+		{HostRoleOwner, PermissionDelete, true},
+		{HostRoleCoHost, PermissionEdit, true},
+		{HostRoleCoHost, PermissionCancel, true},
+		{HostRoleCoHost, PermissionDelete, false},
+		{HostRoleCoHost, PermissionManageHosts, false},
+		{HostRoleCoHost, PermissionManageInvites, true},
+		{HostRoleAssistant, PermissionManageInvites, false},
+		{HostRoleCoHost, PermissionReviewRequests, true},
+		{HostRoleAssistant, PermissionReviewRequests, false},
+		{HostRoleAssistant, PermissionViewDraft, true},
+		{HostRoleAssistant, PermissionCheckIn, true},
+		{HostRoleAssistant, PermissionModerateQA, true},
+		{HostRoleAssistant, PermissionEdit, false},
+		{HostRoleCoHost, PermissionViewHistory, true},
+		{HostRoleCoHost, PermissionRestoreVersion, false},
+		{HostRoleOwner, PermissionRestoreVersion, true},
+		{"", PermissionViewDraft, false},
+	}
+	for _, tc := range cases {
+		if got := tc.role.Can(tc.permission); got != tc.want {
+			t.Errorf("%q.Can(%s) = %v, want %v", tc.role, tc.permission, got, tc.want)
+		}
+	}
+}
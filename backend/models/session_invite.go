@@ -0,0 +1,38 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SessionInvite - приглашение (код) для присоединения к сессии с видимостью invite_only.
+// Коды действуют и для unlisted сессий, но там они нужны только как ссылка.
+type SessionInvite struct {
+	ID        uuid.UUID  `json:"id" db:"id"`
+	SessionID uuid.UUID  `json:"session_id" db:"session_id"`
+	Code      string     `json:"code" db:"code"`
+	CreatedBy *uuid.UUID `json:"created_by,omitempty" db:"created_by"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty" db:"expires_at"`
+	MaxUses   *int       `json:"max_uses,omitempty" db:"max_uses"` // nil - без ограничения
+	UseCount  int        `json:"use_count" db:"use_count"`
+	RevokedAt *time.Time `json:"revoked_at,omitempty" db:"revoked_at"`
+	CreatedAt time.Time  `json:"created_at" db:"created_at"`
+}
+
+// IsActive сообщает, можно ли еще воспользоваться приглашением в момент now
+func (i *SessionInvite) IsActive(now time.Time) bool {
+	if i.RevokedAt != nil {
+		return false
+	}
+	if i.ExpiresAt != nil && !now.Before(*i.ExpiresAt) {
+		return false
+	}
+	return i.MaxUses == nil || i.UseCount < *i.MaxUses
+}
+
+// SessionInviteRequest для создания приглашения. Оба ограничения необязательны.
+type SessionInviteRequest struct {
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	MaxUses   *int       `json:"max_uses,omitempty" binding:"omitempty,min=1,max=10000"`
+}
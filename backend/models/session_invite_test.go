@@ -0,0 +1,30 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSessionInviteIsActive(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	past, future := now.Add(-time.Hour), now.Add(time.Hour)
+	two := 2
+
+	cases := []struct {
+		name   string
+		invite SessionInvite
+		want   bool
+	}{
+		{"unlimited", SessionInvite{}, true},
+		{"not expired", SessionInvite{ExpiresAt: &future}, true},
+		{"expired", SessionInvite{ExpiresAt: &past}, false},
+		{"uses left", SessionInvite{MaxUses: &two, UseCount: 1}, true},
+		{"used up", SessionInvite{MaxUses: &two, UseCount: 2}, false},
+		{"revoked", SessionInvite{RevokedAt: &past}, false},
+	}
+	for _, tc := range cases {
+		if got := tc.invite.IsActive(now); got != tc.want {
+			t.Errorf("%s: IsActive() = %v, want %v", tc.name, got, tc.want)
+		}
+	}
+}
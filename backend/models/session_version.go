@@ -0,0 +1,31 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SessionVersion - запись истории изменений сессии. Версии нумеруются с 1 и записываются
+// триггером БД при каждом изменении отслеживаемых полей (см. миграцию session_versions).
+type SessionVersion struct {
+	ID            uuid.UUID       `json:"id" db:"id"`
+	SessionID     uuid.UUID       `json:"session_id" db:"session_id"`
+	Version       int             `json:"version" db:"version"`
+	ChangedBy     *uuid.UUID      `json:"changed_by,omitempty" db:"changed_by"` // nil - изменение системой
+	ChangedByName *string         `json:"changed_by_name,omitempty" db:"changed_by_name"`
+	Changes       json.RawMessage `json:"changes" db:"changes"`   // {"поле": {"old": ..., "new": ...}}, пусто для первой версии
+	Snapshot      json.RawMessage `json:"snapshot" db:"snapshot"` // Значения полей после изменения
+	CreatedAt     time.Time       `json:"created_at" db:"created_at"`
+}
+
+// RestoreRequest возвращает запрос на изменение сессии, восстанавливающий поля этой версии.
+// Статус и причина отмены не восстанавливаются: они меняются только через публикацию и отмену.
+func (v *SessionVersion) RestoreRequest() (SessionRequest, error) {
+	var req SessionRequest
+	if err := json.Unmarshal(v.Snapshot, &req); err != nil {
+		return SessionRequest{}, err
+	}
+	return req, nil
+}
@@ -0,0 +1,37 @@
+package models
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestSessionVersionRestoreRequest(t *testing.T) {
+	// Снимок в том виде, в каком его строит session_version_snapshot в PostgreSQL
+	v := SessionVersion{Snapshot: json.RawMessage(`{
+		"title": "Go basics", "description": "", "category": "programming",
+		"date_time": "2025-03-10T15:00:00+00:00", "end_time": "2025-03-10T17:00:00+00:00",
+		"time_zone": "Europe/Moscow", "location": "online", "latitude": null, "longitude": null,
+		"venue_address": null, "meeting_url": "https://meet.example.com/go", "join_instructions": null,
+		"max_participants": 8, "tags": ["go"], "search_language": "english", "visibility": "unlisted",
+		"requires_approval": true, "status": "cancelled", "cancellation_reason": "ill"
+	}`)}
+
+	req, err := v.RestoreRequest()
+	if err != nil {
+		t.Fatalf("RestoreRequest() error = %v", err)
+	}
+	if err := req.Normalize(); err != nil {
+		t.Fatalf("Normalize() error = %v", err)
+	}
+	start := time.Date(2025, 3, 10, 15, 0, 0, 0, time.UTC)
+	if req.Title != "Go basics" || !req.DateTime.Equal(start) || req.DurationMinutes != 120 || req.MaxParticipants != 8 {
+		t.Errorf("unexpected request: %+v", req)
+	}
+	if req.Visibility != VisibilityUnlisted || req.RequiresApproval == nil || !*req.RequiresApproval || len(req.Tags) != 1 {
+		t.Errorf("visibility = %s, requires_approval = %v, tags = %v", req.Visibility, req.RequiresApproval, req.Tags)
+	}
+	if req.MeetingURL == nil || *req.MeetingURL != "https://meet.example.com/go" || req.VenueAddress != nil {
+		t.Errorf("meeting_url = %v, venue_address = %v", req.MeetingURL, req.VenueAddress)
+	}
+}
@@ -0,0 +1,152 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+const (
+	MaxUserSkills    = 30 // Сколько навыков можно указать в профиле
+	MaxSkillLength   = 50 // Длина названия навыка и синонима (VARCHAR(50))
+	MaxSkillAliases  = 20
+	MaxSkillSuggests = 25
+)
+
+var (
+	ErrTooManySkills       = fmt.Errorf("no more than %d skills are allowed", MaxUserSkills)
+	ErrSkillTooLong        = fmt.Errorf("skill names must be at most %d characters", MaxSkillLength)
+	ErrEmptySkillName      = errors.New("skill name must not be empty")
+	ErrMergeIntoItself     = errors.New("a skill cannot be merged into itself")
+	ErrAliasMatchesName    = errors.New("an alias must differ from the skill name")
+	ErrTooManySkillAliases = fmt.Errorf("no more than %d aliases are allowed", MaxSkillAliases)
+)
+
+// Skill - канонический навык. Пользователи, указавшие название или любой из синонимов,
+// получают в профиле каноническое название.
+type Skill struct {
+	ID        uuid.UUID      `json:"id" db:"id"`
+	Name      string         `json:"name" db:"name"`
+	Aliases   pq.StringArray `json:"aliases" db:"aliases"`
+	UserCount int            `json:"user_count" db:"user_count"` // Сколько пользователей указали навык
+	CreatedAt time.Time      `json:"created_at" db:"created_at"`
+}
+
+// SkillRequest для изменения названия и синонимов навыка администратором
+type SkillRequest struct {
+	Name    string   `json:"name" binding:"required"`
+	Aliases []string `json:"aliases"`
+}
+
+// SkillMergeRequest - объединение дубликатов: навыки SourceIDs заменяются навыком TargetID
+// у пользователей, в тегах сессий, серий и шаблонов, их названия и синонимы становятся синонимами TargetID
+type SkillMergeRequest struct {
+	TargetID  uuid.UUID   `json:"target_id" binding:"required"`
+	SourceIDs []uuid.UUID `json:"source_ids" binding:"required,min=1,max=50"`
+}
+
+// SkillMergeResult - итог объединения навыков
+type SkillMergeResult struct {
+	Skill     Skill `json:"skill"` // Навык после объединения
+	Merged    int   `json:"merged"`
+	Users     int64 `json:"users"`
+	Sessions  int64 `json:"sessions"`
+	Series    int64 `json:"series"`
+	Templates int64 `json:"templates"`
+}
+
+// NormalizeSkillName убирает пробелы по краям и схлопывает пробелы внутри названия
+func NormalizeSkillName(name string) string {
+	return strings.Join(strings.Fields(name), " ")
+}
+
+// SkillKey - ключ сравнения навыков: нормализованное название в нижнем регистре
+func SkillKey(name string) string {
+	return strings.ToLower(NormalizeSkillName(name))
+}
+
+// NormalizeSkills нормализует навыки из профиля: убирает пустые значения и дубликаты без учета регистра,
+// проверяет длину и количество. Всегда возвращает не-nil срез.
+func NormalizeSkills(skills []string) ([]string, error) {
+	normalized := make([]string, 0, len(skills))
+	seen := make(map[string]bool, len(skills))
+	for _, skill := range skills {
+		skill = NormalizeSkillName(skill)
+		key := strings.ToLower(skill)
+		if skill == "" || seen[key] {
+			continue
+		}
+		if utf8.RuneCountInString(skill) > MaxSkillLength {
+			return nil, ErrSkillTooLong
+		}
+		seen[key] = true
+		normalized = append(normalized, skill)
+	}
+	if len(normalized) > MaxUserSkills {
+		return nil, ErrTooManySkills
+	}
+	return normalized, nil
+}
+
+// SkillNames возвращает названия навыков в том же порядке
+func SkillNames(skills []Skill) []string {
+	names := make([]string, 0, len(skills))
+	for _, skill := range skills {
+		names = append(names, skill.Name)
+	}
+	return names
+}
+
+// Normalize приводит название и синонимы к каноническому виду; синонимы хранятся в нижнем регистре
+func (r *SkillRequest) Normalize() error {
+	r.Name = NormalizeSkillName(r.Name)
+	if r.Name == "" {
+		return ErrEmptySkillName
+	}
+	if utf8.RuneCountInString(r.Name) > MaxSkillLength {
+		return ErrSkillTooLong
+	}
+	aliases := make([]string, 0, len(r.Aliases))
+	seen := make(map[string]bool, len(r.Aliases))
+	for _, alias := range r.Aliases {
+		alias = SkillKey(alias)
+		if alias == "" || seen[alias] {
+			continue
+		}
+		if alias == strings.ToLower(r.Name) {
+			return ErrAliasMatchesName
+		}
+		if utf8.RuneCountInString(alias) > MaxSkillLength {
+			return ErrSkillTooLong
+		}
+		seen[alias] = true
+		aliases = append(aliases, alias)
+	}
+	if len(aliases) > MaxSkillAliases {
+		return ErrTooManySkillAliases
+	}
+	r.Aliases = aliases
+	return nil
+}
+
+// Normalize убирает повторы среди объединяемых навыков и проверяет, что цель не входит в их число
+func (r *SkillMergeRequest) Normalize() error {
+	sources := make([]uuid.UUID, 0, len(r.SourceIDs))
+	seen := make(map[uuid.UUID]bool, len(r.SourceIDs))
+	for _, id := range r.SourceIDs {
+		if id == r.TargetID {
+			return ErrMergeIntoItself
+		}
+		if !seen[id] {
+			seen[id] = true
+			sources = append(sources, id)
+		}
+	}
+	r.SourceIDs = sources
+	return nil
+}
@@ -0,0 +1,62 @@
+package models
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestNormalizeSkills(t *testing.T) {
+	got, err := NormalizeSkills([]string{"  Go ", "go", "Machine   learning", "", "GoLang"})
+	if err != nil {
+		t.Fatalf("NormalizeSkills() error = %v", err)
+	}
+	want := []string{"Go", "Machine learning", "GoLang"}
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("NormalizeSkills() = %q, want %q", got, want)
+	}
+	if empty, err := NormalizeSkills(nil); err != nil || empty == nil || len(empty) != 0 {
+		t.Errorf("NormalizeSkills(nil) = %#v, %v; want empty slice", empty, err)
+	}
+
+	if _, err := NormalizeSkills([]string{strings.Repeat("я", MaxSkillLength+1)}); err != ErrSkillTooLong {
+		t.Errorf("long skill error = %v, want %v", err, ErrSkillTooLong)
+	}
+	many := make([]string, MaxUserSkills+1)
+	for i := range many {
+		many[i] = strings.Repeat("a", i+1)
+	}
+	if _, err := NormalizeSkills(many); err != ErrTooManySkills {
+		t.Errorf("too many skills error = %v, want %v", err, ErrTooManySkills)
+	}
+}
+
+func TestSkillRequestNormalize(t *testing.T) {
+	req := SkillRequest{Name: "  Go ", Aliases: []string{"GoLang", " golang ", "", "Go  Lang"}}
+	if err := req.Normalize(); err != nil {
+		t.Fatalf("Normalize() error = %v", err)
+	}
+	if req.Name != "Go" || strings.Join(req.Aliases, "|") != "golang|go lang" {
+		t.Errorf("unexpected normalized request: %+v", req)
+	}
+
+	if err := (&SkillRequest{Name: "Go", Aliases: []string{"GO"}}).Normalize(); err != ErrAliasMatchesName {
+		t.Errorf("alias equal to name error = %v, want %v", err, ErrAliasMatchesName)
+	}
+	if err := (&SkillRequest{Name: "   "}).Normalize(); err != ErrEmptySkillName {
+		t.Errorf("empty name error = %v, want %v", err, ErrEmptySkillName)
+	}
+}
+
+func TestSkillMergeRequestNormalize(t *testing.T) {
+	target, source := uuid.New(), uuid.New()
+	req := SkillMergeRequest{TargetID: target, SourceIDs: []uuid.UUID{source, source}}
+	if err := req.Normalize(); err != nil || len(req.SourceIDs) != 1 {
+		t.Errorf("Normalize() = %v, sources %v; want one source", err, req.SourceIDs)
+	}
+	req = SkillMergeRequest{TargetID: target, SourceIDs: []uuid.UUID{source, target}}
+	if err := req.Normalize(); err != ErrMergeIntoItself {
+		t.Errorf("merge into itself error = %v, want %v", err, ErrMergeIntoItself)
+	}
+}
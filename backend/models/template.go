@@ -0,0 +1,158 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// SessionTemplate - сохраненный шаблон сессии пользователя: все, кроме даты.
+// Ссылка на встречу и инструкции для входа в шаблон не попадают, т.к. шаблоном можно поделиться.
+type SessionTemplate struct {
+	ID               uuid.UUID         `json:"id" db:"id"`
+	OwnerID          uuid.UUID         `json:"owner_id" db:"owner_id"`
+	Name             string            `json:"name" db:"name"`
+	Title            string            `json:"title" db:"title"`
+	Description      string            `json:"description" db:"description"`
+	Category         string            `json:"category" db:"category"`
+	DurationMinutes  int               `json:"duration_minutes" db:"duration_minutes"`
+	TimeZone         string            `json:"time_zone" db:"time_zone"`
+	Location         string            `json:"location" db:"location"`
+	Latitude         *float64          `json:"latitude,omitempty" db:"latitude"`
+	Longitude        *float64          `json:"longitude,omitempty" db:"longitude"`
+	VenueAddress     *string           `json:"venue_address,omitempty" db:"venue_address"`
+	MaxParticipants  int               `json:"max_participants" db:"max_participants"`
+	Tags             pq.StringArray    `json:"tags" db:"tags"`
+	SearchLanguage   string            `json:"search_language" db:"search_language"`
+	Visibility       SessionVisibility `json:"visibility" db:"visibility"`
+	RequiresApproval bool              `json:"requires_approval" db:"requires_approval"`
+	IsShared         bool              `json:"is_shared" db:"is_shared"` // Шаблон чужой и доступен текущему пользователю через шаринг
+	CreatedAt        time.Time         `json:"created_at" db:"created_at"`
+	UpdatedAt        time.Time         `json:"updated_at" db:"updated_at"`
+}
+
+// SessionTemplateRequest для создания/обновления шаблона. Правила полей - как у SessionRequest.
+type SessionTemplateRequest struct {
+	Name             string            `json:"name" binding:"required,max=100"`
+	Title            string            `json:"title" binding:"required"`
+	Description      string            `json:"description"`
+	Category         string            `json:"category" binding:"required"`
+	DurationMinutes  int               `json:"duration_minutes,omitempty" binding:"omitempty,min=1,max=1440"`
+	TimeZone         string            `json:"time_zone,omitempty" binding:"omitempty,timezone"`
+	Location         string            `json:"location" binding:"required"`
+	Latitude         *float64          `json:"latitude,omitempty" binding:"omitempty,min=-90,max=90"`
+	Longitude        *float64          `json:"longitude,omitempty" binding:"omitempty,min=-180,max=180"`
+	VenueAddress     *string           `json:"venue_address,omitempty" binding:"omitempty,max=500"`
+	MaxParticipants  int               `json:"max_participants" binding:"required,min=1"`
+	Tags             []string          `json:"tags,omitempty" binding:"omitempty,max=20,dive,max=50"`
+	SearchLanguage   string            `json:"search_language,omitempty" binding:"omitempty,oneof=russian english simple"`
+	Visibility       SessionVisibility `json:"visibility,omitempty" binding:"omitempty,oneof=public unlisted invite_only"`
+	RequiresApproval bool              `json:"requires_approval,omitempty"`
+}
+
+// Normalize проверяет координаты и заполняет значения по умолчанию так же, как SessionRequest.Normalize
+func (r *SessionTemplateRequest) Normalize() error {
+	if (r.Latitude == nil) != (r.Longitude == nil) {
+		return ErrIncompleteCoordinates
+	}
+	if r.DurationMinutes == 0 {
+		r.DurationMinutes = int(DefaultSessionDuration / time.Minute)
+	}
+	if r.TimeZone == "" {
+		r.TimeZone = DefaultTimeZone
+	}
+	r.Tags = NormalizeTags(r.Tags)
+	r.VenueAddress = trimToNil(r.VenueAddress)
+	if r.SearchLanguage == "" {
+		r.SearchLanguage = DefaultSearchLanguage
+	}
+	if r.Visibility == "" {
+		r.Visibility = VisibilityPublic
+	}
+	return nil
+}
+
+// TemplateShareRequest - поделиться шаблоном с пользователем
+type TemplateShareRequest struct {
+	UserID uuid.UUID `json:"user_id" binding:"required"`
+}
+
+// TemplateShare - пользователь, которому доступен шаблон
+type TemplateShare struct {
+	TemplateID uuid.UUID `json:"template_id" db:"template_id"`
+	UserID     uuid.UUID `json:"user_id" db:"user_id"`
+	Name       string    `json:"name" db:"name"`
+	Email      string    `json:"email" db:"email"`
+	SharedAt   time.Time `json:"shared_at" db:"shared_at"`
+}
+
+// SessionFromTemplateRequest - создание сессии по шаблону: нужна только дата начала
+type SessionFromTemplateRequest struct {
+	DateTime       time.Time `json:"date_time" binding:"required"`
+	Draft          bool      `json:"draft,omitempty"`
+	AllowConflicts bool      `json:"allow_conflicts,omitempty"` // Создать сессию, даже если ее время пересекается с другими сессиями пользователя
+}
+
+// CloneSessionRequest для POST /api/sessions/:id/clone. Без date_time копия получает дату исходной сессии.
+// Копия создается черновиком, если не указано publish.
+type CloneSessionRequest struct {
+	DateTime       *time.Time `json:"date_time,omitempty"`
+	Publish        bool       `json:"publish,omitempty"`
+	AllowConflicts bool       `json:"allow_conflicts,omitempty"` // Создать копию, даже если ее время пересекается с другими сессиями пользователя
+}
+
+// SessionRequest собирает запрос на создание сессии по шаблону с началом в start.
+// Результат нужно нормализовать (SessionRequest.Normalize).
+func (t *SessionTemplate) SessionRequest(start time.Time, draft bool) SessionRequest {
+	requiresApproval := t.RequiresApproval
+	return SessionRequest{
+		Title:            t.Title,
+		Description:      t.Description,
+		Category:         t.Category,
+		DateTime:         start,
+		DurationMinutes:  t.DurationMinutes,
+		TimeZone:         t.TimeZone,
+		Location:         t.Location,
+		Latitude:         t.Latitude,
+		Longitude:        t.Longitude,
+		VenueAddress:     t.VenueAddress,
+		MaxParticipants:  t.MaxParticipants,
+		Tags:             t.Tags,
+		SearchLanguage:   t.SearchLanguage,
+		Visibility:       t.Visibility,
+		RequiresApproval: &requiresApproval,
+		Draft:            draft,
+	}
+}
+
+// CloneRequest собирает запрос на создание копии сессии: все описание, место, вместимость
+// и ссылка на встречу, но без участников, отзывов и привязки к серии.
+// start == nil - копия в то же время; длительность сохраняется.
+func (s *Session) CloneRequest(start *time.Time, draft bool) SessionRequest {
+	dateTime := s.DateTime
+	if start != nil {
+		dateTime = *start
+	}
+	requiresApproval := s.RequiresApproval
+	return SessionRequest{
+		Title:            s.Title,
+		Description:      s.Description,
+		Category:         s.Category,
+		DateTime:         dateTime,
+		DurationMinutes:  int(s.EndTime.Sub(s.DateTime) / time.Minute),
+		TimeZone:         s.TimeZone,
+		Location:         s.Location,
+		Latitude:         s.Latitude,
+		Longitude:        s.Longitude,
+		VenueAddress:     s.VenueAddress,
+		MeetingURL:       s.MeetingURL,
+		JoinInstructions: s.JoinInstructions,
+		MaxParticipants:  s.MaxParticipants,
+		Tags:             s.Tags,
+		SearchLanguage:   s.SearchLanguage,
+		Visibility:       s.Visibility,
+		RequiresApproval: &requiresApproval,
+		Draft:            draft,
+	}
+}
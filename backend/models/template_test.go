@@ -0,0 +1,74 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestSessionCloneRequest_KeepsDurationWithNewDate(t *testing.T) {
+	start := time.Date(2025, 3, 10, 18, 0, 0, 0, time.UTC)
+	seriesID := uuid.New()
+	url := "https://meet.example.com/abc"
+	session := Session{
+		Title:            "Go basics",
+		Category:         "programming",
+		DateTime:         start,
+		EndTime:          start.Add(2 * time.Hour),
+		TimeZone:         "Europe/Moscow",
+		Location:         "online",
+		MeetingURL:       &url,
+		MaxParticipants:  12,
+		SeriesID:         &seriesID,
+		Visibility:       VisibilityUnlisted,
+		RequiresApproval: true,
+	}
+	newStart := start.AddDate(0, 0, 7)
+
+	req := session.CloneRequest(&newStart, true)
+	if err := req.Normalize(); err != nil {
+		t.Fatalf("Normalize() error = %v", err)
+	}
+
+	if !req.DateTime.Equal(newStart) || !req.EndTime.Equal(newStart.Add(2*time.Hour)) {
+		t.Errorf("clone time = %v-%v, want %v-%v", req.DateTime, req.EndTime, newStart, newStart.Add(2*time.Hour))
+	}
+	if !req.Draft || req.Visibility != VisibilityUnlisted || !*req.RequiresApproval || req.MaxParticipants != 12 {
+		t.Errorf("unexpected clone request: %+v", req)
+	}
+	if req.MeetingURL == nil || *req.MeetingURL != url {
+		t.Errorf("meeting URL not copied: %v", req.MeetingURL)
+	}
+
+	sameDay := session.CloneRequest(nil, false)
+	if !sameDay.DateTime.Equal(start) || sameDay.Draft {
+		t.Errorf("clone without date = %v (draft %v), want %v published", sameDay.DateTime, sameDay.Draft, start)
+	}
+}
+
+func TestSessionTemplateRequestNormalize(t *testing.T) {
+	lat := 55.75
+	req := SessionTemplateRequest{Name: "Weekly", Title: "Go", Category: "programming", Location: "Room 1", MaxParticipants: 5, Latitude: &lat}
+	if err := req.Normalize(); err != ErrIncompleteCoordinates {
+		t.Fatalf("Normalize() error = %v, want %v", err, ErrIncompleteCoordinates)
+	}
+
+	req.Latitude = nil
+	if err := req.Normalize(); err != nil {
+		t.Fatalf("Normalize() error = %v", err)
+	}
+	if req.DurationMinutes != 90 || req.TimeZone != DefaultTimeZone || req.Visibility != VisibilityPublic {
+		t.Errorf("defaults not applied: %+v", req)
+	}
+
+	template := SessionTemplate{DurationMinutes: req.DurationMinutes, TimeZone: req.TimeZone, Title: "Go"}
+	start := time.Date(2025, 4, 1, 10, 0, 0, 0, time.UTC)
+	sessionReq := template.SessionRequest(start, false)
+	if err := sessionReq.Normalize(); err != nil {
+		t.Fatalf("SessionRequest.Normalize() error = %v", err)
+	}
+	if !sessionReq.EndTime.Equal(start.Add(90 * time.Minute)) {
+		t.Errorf("EndTime = %v, want %v", sessionReq.EndTime, start.Add(90*time.Minute))
+	}
+}
@@ -17,6 +17,7 @@ type User struct {
 	Bio           *string    `json:"bio,omitempty" db:"bio"`
 	Skills        pq.StringArray  `json:"skills" db:"skills"`
 	AverageRating float64   `json:"average_rating" db:"average_rating"`
+	TimeZone      *string    `json:"time_zone,omitempty" db:"time_zone"` // IANA; используется для времени в уведомлениях
 	CreatedAt     time.Time `json:"created_at" db:"created_at"`
 	UpdatedAt     time.Time `json:"updated_at" db:"updated_at"`
 	Role         string    `db:"role" json:"role"` // Добавляем роль пользователя
@@ -30,6 +31,7 @@ type UserRequest struct {
 	Name     string   `json:"name" binding:"required"`
 	Bio      string   `json:"bio,omitempty"`
 	Skills   []string `json:"skills"`
+	TimeZone string   `json:"time_zone,omitempty" binding:"omitempty,timezone"`
 }
 
 
@@ -0,0 +1,298 @@
+// Package recurrence разбирает и разворачивает правила повторения RFC 5545 (RRULE)
+// для серий сессий. Поддерживается подмножество, достаточное для регулярных занятий:
+// FREQ=DAILY|WEEKLY|MONTHLY|YEARLY, INTERVAL, COUNT, UNTIL, BYDAY (только для WEEKLY) и WKST=MO.
+package recurrence
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MaxOccurrences ограничивает количество занятий в одной серии.
+// Серия целиком материализуется в таблице sessions, поэтому бесконечные правила не допускаются.
+const MaxOccurrences = 104
+
+var (
+	ErrInvalidRule        = errors.New("invalid RRULE")
+	ErrUnboundedRule      = errors.New("RRULE must contain COUNT or UNTIL")
+	ErrTooManyOccurrences = fmt.Errorf("RRULE produces more than %d occurrences", MaxOccurrences)
+)
+
+// Frequency - значение FREQ
+type Frequency string
+
+const (
+	Daily   Frequency = "DAILY"
+	Weekly  Frequency = "WEEKLY"
+	Monthly Frequency = "MONTHLY"
+	Yearly  Frequency = "YEARLY"
+)
+
+const untilFormat = "20060102T150405Z"
+
+var weekdayCodes = map[string]time.Weekday{
+	"MO": time.Monday, "TU": time.Tuesday, "WE": time.Wednesday, "TH": time.Thursday,
+	"FR": time.Friday, "SA": time.Saturday, "SU": time.Sunday,
+}
+
+// Rule - разобранное правило повторения
+type Rule struct {
+	Freq     Frequency
+	Interval int
+	Count    int       // 0 - не задан
+	Until    time.Time // Нулевое значение - не задан
+	ByDay    []time.Weekday
+}
+
+// Parse разбирает строку RRULE (с префиксом "RRULE:" или без него)
+func Parse(s string) (*Rule, error) {
+	s = strings.TrimSpace(s)
+	s = strings.TrimPrefix(strings.ToUpper(s), "RRULE:")
+	if s == "" {
+		return nil, fmt.Errorf("%w: empty rule", ErrInvalidRule)
+	}
+
+	rule := &Rule{Interval: 1}
+	seen := map[string]bool{}
+	for _, part := range strings.Split(s, ";") {
+		key, value, ok := strings.Cut(part, "=")
+		if !ok || value == "" {
+			return nil, fmt.Errorf("%w: malformed part %q", ErrInvalidRule, part)
+		}
+		if seen[key] {
+			return nil, fmt.Errorf("%w: duplicate part %s", ErrInvalidRule, key)
+		}
+		seen[key] = true
+
+		switch key {
+		case "FREQ":
+			switch Frequency(value) {
+			case Daily, Weekly, Monthly, Yearly:
+				rule.Freq = Frequency(value)
+			default:
+				return nil, fmt.Errorf("%w: unsupported FREQ %s", ErrInvalidRule, value)
+			}
+		case "INTERVAL":
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 1 {
+				return nil, fmt.Errorf("%w: INTERVAL must be a positive integer", ErrInvalidRule)
+			}
+			rule.Interval = n
+		case "COUNT":
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 1 {
+				return nil, fmt.Errorf("%w: COUNT must be a positive integer", ErrInvalidRule)
+			}
+			rule.Count = n
+		case "UNTIL":
+			t, err := parseUntil(value)
+			if err != nil {
+				return nil, fmt.Errorf("%w: %v", ErrInvalidRule, err)
+			}
+			rule.Until = t
+		case "BYDAY":
+			for _, code := range strings.Split(value, ",") {
+				day, ok := weekdayCodes[code]
+				if !ok {
+					return nil, fmt.Errorf("%w: unsupported BYDAY value %s", ErrInvalidRule, code)
+				}
+				rule.ByDay = append(rule.ByDay, day)
+			}
+		case "WKST":
+			if value != "MO" {
+				return nil, fmt.Errorf("%w: only WKST=MO is supported", ErrInvalidRule)
+			}
+		default:
+			return nil, fmt.Errorf("%w: unsupported part %s", ErrInvalidRule, key)
+		}
+	}
+
+	if rule.Freq == "" {
+		return nil, fmt.Errorf("%w: FREQ is required", ErrInvalidRule)
+	}
+	if rule.Count > 0 && !rule.Until.IsZero() {
+		return nil, fmt.Errorf("%w: COUNT and UNTIL are mutually exclusive", ErrInvalidRule)
+	}
+	if rule.Count == 0 && rule.Until.IsZero() {
+		return nil, ErrUnboundedRule
+	}
+	if len(rule.ByDay) > 0 && rule.Freq != Weekly {
+		return nil, fmt.Errorf("%w: BYDAY is only supported with FREQ=WEEKLY", ErrInvalidRule)
+	}
+	rule.ByDay = normalizeWeekdays(rule.ByDay)
+	return rule, nil
+}
+
+func parseUntil(value string) (time.Time, error) {
+	if t, err := time.Parse(untilFormat, value); err == nil {
+		return t, nil
+	}
+	// Форма DATE: считаем, что серия длится до конца указанного дня (UTC)
+	if t, err := time.Parse("20060102", value); err == nil {
+		return t.Add(24*time.Hour - time.Second), nil
+	}
+	return time.Time{}, fmt.Errorf("UNTIL must be in the form YYYYMMDDTHHMMSSZ or YYYYMMDD")
+}
+
+// String возвращает правило в каноническом виде (без префикса "RRULE:")
+func (r *Rule) String() string {
+	parts := []string{"FREQ=" + string(r.Freq)}
+	if r.Interval > 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(r.Interval))
+	}
+	if r.Count > 0 {
+		parts = append(parts, "COUNT="+strconv.Itoa(r.Count))
+	}
+	if !r.Until.IsZero() {
+		parts = append(parts, "UNTIL="+r.Until.UTC().Format(untilFormat))
+	}
+	if len(r.ByDay) > 0 {
+		codes := make([]string, 0, len(r.ByDay))
+		for _, day := range r.ByDay {
+			codes = append(codes, weekdayCode(day))
+		}
+		parts = append(parts, "BYDAY="+strings.Join(codes, ","))
+	}
+	return strings.Join(parts, ";")
+}
+
+// WithUntil возвращает копию правила, которая заканчивается в момент until (COUNT сбрасывается)
+func (r *Rule) WithUntil(until time.Time) *Rule {
+	clone := *r
+	clone.ByDay = append([]time.Weekday(nil), r.ByDay...)
+	clone.Count = 0
+	clone.Until = until.UTC()
+	return &clone
+}
+
+// ShiftDays возвращает копию правила, у которой дни BYDAY сдвинуты на days дней.
+// Используется, когда "это и последующие" занятия переносятся на другой день недели.
+func (r *Rule) ShiftDays(days int) *Rule {
+	clone := *r
+	clone.ByDay = make([]time.Weekday, 0, len(r.ByDay))
+	for _, day := range r.ByDay {
+		clone.ByDay = append(clone.ByDay, time.Weekday(((int(day)+days)%7+7)%7))
+	}
+	clone.ByDay = normalizeWeekdays(clone.ByDay)
+	return &clone
+}
+
+// Expand возвращает все даты начала занятий серии, начиная с dtstart.
+// Вычисления ведутся в часовом поясе dtstart, так что время суток сохраняется.
+func (r *Rule) Expand(dtstart time.Time) ([]time.Time, error) {
+	var occurrences []time.Time
+
+	// Ограничиваем число периодов, чтобы правило вида "каждое 31-е число" не зациклилось
+	const maxPeriods = MaxOccurrences * 12
+	for i := 0; i < maxPeriods; i++ {
+		if !r.Until.IsZero() && r.periodStart(dtstart, i).After(r.Until) {
+			break
+		}
+		for _, t := range r.periodCandidates(dtstart, i) {
+			if t.Before(dtstart) {
+				continue
+			}
+			if !r.Until.IsZero() && t.After(r.Until) {
+				return occurrences, nil
+			}
+			occurrences = append(occurrences, t)
+			if len(occurrences) > MaxOccurrences {
+				return nil, ErrTooManyOccurrences
+			}
+			if r.Count > 0 && len(occurrences) == r.Count {
+				return occurrences, nil
+			}
+		}
+	}
+	return occurrences, nil
+}
+
+// periodStart возвращает начало i-го периода правила (для проверки UNTIL)
+func (r *Rule) periodStart(dtstart time.Time, i int) time.Time {
+	n := i * r.Interval
+	switch r.Freq {
+	case Daily:
+		return dtstart.AddDate(0, 0, n)
+	case Weekly:
+		return startOfWeek(dtstart).AddDate(0, 0, 7*n)
+	case Monthly:
+		return time.Date(dtstart.Year(), dtstart.Month()+time.Month(n), 1, 0, 0, 0, 0, dtstart.Location())
+	default:
+		return time.Date(dtstart.Year()+n, 1, 1, 0, 0, 0, 0, dtstart.Location())
+	}
+}
+
+// periodCandidates возвращает занятия i-го периода. Несуществующие даты
+// (например, 31 февраля) пропускаются, как требует RFC 5545.
+func (r *Rule) periodCandidates(dtstart time.Time, i int) []time.Time {
+	n := i * r.Interval
+	hour, min, sec := dtstart.Clock()
+	loc := dtstart.Location()
+
+	switch r.Freq {
+	case Daily:
+		return []time.Time{dtstart.AddDate(0, 0, n)}
+	case Weekly:
+		if len(r.ByDay) == 0 {
+			return []time.Time{dtstart.AddDate(0, 0, 7*n)}
+		}
+		week := startOfWeek(dtstart).AddDate(0, 0, 7*n)
+		candidates := make([]time.Time, 0, len(r.ByDay))
+		for _, day := range r.ByDay {
+			d := week.AddDate(0, 0, (int(day)+6)%7) // Понедельник - смещение 0
+			candidates = append(candidates, time.Date(d.Year(), d.Month(), d.Day(), hour, min, sec, 0, loc))
+		}
+		return candidates
+	case Monthly:
+		year, month := dtstart.Year(), dtstart.Month()+time.Month(n)
+		t := time.Date(year, month, dtstart.Day(), hour, min, sec, 0, loc)
+		if t.Day() != dtstart.Day() {
+			return nil
+		}
+		return []time.Time{t}
+	default:
+		t := time.Date(dtstart.Year()+n, dtstart.Month(), dtstart.Day(), hour, min, sec, 0, loc)
+		if t.Day() != dtstart.Day() {
+			return nil
+		}
+		return []time.Time{t}
+	}
+}
+
+func startOfWeek(t time.Time) time.Time {
+	offset := (int(t.Weekday()) + 6) % 7
+	d := t.AddDate(0, 0, -offset)
+	return time.Date(d.Year(), d.Month(), d.Day(), 0, 0, 0, 0, t.Location())
+}
+
+// normalizeWeekdays удаляет дубликаты и сортирует дни, начиная с понедельника
+func normalizeWeekdays(days []time.Weekday) []time.Weekday {
+	if len(days) == 0 {
+		return nil
+	}
+	set := map[time.Weekday]bool{}
+	result := make([]time.Weekday, 0, len(days))
+	for _, day := range days {
+		if !set[day] {
+			set[day] = true
+			result = append(result, day)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return (int(result[i])+6)%7 < (int(result[j])+6)%7
+	})
+	return result
+}
+
+func weekdayCode(day time.Weekday) string {
+	for code, d := range weekdayCodes {
+		if d == day {
+			return code
+		}
+	}
+	return ""
+}
@@ -0,0 +1,75 @@
+package recurrence_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/BuzzLyutic/Skill-sharing-web-platform/recurrence"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse_RoundTrip(t *testing.T) {
+	rule, err := recurrence.Parse("RRULE:FREQ=WEEKLY;INTERVAL=2;BYDAY=TH,TU;COUNT=6")
+	require.NoError(t, err)
+
+	assert.Equal(t, recurrence.Weekly, rule.Freq)
+	assert.Equal(t, []time.Weekday{time.Tuesday, time.Thursday}, rule.ByDay)
+	assert.Equal(t, "FREQ=WEEKLY;INTERVAL=2;COUNT=6;BYDAY=TU,TH", rule.String())
+}
+
+func TestParse_Rejects(t *testing.T) {
+	cases := map[string]error{
+		"FREQ=WEEKLY":                       recurrence.ErrUnboundedRule,
+		"FREQ=HOURLY;COUNT=3":               recurrence.ErrInvalidRule,
+		"FREQ=DAILY;COUNT=3;UNTIL=20300101": recurrence.ErrInvalidRule,
+		"FREQ=MONTHLY;BYDAY=MO;COUNT=3":     recurrence.ErrInvalidRule,
+		"FREQ=DAILY;COUNT=3;BYSETPOS=1":     recurrence.ErrInvalidRule,
+		"FREQ=DAILY;INTERVAL=0;COUNT=3":     recurrence.ErrInvalidRule,
+	}
+	for input, want := range cases {
+		_, err := recurrence.Parse(input)
+		assert.True(t, errors.Is(err, want), "input %q: got %v", input, err)
+	}
+}
+
+func TestExpand_WeeklyByDay(t *testing.T) {
+	// Среда, 10:00
+	dtstart := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	rule, err := recurrence.Parse("FREQ=WEEKLY;BYDAY=MO,WE;COUNT=4")
+	require.NoError(t, err)
+
+	got, err := rule.Expand(dtstart)
+	require.NoError(t, err)
+
+	assert.Equal(t, []time.Time{
+		time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC),
+		time.Date(2025, 1, 6, 10, 0, 0, 0, time.UTC),
+		time.Date(2025, 1, 8, 10, 0, 0, 0, time.UTC),
+		time.Date(2025, 1, 13, 10, 0, 0, 0, time.UTC),
+	}, got)
+}
+
+func TestExpand_MonthlySkipsMissingDays(t *testing.T) {
+	dtstart := time.Date(2025, 1, 31, 18, 0, 0, 0, time.UTC)
+	rule, err := recurrence.Parse("FREQ=MONTHLY;UNTIL=20250601T000000Z")
+	require.NoError(t, err)
+
+	got, err := rule.Expand(dtstart)
+	require.NoError(t, err)
+
+	assert.Equal(t, []time.Time{
+		time.Date(2025, 1, 31, 18, 0, 0, 0, time.UTC),
+		time.Date(2025, 3, 31, 18, 0, 0, 0, time.UTC),
+		time.Date(2025, 5, 31, 18, 0, 0, 0, time.UTC),
+	}, got)
+}
+
+func TestExpand_TooManyOccurrences(t *testing.T) {
+	rule, err := recurrence.Parse("FREQ=DAILY;UNTIL=20400101T000000Z")
+	require.NoError(t, err)
+
+	_, err = rule.Expand(time.Date(2025, 1, 1, 9, 0, 0, 0, time.UTC))
+	assert.True(t, errors.Is(err, recurrence.ErrTooManyOccurrences))
+}
@@ -0,0 +1,80 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/BuzzLyutic/Skill-sharing-web-platform/models"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+var ErrAttachmentNotFound = errors.New("attachment not found for this session")
+
+// AttachmentRepository обрабатывает метаданные вложений сессий
+type AttachmentRepository struct {
+	db *sqlx.DB
+}
+
+// NewAttachmentRepository создает новый репозиторий вложений
+func NewAttachmentRepository(db *sqlx.DB) *AttachmentRepository {
+	return &AttachmentRepository{db: db}
+}
+
+// Create сохраняет метаданные уже загруженного в хранилище файла
+func (r *AttachmentRepository) Create(ctx context.Context, attachment models.SessionAttachment) (*models.SessionAttachment, error) {
+	var created models.SessionAttachment
+	query := `
+        INSERT INTO session_attachments (id, session_id, uploaded_by, file_name, content_type, size_bytes, storage_key)
+        VALUES ($1, $2, $3, $4, $5, $6, $7)
+        RETURNING *`
+	err := r.db.GetContext(ctx, &created, query, attachment.ID, attachment.SessionID, attachment.UploadedBy,
+		attachment.FileName, attachment.ContentType, attachment.SizeBytes, attachment.StorageKey)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to create attachment: %v", ErrDatabase, err)
+	}
+	return &created, nil
+}
+
+// ListBySession возвращает вложения сессии в порядке загрузки
+func (r *AttachmentRepository) ListBySession(ctx context.Context, sessionID uuid.UUID) ([]models.SessionAttachment, error) {
+	attachments := []models.SessionAttachment{}
+	query := `SELECT * FROM session_attachments WHERE session_id = $1 ORDER BY created_at ASC, id ASC`
+	err := r.db.SelectContext(ctx, &attachments, query, sessionID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		log.Printf("ERROR listing attachments for session %s: %v", sessionID, err)
+		return nil, fmt.Errorf("%w: failed to list attachments: %v", ErrDatabase, err)
+	}
+	return attachments, nil
+}
+
+// GetByID возвращает вложение сессии
+func (r *AttachmentRepository) GetByID(ctx context.Context, sessionID, attachmentID uuid.UUID) (*models.SessionAttachment, error) {
+	var attachment models.SessionAttachment
+	query := `SELECT * FROM session_attachments WHERE id = $1 AND session_id = $2`
+	err := r.db.GetContext(ctx, &attachment, query, attachmentID, sessionID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrAttachmentNotFound
+		}
+		return nil, fmt.Errorf("%w: failed to get attachment: %v", ErrDatabase, err)
+	}
+	return &attachment, nil
+}
+
+// Delete удаляет метаданные вложения и возвращает их, чтобы вызывающий удалил файл из хранилища
+func (r *AttachmentRepository) Delete(ctx context.Context, sessionID, attachmentID uuid.UUID) (*models.SessionAttachment, error) {
+	var attachment models.SessionAttachment
+	query := `DELETE FROM session_attachments WHERE id = $1 AND session_id = $2 RETURNING *`
+	err := r.db.GetContext(ctx, &attachment, query, attachmentID, sessionID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrAttachmentNotFound
+		}
+		return nil, fmt.Errorf("%w: failed to delete attachment: %v", ErrDatabase, err)
+	}
+	return &attachment, nil
+}
@@ -0,0 +1,127 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/BuzzLyutic/Skill-sharing-web-platform/models"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+// AttendanceRepository обрабатывает отметки присутствия участников сессий
+type AttendanceRepository struct {
+	db *sqlx.DB
+}
+
+// NewAttendanceRepository создает новый репозиторий отметок присутствия
+func NewAttendanceRepository(db *sqlx.DB) *AttendanceRepository {
+	return &AttendanceRepository{db: db}
+}
+
+// ListAttendees возвращает участников сессии с отметками присутствия и посещаемостью
+// каждого по всем сессиям, где присутствие было отмечено
+func (r *AttendanceRepository) ListAttendees(ctx context.Context, sessionID uuid.UUID) ([]models.Attendee, error) {
+	attendees := []models.Attendee{}
+	query := `
+        SELECT sp.user_id, u.name, u.email, sp.joined_at, sp.attendance, sp.checked_in_at, sp.check_in_method,
+               stats.attended AS attended_count, stats.no_show AS no_show_count,
+               stats.attended::float8 / NULLIF(stats.attended + stats.no_show, 0) AS attendance_rate
+        FROM session_participants sp
+        JOIN users u ON u.id = sp.user_id
+        CROSS JOIN LATERAL (
+            SELECT COUNT(*) FILTER (WHERE h.attendance = 'attended') AS attended,
+                   COUNT(*) FILTER (WHERE h.attendance = 'no_show') AS no_show
+            FROM session_participants h
+            WHERE h.user_id = sp.user_id AND h.attendance <> 'unknown'
+        ) stats
+        WHERE sp.session_id = $1
+        ORDER BY u.name ASC, sp.user_id ASC`
+	err := r.db.SelectContext(ctx, &attendees, query, sessionID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		log.Printf("ERROR listing attendees for session %s: %v", sessionID, err)
+		return nil, fmt.Errorf("%w: failed to list attendees: %v", ErrDatabase, err)
+	}
+	return attendees, nil
+}
+
+// SetAttendance записывает отметки организатора markedBy. Все отметки применяются в одной транзакции:
+// если кто-то из entries не участник сессии, не применяется ни одна (ErrNotJoined).
+// markRemainingNoShow помечает неявившимися всех, чье присутствие осталось неотмеченным.
+func (r *AttendanceRepository) SetAttendance(ctx context.Context, sessionID, markedBy uuid.UUID, entries []models.AttendanceEntry, markRemainingNoShow bool) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%w: failed to begin attendance transaction: %v", ErrDatabase, err)
+	}
+	defer tx.Rollback()
+
+	// Время и способ отметки сохраняются только для пришедших; повторная отметка их не перезаписывает
+	query := `
+        UPDATE session_participants
+        SET attendance = $3,
+            checked_in_at = CASE WHEN $3 = 'attended' THEN COALESCE(checked_in_at, NOW()) END,
+            check_in_method = CASE WHEN $3 = 'attended' THEN COALESCE(check_in_method, 'host') END,
+            attendance_marked_by = $4
+        WHERE session_id = $1 AND user_id = $2`
+	for _, entry := range entries {
+		result, err := tx.ExecContext(ctx, query, sessionID, entry.UserID, entry.Status, markedBy)
+		if err != nil {
+			return fmt.Errorf("%w: failed to mark attendance: %v", ErrDatabase, err)
+		}
+		if rowsAffected, _ := result.RowsAffected(); rowsAffected == 0 {
+			return fmt.Errorf("%w: %s", ErrNotJoined, entry.UserID)
+		}
+	}
+
+	if markRemainingNoShow {
+		query := `
+            UPDATE session_participants SET attendance = $2, attendance_marked_by = $3
+            WHERE session_id = $1 AND attendance = 'unknown'`
+		if _, err := tx.ExecContext(ctx, query, sessionID, models.AttendanceNoShow, markedBy); err != nil {
+			return fmt.Errorf("%w: failed to mark no-shows: %v", ErrDatabase, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%w: failed to commit attendance transaction: %v", ErrDatabase, err)
+	}
+	return nil
+}
+
+// CheckIn отмечает участника пришедшим по коду. Повторная отметка не меняет время первой.
+// Возвращает время отметки или ErrNotJoined, если пользователь не участник.
+func (r *AttendanceRepository) CheckIn(ctx context.Context, sessionID, userID uuid.UUID) (time.Time, error) {
+	var checkedInAt time.Time
+	query := `
+        UPDATE session_participants
+        SET attendance = $3, checked_in_at = COALESCE(checked_in_at, NOW()),
+            check_in_method = COALESCE(check_in_method, $4), attendance_marked_by = $2
+        WHERE session_id = $1 AND user_id = $2
+        RETURNING checked_in_at`
+	err := r.db.GetContext(ctx, &checkedInAt, query, sessionID, userID, models.AttendanceAttended, models.CheckInMethodCode)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return time.Time{}, ErrNotJoined
+		}
+		return time.Time{}, fmt.Errorf("%w: failed to check in: %v", ErrDatabase, err)
+	}
+	return checkedInAt, nil
+}
+
+// GetAttendance возвращает отметку присутствия участника или ErrNotJoined, если он не участник
+func (r *AttendanceRepository) GetAttendance(ctx context.Context, sessionID, userID uuid.UUID) (models.AttendanceStatus, error) {
+	var status models.AttendanceStatus
+	query := `SELECT attendance FROM session_participants WHERE session_id = $1 AND user_id = $2`
+	err := r.db.GetContext(ctx, &status, query, sessionID, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", ErrNotJoined
+		}
+		return "", fmt.Errorf("%w: failed to get attendance: %v", ErrDatabase, err)
+	}
+	return status, nil
+}
@@ -0,0 +1,78 @@
+package repositories_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/BuzzLyutic/Skill-sharing-web-platform/models"
+	"github.com/BuzzLyutic/Skill-sharing-web-platform/repositories"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newAttendanceRepoWithMock(t *testing.T) (*repositories.AttendanceRepository, sqlmock.Sqlmock) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	return repositories.NewAttendanceRepository(sqlx.NewDb(db, "sqlmock")), mock
+}
+
+func TestAttendanceRepository_SetAttendance_MarksRemainingNoShow(t *testing.T) {
+	repo, mock := newAttendanceRepoWithMock(t)
+	sessionID, hostID, attendedID := uuid.New(), uuid.New(), uuid.New()
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE session_participants\s+SET attendance = \$3`).
+		WithArgs(sessionID, attendedID, models.AttendanceAttended, hostID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`UPDATE session_participants SET attendance = \$2, attendance_marked_by = \$3\s+WHERE session_id = \$1 AND attendance = 'unknown'`).
+		WithArgs(sessionID, models.AttendanceNoShow, hostID).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+	mock.ExpectCommit()
+
+	err := repo.SetAttendance(context.Background(), sessionID, hostID,
+		[]models.AttendanceEntry{{UserID: attendedID, Status: models.AttendanceAttended}}, true)
+
+	require.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAttendanceRepository_SetAttendance_UnknownParticipantRollsBack(t *testing.T) {
+	repo, mock := newAttendanceRepoWithMock(t)
+	sessionID, hostID, firstID, strangerID := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE session_participants\s+SET attendance = \$3`).
+		WithArgs(sessionID, firstID, models.AttendanceNoShow, hostID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`UPDATE session_participants\s+SET attendance = \$3`).
+		WithArgs(sessionID, strangerID, models.AttendanceAttended, hostID).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+
+	err := repo.SetAttendance(context.Background(), sessionID, hostID, []models.AttendanceEntry{
+		{UserID: firstID, Status: models.AttendanceNoShow},
+		{UserID: strangerID, Status: models.AttendanceAttended},
+	}, false)
+
+	assert.ErrorIs(t, err, repositories.ErrNotJoined)
+	assert.Contains(t, err.Error(), strangerID.String())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAttendanceRepository_CheckIn_NotParticipant(t *testing.T) {
+	repo, mock := newAttendanceRepoWithMock(t)
+	sessionID, userID := uuid.New(), uuid.New()
+
+	mock.ExpectQuery(`UPDATE session_participants\s+SET attendance = \$3, checked_in_at = COALESCE\(checked_in_at, NOW\(\)\)`).
+		WithArgs(sessionID, userID, models.AttendanceAttended, models.CheckInMethodCode).
+		WillReturnRows(sqlmock.NewRows([]string{"checked_in_at"}))
+
+	_, err := repo.CheckIn(context.Background(), sessionID, userID)
+
+	assert.ErrorIs(t, err, repositories.ErrNotJoined)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
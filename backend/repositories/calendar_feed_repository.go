@@ -0,0 +1,95 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/BuzzLyutic/Skill-sharing-web-platform/models"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+var ErrCalendarFeedNotFound = errors.New("calendar feed not found")
+
+// CalendarFeedRepository обрабатывает персональные подписки на календарь
+type CalendarFeedRepository struct {
+	db *sqlx.DB
+}
+
+// NewCalendarFeedRepository создает новый репозиторий подписок на календарь
+func NewCalendarFeedRepository(db *sqlx.DB) *CalendarFeedRepository {
+	return &CalendarFeedRepository{db: db}
+}
+
+// Get возвращает подписку пользователя
+func (r *CalendarFeedRepository) Get(ctx context.Context, userID uuid.UUID) (*models.CalendarFeed, error) {
+	var feed models.CalendarFeed
+	err := r.db.GetContext(ctx, &feed, `SELECT user_id, created_at FROM calendar_feeds WHERE user_id = $1`, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrCalendarFeedNotFound
+		}
+		return nil, fmt.Errorf("%w: failed to get calendar feed: %v", ErrDatabase, err)
+	}
+	return &feed, nil
+}
+
+// Rotate сохраняет хэш нового токена подписки; прежняя ссылка перестает работать
+func (r *CalendarFeedRepository) Rotate(ctx context.Context, userID uuid.UUID, tokenHash string) (*models.CalendarFeed, error) {
+	var feed models.CalendarFeed
+	query := `
+        INSERT INTO calendar_feeds (user_id, token_hash) VALUES ($1, $2)
+        ON CONFLICT (user_id) DO UPDATE SET token_hash = EXCLUDED.token_hash, created_at = NOW()
+        RETURNING user_id, created_at`
+	if err := r.db.GetContext(ctx, &feed, query, userID, tokenHash); err != nil {
+		return nil, fmt.Errorf("%w: failed to save calendar feed: %v", ErrDatabase, err)
+	}
+	return &feed, nil
+}
+
+// Revoke удаляет подписку пользователя
+func (r *CalendarFeedRepository) Revoke(ctx context.Context, userID uuid.UUID) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM calendar_feeds WHERE user_id = $1`, userID)
+	if err != nil {
+		return fmt.Errorf("%w: failed to revoke calendar feed: %v", ErrDatabase, err)
+	}
+	if rowsAffected, _ := result.RowsAffected(); rowsAffected == 0 {
+		return ErrCalendarFeedNotFound
+	}
+	return nil
+}
+
+// GetUserIDByTokenHash находит владельца подписки по хэшу токена
+func (r *CalendarFeedRepository) GetUserIDByTokenHash(ctx context.Context, tokenHash string) (uuid.UUID, error) {
+	var userID uuid.UUID
+	err := r.db.GetContext(ctx, &userID, `SELECT user_id FROM calendar_feeds WHERE token_hash = $1`, tokenHash)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return uuid.Nil, ErrCalendarFeedNotFound
+		}
+		return uuid.Nil, fmt.Errorf("%w: failed to find calendar feed: %v", ErrDatabase, err)
+	}
+	return userID, nil
+}
+
+// ListSessions возвращает сессии для ленты пользователя: те, где он организатор (с принятой ролью)
+// или участник, кроме черновиков, заканчивающиеся после since. Отмененные сессии включаются,
+// чтобы календари получили STATUS:CANCELLED.
+func (r *CalendarFeedRepository) ListSessions(ctx context.Context, userID uuid.UUID, since time.Time) ([]models.Session, error) {
+	sessions := []models.Session{}
+	query := `
+        SELECT s.* FROM sessions s
+        WHERE s.end_time > $2 AND s.status <> 'draft'
+          AND (
+            EXISTS (SELECT 1 FROM session_hosts h WHERE h.session_id = s.id AND h.user_id = $1 AND h.status = 'accepted')
+            OR EXISTS (SELECT 1 FROM session_participants p WHERE p.session_id = s.id AND p.user_id = $1)
+          )
+        ORDER BY s.date_time ASC, s.id ASC`
+	if err := r.db.SelectContext(ctx, &sessions, query, userID, since); err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("%w: failed to list calendar feed sessions: %v", ErrDatabase, err)
+	}
+	return sessions, nil
+}
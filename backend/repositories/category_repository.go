@@ -0,0 +1,173 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/BuzzLyutic/Skill-sharing-web-platform/models"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+var (
+	ErrCategoryNotFound    = errors.New("category not found")
+	ErrUnknownCategory     = errors.New("unknown category")
+	ErrCategorySlugTaken   = errors.New("category slug is already taken")
+	ErrCategoryInUse       = errors.New("category has subcategories or is used by sessions, series or templates")
+	ErrCategoryParentCycle = errors.New("category cannot be moved under itself or its subcategory")
+)
+
+const categoryColumns = `id, parent_id, slug, names, icon, position, created_at, updated_at`
+
+// CategoryRepository обрабатывает дерево категорий сессий
+type CategoryRepository struct {
+	db *sqlx.DB
+}
+
+// NewCategoryRepository создает новый репозиторий категорий
+func NewCategoryRepository(db *sqlx.DB) *CategoryRepository {
+	return &CategoryRepository{db: db}
+}
+
+// List возвращает все категории плоским списком (дерево собирает models.BuildCategoryTree)
+func (r *CategoryRepository) List(ctx context.Context) ([]models.Category, error) {
+	categories := []models.Category{}
+	query := `SELECT ` + categoryColumns + ` FROM categories ORDER BY position, slug`
+	if err := r.db.SelectContext(ctx, &categories, query); err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("%w: failed to list categories: %v", ErrDatabase, err)
+	}
+	return categories, nil
+}
+
+// GetByID возвращает категорию по ID
+func (r *CategoryRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Category, error) {
+	var category models.Category
+	query := `SELECT ` + categoryColumns + ` FROM categories WHERE id = $1`
+	if err := r.db.GetContext(ctx, &category, query, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrCategoryNotFound
+		}
+		return nil, fmt.Errorf("%w: failed to get category: %v", ErrDatabase, err)
+	}
+	return &category, nil
+}
+
+// Resolve находит категорию по slug или по любому локализованному названию без учета регистра,
+// чтобы "Programming" и "programming" означали одну категорию
+func (r *CategoryRepository) Resolve(ctx context.Context, value string) (*models.Category, error) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if value == "" {
+		return nil, ErrUnknownCategory
+	}
+	var category models.Category
+	query := `
+        SELECT ` + categoryColumns + ` FROM categories c
+        WHERE c.slug = $1 OR EXISTS (SELECT 1 FROM jsonb_each_text(c.names) n WHERE lower(n.value) = $1)
+        ORDER BY (c.slug = $1) DESC, c.position, c.slug
+        LIMIT 1`
+	if err := r.db.GetContext(ctx, &category, query, value); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%w: %q", ErrUnknownCategory, value)
+		}
+		return nil, fmt.Errorf("%w: failed to resolve category: %v", ErrDatabase, err)
+	}
+	return &category, nil
+}
+
+// Create добавляет категорию
+func (r *CategoryRepository) Create(ctx context.Context, req models.CategoryRequest) (*models.Category, error) {
+	var category models.Category
+	query := `
+        INSERT INTO categories (parent_id, slug, names, icon, position)
+        VALUES ($1, $2, $3, $4, $5)
+        RETURNING ` + categoryColumns
+	err := r.db.GetContext(ctx, &category, query, req.ParentID, req.Slug, models.LocalizedNames(req.Names), req.Icon, req.Position)
+	if err != nil {
+		return nil, categoryWriteError(err, "create")
+	}
+	return &category, nil
+}
+
+// Update изменяет категорию. Новый slug распространяется на сессии (ON UPDATE CASCADE),
+// и в их историю версий actorID записывается как автор изменения.
+// Категорию нельзя перенести внутрь ее собственного поддерева.
+func (r *CategoryRepository) Update(ctx context.Context, id, actorID uuid.UUID, req models.CategoryRequest) (*models.Category, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to begin category transaction: %v", ErrDatabase, err)
+	}
+	defer tx.Rollback()
+	if err := setActor(ctx, tx, actorID); err != nil {
+		return nil, err
+	}
+
+	if req.ParentID != nil {
+		var cycle bool
+		cycleQuery := `
+            WITH RECURSIVE subtree AS (
+                SELECT id FROM categories WHERE id = $1
+                UNION ALL
+                SELECT c.id FROM categories c JOIN subtree s ON c.parent_id = s.id
+            )
+            SELECT EXISTS (SELECT 1 FROM subtree WHERE id = $2)`
+		if err := tx.GetContext(ctx, &cycle, cycleQuery, id, *req.ParentID); err != nil {
+			return nil, fmt.Errorf("%w: failed to check category parent: %v", ErrDatabase, err)
+		}
+		if cycle {
+			return nil, ErrCategoryParentCycle
+		}
+	}
+
+	var category models.Category
+	query := `
+        UPDATE categories
+        SET parent_id = $2, slug = $3, names = $4, icon = $5, position = $6, updated_at = NOW()
+        WHERE id = $1
+        RETURNING ` + categoryColumns
+	err = tx.GetContext(ctx, &category, query, id, req.ParentID, req.Slug, models.LocalizedNames(req.Names), req.Icon, req.Position)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrCategoryNotFound
+		}
+		return nil, categoryWriteError(err, "update")
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("%w: failed to commit category update: %v", ErrDatabase, err)
+	}
+	return &category, nil
+}
+
+// Delete удаляет категорию без подкатегорий, которая нигде не используется
+func (r *CategoryRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM categories WHERE id = $1`, id)
+	if err != nil {
+		return categoryWriteError(err, "delete")
+	}
+	if rowsAffected, _ := result.RowsAffected(); rowsAffected == 0 {
+		return ErrCategoryNotFound
+	}
+	return nil
+}
+
+// categoryWriteError преобразует нарушения ограничений таблицы categories в ошибки репозитория
+func categoryWriteError(err error, action string) error {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		switch pqErr.Code {
+		case "23505": // unique_violation
+			return ErrCategorySlugTaken
+		case "23503": // foreign_key_violation: несуществующий родитель или категория используется
+			if action == "delete" {
+				return ErrCategoryInUse
+			}
+			return ErrCategoryNotFound
+		case "23514": // check_violation: категория - сама себе родитель
+			return ErrCategoryParentCycle
+		}
+	}
+	return fmt.Errorf("%w: failed to %s category: %v", ErrDatabase, action, err)
+}
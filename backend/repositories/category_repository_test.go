@@ -0,0 +1,79 @@
+package repositories_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/BuzzLyutic/Skill-sharing-web-platform/models"
+	"github.com/BuzzLyutic/Skill-sharing-web-platform/repositories"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newCategoryRepoWithMock(t *testing.T) (*repositories.CategoryRepository, sqlmock.Sqlmock) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	return repositories.NewCategoryRepository(sqlx.NewDb(db, "sqlmock")), mock
+}
+
+var categoryRowColumns = []string{"id", "parent_id", "slug", "names", "icon", "position", "created_at", "updated_at"}
+
+func TestCategoryRepository_Resolve_MatchesNameCaseInsensitively(t *testing.T) {
+	repo, mock := newCategoryRepoWithMock(t)
+	id := uuid.New()
+
+	mock.ExpectQuery(`FROM categories c\s+WHERE c.slug = \$1 OR EXISTS \(SELECT 1 FROM jsonb_each_text\(c.names\) n WHERE lower\(n.value\) = \$1\)`).
+		WithArgs("programming").
+		WillReturnRows(sqlmock.NewRows(categoryRowColumns).
+			AddRow(id, nil, "programming", []byte(`{"en":"Programming","ru":"Программирование"}`), nil, 1, time.Now(), time.Now()))
+
+	category, err := repo.Resolve(context.Background(), "  Programming ")
+	require.NoError(t, err)
+	assert.Equal(t, "programming", category.Slug)
+	assert.Equal(t, "Программирование", category.LocalizedName("ru"))
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCategoryRepository_Resolve_Unknown(t *testing.T) {
+	repo, mock := newCategoryRepoWithMock(t)
+
+	mock.ExpectQuery(`FROM categories c`).WithArgs("coding").WillReturnRows(sqlmock.NewRows(categoryRowColumns))
+
+	_, err := repo.Resolve(context.Background(), "Coding")
+	assert.ErrorIs(t, err, repositories.ErrUnknownCategory)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCategoryRepository_Update_RejectsMoveIntoSubtree(t *testing.T) {
+	repo, mock := newCategoryRepoWithMock(t)
+	id, child, actorID := uuid.New(), uuid.New(), uuid.New()
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`SELECT set_config\('skillshare.actor_id', \$1, true\)`).WithArgs(actorID.String()).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(`WITH RECURSIVE subtree`).WithArgs(id, child).
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+	mock.ExpectRollback()
+
+	_, err := repo.Update(context.Background(), id, actorID, models.CategoryRequest{ParentID: &child, Slug: "programming", Names: map[string]string{"en": "Programming"}})
+	assert.ErrorIs(t, err, repositories.ErrCategoryParentCycle)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCategoryRepository_Delete_InUse(t *testing.T) {
+	repo, mock := newCategoryRepoWithMock(t)
+	id := uuid.New()
+
+	mock.ExpectExec(`DELETE FROM categories WHERE id = \$1`).WithArgs(id).
+		WillReturnError(&pq.Error{Code: "23503"})
+
+	err := repo.Delete(context.Background(), id)
+	assert.ErrorIs(t, err, repositories.ErrCategoryInUse)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
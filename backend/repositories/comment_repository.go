@@ -0,0 +1,124 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/BuzzLyutic/Skill-sharing-web-platform/models"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+var (
+	ErrCommentNotFound       = errors.New("comment not found")
+	ErrParentCommentNotFound = errors.New("parent comment not found in this session or has been deleted")
+)
+
+// CommentRepository обрабатывает комментарии в обсуждениях сессий
+type CommentRepository struct {
+	db *sqlx.DB
+}
+
+// NewCommentRepository создает новый репозиторий комментариев
+func NewCommentRepository(db *sqlx.DB) *CommentRepository {
+	return &CommentRepository{db: db}
+}
+
+// commentColumns - поля комментария с именем автора; текст удаленных комментариев не выбирается
+const commentColumns = `
+        c.id, c.session_id, c.parent_id, c.user_id, u.name AS author_name,
+        CASE WHEN c.deleted_at IS NULL THEN c.body ELSE '' END AS body,
+        c.created_at, c.edited_at, c.deleted_at`
+
+// Create добавляет комментарий. Ответ (parentID != nil) допускается только на неудаленный
+// комментарий той же сессии, иначе ErrParentCommentNotFound.
+func (r *CommentRepository) Create(ctx context.Context, sessionID, userID uuid.UUID, parentID *uuid.UUID, body string) (*models.SessionComment, error) {
+	var comment models.SessionComment
+	query := `
+        WITH c AS (
+            INSERT INTO session_comments (session_id, parent_id, user_id, body)
+            SELECT $1::uuid, $2::uuid, $3::uuid, $4::text
+            WHERE $2::uuid IS NULL OR EXISTS (
+                SELECT 1 FROM session_comments p WHERE p.id = $2 AND p.session_id = $1 AND p.deleted_at IS NULL
+            )
+            RETURNING *
+        )
+        SELECT` + commentColumns + `
+        FROM c LEFT JOIN users u ON u.id = c.user_id`
+	err := r.db.GetContext(ctx, &comment, query, sessionID, parentID, userID, body)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrParentCommentNotFound
+		}
+		return nil, fmt.Errorf("%w: failed to create comment: %v", ErrDatabase, err)
+	}
+	return &comment, nil
+}
+
+// ListBySession возвращает все комментарии сессии в порядке создания (плоским списком)
+func (r *CommentRepository) ListBySession(ctx context.Context, sessionID uuid.UUID) ([]models.SessionComment, error) {
+	comments := []models.SessionComment{}
+	query := `SELECT` + commentColumns + `
+        FROM session_comments c LEFT JOIN users u ON u.id = c.user_id
+        WHERE c.session_id = $1
+        ORDER BY c.created_at ASC, c.id ASC`
+	err := r.db.SelectContext(ctx, &comments, query, sessionID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		log.Printf("ERROR listing comments for session %s: %v", sessionID, err)
+		return nil, fmt.Errorf("%w: failed to list comments: %v", ErrDatabase, err)
+	}
+	return comments, nil
+}
+
+// GetByID возвращает комментарий
+func (r *CommentRepository) GetByID(ctx context.Context, commentID uuid.UUID) (*models.SessionComment, error) {
+	var comment models.SessionComment
+	query := `SELECT` + commentColumns + `
+        FROM session_comments c LEFT JOIN users u ON u.id = c.user_id
+        WHERE c.id = $1`
+	err := r.db.GetContext(ctx, &comment, query, commentID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrCommentNotFound
+		}
+		return nil, fmt.Errorf("%w: failed to get comment: %v", ErrDatabase, err)
+	}
+	return &comment, nil
+}
+
+// Update меняет текст неудаленного комментария и отмечает время редактирования
+func (r *CommentRepository) Update(ctx context.Context, commentID uuid.UUID, body string) (*models.SessionComment, error) {
+	var comment models.SessionComment
+	query := `
+        WITH c AS (
+            UPDATE session_comments SET body = $2, edited_at = NOW()
+            WHERE id = $1 AND deleted_at IS NULL
+            RETURNING *
+        )
+        SELECT` + commentColumns + `
+        FROM c LEFT JOIN users u ON u.id = c.user_id`
+	err := r.db.GetContext(ctx, &comment, query, commentID, body)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrCommentNotFound
+		}
+		return nil, fmt.Errorf("%w: failed to update comment: %v", ErrDatabase, err)
+	}
+	return &comment, nil
+}
+
+// SoftDelete помечает комментарий удаленным. Ответы на него остаются в обсуждении.
+func (r *CommentRepository) SoftDelete(ctx context.Context, commentID, deletedBy uuid.UUID) error {
+	query := `UPDATE session_comments SET deleted_at = NOW(), deleted_by = $2 WHERE id = $1 AND deleted_at IS NULL`
+	result, err := r.db.ExecContext(ctx, query, commentID, deletedBy)
+	if err != nil {
+		return fmt.Errorf("%w: failed to delete comment: %v", ErrDatabase, err)
+	}
+	if rowsAffected, _ := result.RowsAffected(); rowsAffected == 0 {
+		return ErrCommentNotFound
+	}
+	return nil
+}
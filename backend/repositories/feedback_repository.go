@@ -6,6 +6,7 @@ import (
 	"log"
 	"fmt"
 	"database/sql"
+	"time"
 	"github.com/BuzzLyutic/Skill-sharing-web-platform/models"
 	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
@@ -60,32 +61,43 @@ func (r *FeedbackRepository) CreateFeedback(ctx context.Context, req models.Feed
 	return &fb, nil
 }
 
-// GetFeedbackBySession получает все отзывы для конкретной сессии
-// Возвращает слайс моделей (не указателей)
-func (r *FeedbackRepository) GetFeedbackBySession(ctx context.Context, sessionID uuid.UUID) ([]models.Feedback, error) {
-	var feedbacks []models.Feedback
+// GetFeedbackBySession получает страницу отзывов для конкретной сессии (от новых к старым)
+// с keyset-пагинацией по (created_at, id). Возвращает слайс моделей (не указателей)
+func (r *FeedbackRepository) GetFeedbackBySession(ctx context.Context, sessionID uuid.UUID, page models.PageRequest) ([]models.Feedback, models.PageInfo, error) {
+	var fetched []models.Feedback
+	keyset := newKeysetQuery(page, "created_at", "id", true, 2)
 	// Явно указываем поля, избегая SELECT *
 	query := `
 		SELECT id, session_id, user_id, rating, comment, created_at
-		FROM feedback
-		WHERE session_id = $1
-		ORDER BY created_at DESC`
+		FROM feedback` + appendWhere(" WHERE session_id = $1", keyset.condition) + keyset.orderAndLimit
 	// Используем SelectContext
-	err := r.db.SelectContext(ctx, &feedbacks, query, sessionID)
+	err := r.db.SelectContext(ctx, &fetched, query, append([]interface{}{sessionID}, keyset.args...)...)
 	if err != nil {
 		// sql.ErrNoRows не является ошибкой для Select, он вернет пустой слайс.
 		// Логируем только "настоящие" ошибки БД.
         if !errors.Is(err, sql.ErrNoRows) {
 		    log.Printf("ERROR fetching feedback for session %s: %v", sessionID, err)
-            return nil, fmt.Errorf("%w: failed to get feedback by session %s: %v", ErrDatabase, sessionID, err)
+            return nil, models.PageInfo{}, fmt.Errorf("%w: failed to get feedback by session %s: %v", ErrDatabase, sessionID, err)
         }
 		// Если была ошибка sql.ErrNoRows или нет ошибок, вернется пустой (но не nil) слайс feedbacks
 	}
+	feedbacks, info := models.NewPageInfo(fetched, page, func(f *models.Feedback) (time.Time, uuid.UUID) {
+		return f.CreatedAt, f.ID
+	})
     // Гарантируем возврат [] вместо nil, если записей нет
     if feedbacks == nil {
         feedbacks = []models.Feedback{}
     }
-	return feedbacks, nil
+
+	if page.WithCount {
+		var total int
+		if err := r.db.GetContext(ctx, &total, `SELECT COUNT(*) FROM feedback WHERE session_id = $1`, sessionID); err != nil {
+			log.Printf("ERROR counting feedback for session %s: %v", sessionID, err)
+			return nil, models.PageInfo{}, fmt.Errorf("%w: failed to count feedback for session %s: %v", ErrDatabase, sessionID, err)
+		}
+		info.TotalItems = &total
+	}
+	return feedbacks, info, nil
 }
 
 // GetFeedbackByUserAndSession проверяет, оставлял ли пользователь отзыв на сессию
@@ -0,0 +1,155 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/BuzzLyutic/Skill-sharing-web-platform/models"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+var (
+	ErrAlreadyHost       = errors.New("user is already a host of this session")
+	ErrHostNotFound      = errors.New("host not found for this session")
+	ErrCannotRemoveOwner = errors.New("session owner cannot be removed")
+)
+
+// HostRepository обрабатывает операции с организаторами сессий
+type HostRepository struct {
+	db *sqlx.DB
+}
+
+// NewHostRepository создает новый репозиторий организаторов
+func NewHostRepository(db *sqlx.DB) *HostRepository {
+	return &HostRepository{db: db}
+}
+
+const hostColumns = `
+        h.session_id, h.user_id, h.role, h.status, h.invited_by, h.invited_at, h.accepted_at, u.name, u.email`
+
+// GetRole возвращает роль пользователя в сессии. Непринятые приглашения не дают прав:
+// для них, как и для посторонних пользователей, возвращается пустая роль.
+func (r *HostRepository) GetRole(ctx context.Context, sessionID, userID uuid.UUID) (models.HostRole, error) {
+	var role models.HostRole
+	query := `SELECT role FROM session_hosts WHERE session_id = $1 AND user_id = $2 AND status = $3`
+	err := r.db.GetContext(ctx, &role, query, sessionID, userID, models.HostStatusAccepted)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", nil
+		}
+		return "", fmt.Errorf("%w: failed to get host role: %v", ErrDatabase, err)
+	}
+	return role, nil
+}
+
+// GetRoles возвращает роли пользователя в нескольких сессиях (например, в занятиях серии) одним запросом.
+// Сессии, где пользователь не организатор, в результат не попадают.
+func (r *HostRepository) GetRoles(ctx context.Context, sessionIDs []uuid.UUID, userID uuid.UUID) (map[uuid.UUID]models.HostRole, error) {
+	var rows []struct {
+		SessionID uuid.UUID       `db:"session_id"`
+		Role      models.HostRole `db:"role"`
+	}
+	query := `SELECT session_id, role FROM session_hosts WHERE session_id = ANY($1) AND user_id = $2 AND status = $3`
+	if err := r.db.SelectContext(ctx, &rows, query, pq.Array(sessionIDs), userID, models.HostStatusAccepted); err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("%w: failed to get host roles: %v", ErrDatabase, err)
+	}
+	roles := make(map[uuid.UUID]models.HostRole, len(rows))
+	for _, row := range rows {
+		roles[row.SessionID] = row.Role
+	}
+	return roles, nil
+}
+
+// ListHosts возвращает организаторов сессии: владельца, затем соорганизаторов и помощников.
+// includeInvited - включать еще не принятые приглашения.
+func (r *HostRepository) ListHosts(ctx context.Context, sessionID uuid.UUID, includeInvited bool) ([]models.SessionHost, error) {
+	hosts := []models.SessionHost{}
+	query := `SELECT` + hostColumns + `
+        FROM session_hosts h
+        JOIN users u ON u.id = h.user_id
+        WHERE h.session_id = $1 AND ($2 OR h.status = 'accepted')
+        ORDER BY CASE h.role WHEN 'owner' THEN 0 WHEN 'co-host' THEN 1 ELSE 2 END, h.invited_at ASC`
+	err := r.db.SelectContext(ctx, &hosts, query, sessionID, includeInvited)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		log.Printf("ERROR listing hosts for session %s: %v", sessionID, err)
+		return nil, fmt.Errorf("%w: failed to list session hosts: %v", ErrDatabase, err)
+	}
+	return hosts, nil
+}
+
+// Invite приглашает пользователя в организаторы сессии с ролью role
+func (r *HostRepository) Invite(ctx context.Context, sessionID, userID uuid.UUID, role models.HostRole, invitedBy uuid.UUID) (*models.SessionHost, error) {
+	query := `
+        INSERT INTO session_hosts (session_id, user_id, role, status, invited_by)
+        VALUES ($1, $2, $3, $4, $5)`
+	_, err := r.db.ExecContext(ctx, query, sessionID, userID, role, models.HostStatusInvited, invitedBy)
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23505" { // unique_violation
+			return nil, ErrAlreadyHost
+		}
+		return nil, fmt.Errorf("%w: failed to invite host: %v", ErrDatabase, err)
+	}
+	return r.getHost(ctx, sessionID, userID)
+}
+
+// Accept принимает приглашение пользователя стать организатором сессии
+func (r *HostRepository) Accept(ctx context.Context, sessionID, userID uuid.UUID) (*models.SessionHost, error) {
+	query := `
+        UPDATE session_hosts SET status = $3, accepted_at = NOW()
+        WHERE session_id = $1 AND user_id = $2 AND status = $4`
+	result, err := r.db.ExecContext(ctx, query, sessionID, userID, models.HostStatusAccepted, models.HostStatusInvited)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to accept host invitation: %v", ErrDatabase, err)
+	}
+	if rowsAffected, _ := result.RowsAffected(); rowsAffected == 0 {
+		return nil, ErrHostNotFound
+	}
+	return r.getHost(ctx, sessionID, userID)
+}
+
+// Remove удаляет организатора или отзывает приглашение. Владельца удалить нельзя.
+func (r *HostRepository) Remove(ctx context.Context, sessionID, userID uuid.UUID) error {
+	var role models.HostRole
+	query := `
+        DELETE FROM session_hosts
+        WHERE session_id = $1 AND user_id = $2 AND role <> 'owner'
+        RETURNING role`
+	err := r.db.GetContext(ctx, &role, query, sessionID, userID)
+	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("%w: failed to remove host: %v", ErrDatabase, err)
+		}
+		// Ничего не удалено: пользователь либо владелец, либо не организатор
+		current, errRole := r.GetRole(ctx, sessionID, userID)
+		if errRole != nil {
+			return errRole
+		}
+		if current == models.HostRoleOwner {
+			return ErrCannotRemoveOwner
+		}
+		return ErrHostNotFound
+	}
+	return nil
+}
+
+func (r *HostRepository) getHost(ctx context.Context, sessionID, userID uuid.UUID) (*models.SessionHost, error) {
+	var host models.SessionHost
+	query := `SELECT` + hostColumns + `
+        FROM session_hosts h
+        JOIN users u ON u.id = h.user_id
+        WHERE h.session_id = $1 AND h.user_id = $2`
+	err := r.db.GetContext(ctx, &host, query, sessionID, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrHostNotFound
+		}
+		return nil, fmt.Errorf("%w: failed to get session host: %v", ErrDatabase, err)
+	}
+	return &host, nil
+}
@@ -16,3 +16,8 @@ type UserRepositoryInterface interface {
 	Delete(ctx context.Context, id uuid.UUID) error
 	UpdateUserRole(ctx context.Context, userID uuid.UUID, newRole models.Role) error
 }
+
+// SkillRepositoryInterface defines methods for mapping free-text skills to the skill catalog
+type SkillRepositoryInterface interface {
+	Canonicalize(ctx context.Context, names []string) ([]models.Skill, error)
+}
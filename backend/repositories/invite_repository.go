@@ -0,0 +1,141 @@
+package repositories
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/base32"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/BuzzLyutic/Skill-sharing-web-platform/models"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+var (
+	ErrInviteNotFound = errors.New("invite not found for this session")
+	ErrInviteRequired = errors.New("an invite code is required to join this session")
+	ErrInvalidInvite  = errors.New("invite code is invalid, expired, revoked or used up")
+)
+
+// InviteRepository обрабатывает операции с приглашениями в сессии
+type InviteRepository struct {
+	db *sqlx.DB
+}
+
+// NewInviteRepository создает новый репозиторий приглашений
+func NewInviteRepository(db *sqlx.DB) *InviteRepository {
+	return &InviteRepository{db: db}
+}
+
+// inviteCodeBytes - длина случайной части кода приглашения (16 символов base32)
+const inviteCodeBytes = 10
+
+// inviteCodeEncoding - base32 без паддинга: код удобно диктовать и вставлять в URL
+var inviteCodeEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// newInviteCode генерирует случайный код приглашения
+func newInviteCode() (string, error) {
+	buf := make([]byte, inviteCodeBytes)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return inviteCodeEncoding.EncodeToString(buf), nil
+}
+
+// Create создает приглашение в сессию с необязательными сроком действия и лимитом использований
+func (r *InviteRepository) Create(ctx context.Context, sessionID, createdBy uuid.UUID, req models.SessionInviteRequest) (*models.SessionInvite, error) {
+	query := `
+        INSERT INTO session_invites (session_id, code, created_by, expires_at, max_uses)
+        VALUES ($1, $2, $3, $4, $5)
+        RETURNING *`
+	// Совпадение кодов практически невозможно, но уникальный индекс все равно может его отклонить
+	for attempt := 0; attempt < 3; attempt++ {
+		code, err := newInviteCode()
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate invite code: %w", err)
+		}
+		var invite models.SessionInvite
+		err = r.db.GetContext(ctx, &invite, query, sessionID, code, createdBy, req.ExpiresAt, req.MaxUses)
+		if err == nil {
+			return &invite, nil
+		}
+		var pqErr *pq.Error
+		if !errors.As(err, &pqErr) || pqErr.Code != "23505" { // unique_violation
+			return nil, fmt.Errorf("%w: failed to create invite: %v", ErrDatabase, err)
+		}
+	}
+	return nil, fmt.Errorf("%w: failed to generate a unique invite code", ErrDatabase)
+}
+
+// ListBySession возвращает все приглашения сессии, включая отозванные, от новых к старым
+func (r *InviteRepository) ListBySession(ctx context.Context, sessionID uuid.UUID) ([]models.SessionInvite, error) {
+	invites := []models.SessionInvite{}
+	query := `SELECT * FROM session_invites WHERE session_id = $1 ORDER BY created_at DESC`
+	err := r.db.SelectContext(ctx, &invites, query, sessionID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		log.Printf("ERROR listing invites for session %s: %v", sessionID, err)
+		return nil, fmt.Errorf("%w: failed to list session invites: %v", ErrDatabase, err)
+	}
+	return invites, nil
+}
+
+// Revoke отзывает приглашение. Повторный отзыв не меняет время первого.
+func (r *InviteRepository) Revoke(ctx context.Context, sessionID, inviteID uuid.UUID) (*models.SessionInvite, error) {
+	var invite models.SessionInvite
+	query := `
+        UPDATE session_invites SET revoked_at = COALESCE(revoked_at, NOW())
+        WHERE id = $1 AND session_id = $2
+        RETURNING *`
+	err := r.db.GetContext(ctx, &invite, query, inviteID, sessionID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrInviteNotFound
+		}
+		return nil, fmt.Errorf("%w: failed to revoke invite: %v", ErrDatabase, err)
+	}
+	return &invite, nil
+}
+
+// IsValid проверяет, что код - действующее приглашение в сессию. Использование не засчитывается:
+// это происходит только при присоединении (SessionRepository.JoinSession).
+func (r *InviteRepository) IsValid(ctx context.Context, sessionID uuid.UUID, code string) (bool, error) {
+	if code == "" {
+		return false, nil
+	}
+	var exists bool
+	query := `SELECT EXISTS (SELECT 1 FROM session_invites WHERE session_id = $1 AND code = $2` + activeInviteCondition + `)`
+	if err := r.db.GetContext(ctx, &exists, query, sessionID, code); err != nil {
+		return false, fmt.Errorf("%w: failed to check invite: %v", ErrDatabase, err)
+	}
+	return exists, nil
+}
+
+// activeInviteCondition - условие действующего приглашения (см. models.SessionInvite.IsActive)
+const activeInviteCondition = `
+        AND revoked_at IS NULL
+        AND (expires_at IS NULL OR expires_at > NOW())
+        AND (max_uses IS NULL OR use_count < max_uses)`
+
+// consumeInvite засчитывает использование приглашения code в сессию sessionID.
+// Должна вызываться внутри транзакции присоединения, чтобы лимит использований
+// не превышался при одновременных запросах.
+func consumeInvite(ctx context.Context, tx *sqlx.Tx, sessionID uuid.UUID, code string) error {
+	if code == "" {
+		return ErrInviteRequired
+	}
+	query := `
+        UPDATE session_invites SET use_count = use_count + 1
+        WHERE session_id = $1 AND code = $2` + activeInviteCondition
+	result, err := tx.ExecContext(ctx, query, sessionID, code)
+	if err != nil {
+		return fmt.Errorf("%w: failed to use invite: %v", ErrDatabase, err)
+	}
+	if rowsAffected, _ := result.RowsAffected(); rowsAffected == 0 {
+		return ErrInvalidInvite
+	}
+	return nil
+}
@@ -0,0 +1,146 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/BuzzLyutic/Skill-sharing-web-platform/models"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+var (
+	ErrJoinRequestPending  = errors.New("a join request for this session is already pending")
+	ErrJoinRequestNotFound = errors.New("pending join request not found for this session")
+)
+
+// JoinRequestRepository обрабатывает операции с заявками на участие в сессиях с одобрением
+type JoinRequestRepository struct {
+	db *sqlx.DB
+}
+
+// NewJoinRequestRepository создает новый репозиторий заявок на участие
+func NewJoinRequestRepository(db *sqlx.DB) *JoinRequestRepository {
+	return &JoinRequestRepository{db: db}
+}
+
+const joinRequestColumns = `
+        jr.id, jr.session_id, jr.user_id, jr.status, jr.message, jr.decision_message, jr.decided_by,
+        jr.created_at, jr.decided_at, u.name, u.email`
+
+// ListBySession возвращает заявки сессии со статусом status в порядке подачи
+func (r *JoinRequestRepository) ListBySession(ctx context.Context, sessionID uuid.UUID, status models.JoinRequestStatus) ([]models.SessionJoinRequest, error) {
+	requests := []models.SessionJoinRequest{}
+	query := `SELECT` + joinRequestColumns + `
+        FROM session_join_requests jr
+        JOIN users u ON u.id = jr.user_id
+        WHERE jr.session_id = $1 AND jr.status = $2
+        ORDER BY jr.created_at ASC, jr.id ASC`
+	err := r.db.SelectContext(ctx, &requests, query, sessionID, status)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		log.Printf("ERROR listing join requests for session %s: %v", sessionID, err)
+		return nil, fmt.Errorf("%w: failed to list join requests: %v", ErrDatabase, err)
+	}
+	return requests, nil
+}
+
+// Approve одобряет заявку пользователя. Заявитель становится участником, а если мест уже нет -
+// встает в очередь ожидания. Выполняется под блокировкой строки сессии, как и JoinSession.
+func (r *JoinRequestRepository) Approve(ctx context.Context, sessionID, userID, decidedBy uuid.UUID, message string) (*models.JoinResult, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to begin approve transaction: %v", ErrDatabase, err)
+	}
+	defer tx.Rollback()
+
+	seats, err := lockSessionSeats(ctx, tx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	if seats.Status != models.SessionStatusPublished {
+		return nil, ErrSessionNotOpen
+	}
+	if err := decideJoinRequest(ctx, tx, sessionID, userID, decidedBy, models.JoinRequestStatusApproved, message); err != nil {
+		return nil, err
+	}
+
+	var participants int
+	query := `SELECT COUNT(*) FROM session_participants WHERE session_id = $1`
+	if err := tx.GetContext(ctx, &participants, query, sessionID); err != nil {
+		return nil, fmt.Errorf("%w: failed to count participants: %v", ErrDatabase, err)
+	}
+	result, err := takeSeatOrWaitlist(ctx, tx, sessionID, userID, participants, seats.MaxParticipants)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("%w: failed to commit approve transaction: %v", ErrDatabase, err)
+	}
+	return result, nil
+}
+
+// Reject отклоняет заявку пользователя
+func (r *JoinRequestRepository) Reject(ctx context.Context, sessionID, userID, decidedBy uuid.UUID, message string) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%w: failed to begin reject transaction: %v", ErrDatabase, err)
+	}
+	defer tx.Rollback()
+
+	if err := decideJoinRequest(ctx, tx, sessionID, userID, decidedBy, models.JoinRequestStatusRejected, message); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%w: failed to commit reject transaction: %v", ErrDatabase, err)
+	}
+	return nil
+}
+
+// decideJoinRequest записывает решение по ожидающей заявке
+func decideJoinRequest(ctx context.Context, tx *sqlx.Tx, sessionID, userID, decidedBy uuid.UUID, status models.JoinRequestStatus, message string) error {
+	query := `
+        UPDATE session_join_requests
+        SET status = $3, decision_message = NULLIF($4, ''), decided_by = $5, decided_at = NOW()
+        WHERE session_id = $1 AND user_id = $2 AND status = 'pending'`
+	result, err := tx.ExecContext(ctx, query, sessionID, userID, status, message, decidedBy)
+	if err != nil {
+		return fmt.Errorf("%w: failed to update join request: %v", ErrDatabase, err)
+	}
+	if rowsAffected, _ := result.RowsAffected(); rowsAffected == 0 {
+		return ErrJoinRequestNotFound
+	}
+	return nil
+}
+
+// createJoinRequest создает ожидающую заявку на участие. Вызывается из JoinSession.
+func createJoinRequest(ctx context.Context, tx *sqlx.Tx, sessionID, userID uuid.UUID, message string) error {
+	query := `INSERT INTO session_join_requests (session_id, user_id, message) VALUES ($1, $2, NULLIF($3, ''))`
+	if _, err := tx.ExecContext(ctx, query, sessionID, userID, message); err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23505" { // unique_violation
+			return ErrJoinRequestPending
+		}
+		return fmt.Errorf("%w: failed to create join request: %v", ErrDatabase, err)
+	}
+	return nil
+}
+
+// withdrawJoinRequest отзывает ожидающую заявку пользователя. Вызывается из LeaveSession.
+func withdrawJoinRequest(ctx context.Context, tx *sqlx.Tx, sessionID, userID uuid.UUID) error {
+	query := `
+        UPDATE session_join_requests SET status = $3, decided_at = NOW()
+        WHERE session_id = $1 AND user_id = $2 AND status = 'pending'`
+	result, err := tx.ExecContext(ctx, query, sessionID, userID, models.JoinRequestStatusWithdrawn)
+	if err != nil {
+		return fmt.Errorf("%w: failed to withdraw join request: %v", ErrDatabase, err)
+	}
+	if rowsAffected, _ := result.RowsAffected(); rowsAffected == 0 {
+		return ErrNotJoined
+	}
+	return nil
+}
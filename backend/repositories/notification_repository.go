@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"time"
 
 	"github.com/BuzzLyutic/Skill-sharing-web-platform/models"
 	"github.com/google/uuid"
@@ -37,23 +38,35 @@ func (r *NotificationRepository) CreateNotification(ctx context.Context, notific
 	return &createdNotification, nil
 }
 
-// GetUnreadNotificationsForUser получает непрочитанные уведомления для пользователя
-func (r *NotificationRepository) GetUnreadNotificationsForUser(ctx context.Context, userID uuid.UUID, limit int) ([]models.Notification, error) {
-	var notifications []models.Notification
+// GetUnreadNotificationsForUser получает страницу непрочитанных уведомлений пользователя
+// (от новых к старым) с keyset-пагинацией по (created_at, id)
+func (r *NotificationRepository) GetUnreadNotificationsForUser(ctx context.Context, userID uuid.UUID, page models.PageRequest) ([]models.Notification, models.PageInfo, error) {
+	var fetched []models.Notification
+	keyset := newKeysetQuery(page, "created_at", "id", true, 2)
 	query := `
-		SELECT * FROM notifications
-		WHERE user_id = $1 AND is_read = FALSE
-		ORDER BY created_at DESC
-		LIMIT $2`
-	err := r.db.SelectContext(ctx, &notifications, query, userID, limit)
+		SELECT * FROM notifications` + appendWhere(" WHERE user_id = $1 AND is_read = FALSE", keyset.condition) + keyset.orderAndLimit
+	err := r.db.SelectContext(ctx, &fetched, query, append([]interface{}{userID}, keyset.args...)...)
 	if err != nil {
 		log.Printf("ERROR getting unread notifications for user %s: %v", userID, err)
-		return nil, fmt.Errorf("failed to get notifications: %w", err)
+		return nil, models.PageInfo{}, fmt.Errorf("failed to get notifications: %w", err)
 	}
+	notifications, info := models.NewPageInfo(fetched, page, func(n *models.Notification) (time.Time, uuid.UUID) {
+		return n.CreatedAt, n.ID
+	})
 	if notifications == nil {
 		notifications = []models.Notification{}
 	}
-	return notifications, nil
+
+	if page.WithCount {
+		var total int
+		countQuery := `SELECT COUNT(*) FROM notifications WHERE user_id = $1 AND is_read = FALSE`
+		if err := r.db.GetContext(ctx, &total, countQuery, userID); err != nil {
+			log.Printf("ERROR counting unread notifications for user %s: %v", userID, err)
+			return nil, models.PageInfo{}, fmt.Errorf("failed to count notifications: %w", err)
+		}
+		info.TotalItems = &total
+	}
+	return notifications, info, nil
 }
 
 // MarkNotificationAsRead помечает уведомление как прочитанное
@@ -0,0 +1,51 @@
+package repositories
+
+import (
+	"fmt"
+
+	"github.com/BuzzLyutic/Skill-sharing-web-platform/models"
+)
+
+// keysetQuery - части запроса keyset-пагинации по паре колонок (время, id)
+type keysetQuery struct {
+	condition     string        // Условие "после курсора" или пустая строка для первой страницы
+	orderAndLimit string        // " ORDER BY ... LIMIT $n"
+	args          []interface{} // Аргументы условия и LIMIT
+}
+
+// newKeysetQuery строит условие, сортировку и лимит для страницы page. descending - порядок
+// отображения списка; страница "назад" выбирается в обратном порядке и переворачивается
+// в models.NewPageInfo. Выбирается Limit+1 строк, чтобы узнать, есть ли следующая страница.
+// argID - номер первого свободного параметра запроса.
+func newKeysetQuery(page models.PageRequest, timeColumn, idColumn string, descending bool, argID int) keysetQuery {
+	if page.Cursor != nil && page.Cursor.Backward {
+		descending = !descending
+	}
+	comparison, direction := ">", "ASC"
+	if descending {
+		comparison, direction = "<", "DESC"
+	}
+
+	var q keysetQuery
+	if page.Cursor != nil {
+		// Сравнение строк (time, id) использует составной индекс и не пропускает записи с одинаковым временем
+		q.condition = fmt.Sprintf("(%s, %s) %s ($%d, $%d)", timeColumn, idColumn, comparison, argID, argID+1)
+		q.args = append(q.args, page.Cursor.Time, page.Cursor.ID)
+		argID += 2
+	}
+	q.orderAndLimit = fmt.Sprintf(" ORDER BY %[1]s %[3]s, %[2]s %[3]s LIMIT $%[4]d", timeColumn, idColumn, direction, argID)
+	q.args = append(q.args, page.Limit+1)
+	return q
+}
+
+// appendWhere добавляет условие condition к WHERE-части запроса whereSQL (" WHERE ..." или "")
+func appendWhere(whereSQL, condition string) string {
+	switch {
+	case condition == "":
+		return whereSQL
+	case whereSQL == "":
+		return " WHERE " + condition
+	default:
+		return whereSQL + " AND " + condition
+	}
+}
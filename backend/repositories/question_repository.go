@@ -0,0 +1,160 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/BuzzLyutic/Skill-sharing-web-platform/models"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+var (
+	ErrQuestionNotFound = errors.New("question not found")
+	ErrAlreadyVoted     = errors.New("you have already voted for this question")
+	ErrVoteNotFound     = errors.New("you have not voted for this question")
+)
+
+// QuestionRepository обрабатывает вопросы к сессиям и голоса за них
+type QuestionRepository struct {
+	db *sqlx.DB
+}
+
+// NewQuestionRepository создает новый репозиторий вопросов
+func NewQuestionRepository(db *sqlx.DB) *QuestionRepository {
+	return &QuestionRepository{db: db}
+}
+
+// questionSelect выбирает вопросы с именем автора, числом голосов и голосом пользователя $1
+const questionSelect = `
+        SELECT q.id, q.session_id, q.user_id, u.name AS author_name, q.body, q.is_answered, q.is_pinned,
+               q.answered_at, q.created_at,
+               (SELECT COUNT(*) FROM session_question_votes v WHERE v.question_id = q.id) AS votes,
+               EXISTS (SELECT 1 FROM session_question_votes v WHERE v.question_id = q.id AND v.user_id = $1) AS has_voted
+        FROM session_questions q
+        LEFT JOIN users u ON u.id = q.user_id`
+
+// Create добавляет вопрос к сессии
+func (r *QuestionRepository) Create(ctx context.Context, sessionID, userID uuid.UUID, body string) (*models.SessionQuestion, error) {
+	var questionID uuid.UUID
+	query := `INSERT INTO session_questions (session_id, user_id, body) VALUES ($1, $2, $3) RETURNING id`
+	if err := r.db.GetContext(ctx, &questionID, query, sessionID, userID, body); err != nil {
+		return nil, fmt.Errorf("%w: failed to create question: %v", ErrDatabase, err)
+	}
+	return r.GetByID(ctx, sessionID, questionID, userID)
+}
+
+// ListBySession возвращает вопросы сессии: сначала закрепленные, затем по убыванию голосов,
+// при равенстве - более ранние. viewerID нужен для поля HasVoted.
+func (r *QuestionRepository) ListBySession(ctx context.Context, sessionID, viewerID uuid.UUID) ([]models.SessionQuestion, error) {
+	questions := []models.SessionQuestion{}
+	query := questionSelect + `
+        WHERE q.session_id = $2
+        ORDER BY q.is_pinned DESC, votes DESC, q.created_at ASC, q.id ASC`
+	err := r.db.SelectContext(ctx, &questions, query, viewerID, sessionID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		log.Printf("ERROR listing questions for session %s: %v", sessionID, err)
+		return nil, fmt.Errorf("%w: failed to list questions: %v", ErrDatabase, err)
+	}
+	return questions, nil
+}
+
+// GetByID возвращает вопрос сессии
+func (r *QuestionRepository) GetByID(ctx context.Context, sessionID, questionID, viewerID uuid.UUID) (*models.SessionQuestion, error) {
+	var question models.SessionQuestion
+	query := questionSelect + ` WHERE q.id = $2 AND q.session_id = $3`
+	err := r.db.GetContext(ctx, &question, query, viewerID, questionID, sessionID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrQuestionNotFound
+		}
+		return nil, fmt.Errorf("%w: failed to get question: %v", ErrDatabase, err)
+	}
+	return &question, nil
+}
+
+// Vote добавляет голос пользователя за вопрос. Повторный голос - ErrAlreadyVoted.
+func (r *QuestionRepository) Vote(ctx context.Context, sessionID, questionID, userID uuid.UUID) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%w: failed to begin transaction: %v", ErrDatabase, err)
+	}
+	defer tx.Rollback()
+
+	var exists bool
+	query := `SELECT EXISTS (SELECT 1 FROM session_questions WHERE id = $1 AND session_id = $2)`
+	if err := tx.GetContext(ctx, &exists, query, questionID, sessionID); err != nil {
+		return fmt.Errorf("%w: failed to check question: %v", ErrDatabase, err)
+	}
+	if !exists {
+		return ErrQuestionNotFound
+	}
+
+	result, err := tx.ExecContext(ctx,
+		`INSERT INTO session_question_votes (question_id, user_id) VALUES ($1, $2) ON CONFLICT DO NOTHING`,
+		questionID, userID)
+	if err != nil {
+		return fmt.Errorf("%w: failed to vote for question: %v", ErrDatabase, err)
+	}
+	if rowsAffected, _ := result.RowsAffected(); rowsAffected == 0 {
+		return ErrAlreadyVoted
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%w: failed to commit vote: %v", ErrDatabase, err)
+	}
+	return nil
+}
+
+// Unvote снимает голос пользователя за вопрос сессии
+func (r *QuestionRepository) Unvote(ctx context.Context, sessionID, questionID, userID uuid.UUID) error {
+	query := `
+        DELETE FROM session_question_votes v
+        USING session_questions q
+        WHERE v.question_id = q.id AND q.id = $1 AND q.session_id = $2 AND v.user_id = $3`
+	result, err := r.db.ExecContext(ctx, query, questionID, sessionID, userID)
+	if err != nil {
+		return fmt.Errorf("%w: failed to remove vote: %v", ErrDatabase, err)
+	}
+	if rowsAffected, _ := result.RowsAffected(); rowsAffected == 0 {
+		return ErrVoteNotFound
+	}
+	return nil
+}
+
+// UpdateStatus меняет отметки "отвечен" и "закреплен"; nil - оставить как есть.
+// answered_at ставится при первой отметке отвеченным и сбрасывается при снятии отметки.
+func (r *QuestionRepository) UpdateStatus(ctx context.Context, sessionID, questionID uuid.UUID, isAnswered, isPinned *bool) error {
+	query := `
+        UPDATE session_questions SET
+            is_answered = COALESCE($3, is_answered),
+            is_pinned = COALESCE($4, is_pinned),
+            answered_at = CASE
+                WHEN $3 IS NULL THEN answered_at
+                WHEN $3 THEN COALESCE(answered_at, NOW())
+                ELSE NULL
+            END
+        WHERE id = $1 AND session_id = $2`
+	result, err := r.db.ExecContext(ctx, query, questionID, sessionID, isAnswered, isPinned)
+	if err != nil {
+		return fmt.Errorf("%w: failed to update question: %v", ErrDatabase, err)
+	}
+	if rowsAffected, _ := result.RowsAffected(); rowsAffected == 0 {
+		return ErrQuestionNotFound
+	}
+	return nil
+}
+
+// Delete удаляет вопрос сессии вместе с голосами
+func (r *QuestionRepository) Delete(ctx context.Context, sessionID, questionID uuid.UUID) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM session_questions WHERE id = $1 AND session_id = $2`, questionID, sessionID)
+	if err != nil {
+		return fmt.Errorf("%w: failed to delete question: %v", ErrDatabase, err)
+	}
+	if rowsAffected, _ := result.RowsAffected(); rowsAffected == 0 {
+		return ErrQuestionNotFound
+	}
+	return nil
+}
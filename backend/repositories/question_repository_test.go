@@ -0,0 +1,55 @@
+package repositories_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/BuzzLyutic/Skill-sharing-web-platform/repositories"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newQuestionRepoWithMock(t *testing.T) (*repositories.QuestionRepository, sqlmock.Sqlmock) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	return repositories.NewQuestionRepository(sqlx.NewDb(db, "sqlmock")), mock
+}
+
+func TestQuestionRepository_Vote_SecondVoteIsRejected(t *testing.T) {
+	repo, mock := newQuestionRepoWithMock(t)
+	sessionID, questionID, userID := uuid.New(), uuid.New(), uuid.New()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT EXISTS \(SELECT 1 FROM session_questions WHERE id = \$1 AND session_id = \$2\)`).
+		WithArgs(questionID, sessionID).
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+	mock.ExpectExec(`INSERT INTO session_question_votes \(question_id, user_id\) VALUES \(\$1, \$2\) ON CONFLICT DO NOTHING`).
+		WithArgs(questionID, userID).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+
+	err := repo.Vote(context.Background(), sessionID, questionID, userID)
+
+	assert.ErrorIs(t, err, repositories.ErrAlreadyVoted)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestQuestionRepository_Vote_QuestionFromAnotherSession(t *testing.T) {
+	repo, mock := newQuestionRepoWithMock(t)
+	sessionID, questionID, userID := uuid.New(), uuid.New(), uuid.New()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT EXISTS \(SELECT 1 FROM session_questions`).
+		WithArgs(questionID, sessionID).
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+	mock.ExpectRollback()
+
+	err := repo.Vote(context.Background(), sessionID, questionID, userID)
+
+	assert.ErrorIs(t, err, repositories.ErrQuestionNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,149 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/BuzzLyutic/Skill-sharing-web-platform/models"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+var (
+	ErrReconfirmationNotFound = errors.New("no reconfirmation requested for this session")
+	ErrReconfirmationClosed   = errors.New("reconfirmation has already been answered or has expired")
+)
+
+// ReconfirmationRepository обрабатывает подтверждения участия после переноса сессий
+type ReconfirmationRepository struct {
+	db *sqlx.DB
+}
+
+// NewReconfirmationRepository создает новый репозиторий подтверждений участия
+func NewReconfirmationRepository(db *sqlx.DB) *ReconfirmationRepository {
+	return &ReconfirmationRepository{db: db}
+}
+
+// Request запрашивает подтверждение у всех текущих участников сессии. Повторный перенос
+// открывает запрос заново, даже если участник уже ответил. Возвращает ID участников.
+func (r *ReconfirmationRepository) Request(ctx context.Context, sessionID uuid.UUID, previousStart, deadline time.Time) ([]uuid.UUID, error) {
+	userIDs := []uuid.UUID{}
+	query := `
+        INSERT INTO session_reconfirmations (session_id, user_id, previous_date_time, deadline)
+        SELECT session_id, user_id, $2, $3 FROM session_participants WHERE session_id = $1
+        ON CONFLICT (session_id, user_id) DO UPDATE
+        SET status = 'pending', previous_date_time = EXCLUDED.previous_date_time, deadline = EXCLUDED.deadline,
+            requested_at = NOW(), responded_at = NULL
+        RETURNING user_id`
+	if err := r.db.SelectContext(ctx, &userIDs, query, sessionID, previousStart, deadline); err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("%w: failed to request reconfirmations: %v", ErrDatabase, err)
+	}
+	return userIDs, nil
+}
+
+// Get возвращает запрос подтверждения участника
+func (r *ReconfirmationRepository) Get(ctx context.Context, sessionID, userID uuid.UUID) (*models.SessionReconfirmation, error) {
+	var reconfirmation models.SessionReconfirmation
+	query := `
+        SELECT session_id, user_id, status, previous_date_time, deadline, requested_at, responded_at
+        FROM session_reconfirmations WHERE session_id = $1 AND user_id = $2`
+	if err := r.db.GetContext(ctx, &reconfirmation, query, sessionID, userID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrReconfirmationNotFound
+		}
+		return nil, fmt.Errorf("%w: failed to get reconfirmation: %v", ErrDatabase, err)
+	}
+	return &reconfirmation, nil
+}
+
+// ListBySession возвращает ответы участников на перенос сессии
+func (r *ReconfirmationRepository) ListBySession(ctx context.Context, sessionID uuid.UUID) ([]models.SessionReconfirmation, error) {
+	reconfirmations := []models.SessionReconfirmation{}
+	query := `
+        SELECT rc.session_id, rc.user_id, u.name AS user_name, rc.status, rc.previous_date_time, rc.deadline,
+               rc.requested_at, rc.responded_at
+        FROM session_reconfirmations rc
+        JOIN users u ON u.id = rc.user_id
+        WHERE rc.session_id = $1
+        ORDER BY rc.status, u.name`
+	if err := r.db.SelectContext(ctx, &reconfirmations, query, sessionID); err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("%w: failed to list reconfirmations: %v", ErrDatabase, err)
+	}
+	return reconfirmations, nil
+}
+
+// respondQuery сохраняет ответ участника, если запрос еще открыт
+const respondQuery = `
+        UPDATE session_reconfirmations SET status = $3, responded_at = NOW()
+        WHERE session_id = $1 AND user_id = $2 AND status = 'pending' AND deadline > NOW()
+        RETURNING session_id, user_id, status, previous_date_time, deadline, requested_at, responded_at`
+
+// Respond сохраняет ответ участника, если запрос еще открыт
+func (r *ReconfirmationRepository) Respond(ctx context.Context, sessionID, userID uuid.UUID, status models.ReconfirmationStatus) (*models.SessionReconfirmation, error) {
+	var reconfirmation models.SessionReconfirmation
+	if err := r.db.GetContext(ctx, &reconfirmation, respondQuery, sessionID, userID, status); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrReconfirmationClosed
+		}
+		return nil, fmt.Errorf("%w: failed to save reconfirmation: %v", ErrDatabase, err)
+	}
+	return &reconfirmation, nil
+}
+
+// Decline сохраняет отказ участника и в той же транзакции освобождает его место,
+// переводя пользователей из очереди ожидания. Если запрос уже закрыт или пользователь
+// больше не участник, ничего не меняется. Возвращает ответ и ID переведенных из очереди.
+func (r *ReconfirmationRepository) Decline(ctx context.Context, sessionID, userID uuid.UUID) (*models.SessionReconfirmation, []uuid.UUID, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: failed to begin reconfirmation transaction: %v", ErrDatabase, err)
+	}
+	defer tx.Rollback()
+
+	// Сессия блокируется первой, как и в LeaveSession
+	seats, err := lockSessionSeats(ctx, tx, sessionID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var reconfirmation models.SessionReconfirmation
+	if err := tx.GetContext(ctx, &reconfirmation, respondQuery, sessionID, userID, models.ReconfirmationDeclined); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil, ErrReconfirmationClosed
+		}
+		return nil, nil, fmt.Errorf("%w: failed to save reconfirmation: %v", ErrDatabase, err)
+	}
+
+	result, err := tx.ExecContext(ctx, `DELETE FROM session_participants WHERE session_id = $1 AND user_id = $2`, sessionID, userID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: failed to leave session: %v", ErrDatabase, err)
+	}
+	if rowsAffected, _ := result.RowsAffected(); rowsAffected == 0 {
+		return nil, nil, ErrNotJoined
+	}
+
+	var promoted []uuid.UUID
+	if seats.Status == models.SessionStatusPublished {
+		if promoted, err = promoteFromWaitlist(ctx, tx, sessionID, seats.MaxParticipants); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, nil, fmt.Errorf("%w: failed to commit reconfirmation transaction: %v", ErrDatabase, err)
+	}
+	return &reconfirmation, promoted, nil
+}
+
+// ExpireOverdue закрывает запросы без ответа, срок которых истек к моменту now. Участие сохраняется.
+func (r *ReconfirmationRepository) ExpireOverdue(ctx context.Context, now time.Time) (int64, error) {
+	result, err := r.db.ExecContext(ctx, `UPDATE session_reconfirmations SET status = 'expired' WHERE status = 'pending' AND deadline <= $1`, now)
+	if err != nil {
+		return 0, fmt.Errorf("%w: failed to expire reconfirmations: %v", ErrDatabase, err)
+	}
+	expired, _ := result.RowsAffected()
+	return expired, nil
+}
@@ -0,0 +1,90 @@
+package repositories_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/BuzzLyutic/Skill-sharing-web-platform/models"
+	"github.com/BuzzLyutic/Skill-sharing-web-platform/repositories"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newReconfirmationRepoWithMock(t *testing.T) (*repositories.ReconfirmationRepository, sqlmock.Sqlmock) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	return repositories.NewReconfirmationRepository(sqlx.NewDb(db, "sqlmock")), mock
+}
+
+var reconfirmationColumns = []string{"session_id", "user_id", "status", "previous_date_time", "deadline", "requested_at", "responded_at"}
+
+func TestReconfirmationRepository_Decline_LeavesAndPromotes(t *testing.T) {
+	repo, mock := newReconfirmationRepoWithMock(t)
+	sessionID, userID, promotedID := uuid.New(), uuid.New(), uuid.New()
+	now := time.Now()
+
+	mock.ExpectBegin()
+	expectSeatLock(mock, sessionID, 5, models.SessionStatusPublished)
+	mock.ExpectQuery(`UPDATE session_reconfirmations SET status = \$3`).
+		WithArgs(sessionID, userID, models.ReconfirmationDeclined).
+		WillReturnRows(sqlmock.NewRows(reconfirmationColumns).
+			AddRow(sessionID, userID, models.ReconfirmationDeclined, now, now.Add(time.Hour), now, now))
+	mock.ExpectExec(`DELETE FROM session_participants WHERE session_id = \$1 AND user_id = \$2`).
+		WithArgs(sessionID, userID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(`WITH free AS`).
+		WithArgs(sessionID, 5).
+		WillReturnRows(sqlmock.NewRows([]string{"user_id"}).AddRow(promotedID))
+	mock.ExpectCommit()
+
+	reconfirmation, promoted, err := repo.Decline(context.Background(), sessionID, userID)
+
+	require.NoError(t, err)
+	assert.Equal(t, models.ReconfirmationDeclined, reconfirmation.Status)
+	assert.Equal(t, []uuid.UUID{promotedID}, promoted)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestReconfirmationRepository_Decline_NotJoinedKeepsRequestOpen(t *testing.T) {
+	repo, mock := newReconfirmationRepoWithMock(t)
+	sessionID, userID := uuid.New(), uuid.New()
+	now := time.Now()
+
+	mock.ExpectBegin()
+	expectSeatLock(mock, sessionID, 5, models.SessionStatusPublished)
+	mock.ExpectQuery(`UPDATE session_reconfirmations SET status = \$3`).
+		WithArgs(sessionID, userID, models.ReconfirmationDeclined).
+		WillReturnRows(sqlmock.NewRows(reconfirmationColumns).
+			AddRow(sessionID, userID, models.ReconfirmationDeclined, now, now.Add(time.Hour), now, now))
+	mock.ExpectExec(`DELETE FROM session_participants`).
+		WithArgs(sessionID, userID).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+
+	_, _, err := repo.Decline(context.Background(), sessionID, userID)
+
+	assert.ErrorIs(t, err, repositories.ErrNotJoined)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestReconfirmationRepository_Decline_ClosedRequest(t *testing.T) {
+	repo, mock := newReconfirmationRepoWithMock(t)
+	sessionID, userID := uuid.New(), uuid.New()
+
+	mock.ExpectBegin()
+	expectSeatLock(mock, sessionID, 5, models.SessionStatusPublished)
+	mock.ExpectQuery(`UPDATE session_reconfirmations SET status = \$3`).
+		WithArgs(sessionID, userID, models.ReconfirmationDeclined).
+		WillReturnRows(sqlmock.NewRows(reconfirmationColumns))
+	mock.ExpectRollback()
+
+	_, _, err := repo.Decline(context.Background(), sessionID, userID)
+
+	assert.ErrorIs(t, err, repositories.ErrReconfirmationClosed)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,274 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/BuzzLyutic/Skill-sharing-web-platform/models"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+var (
+	ErrSeriesNotFound = errors.New("session series not found")
+)
+
+// SeriesRepository обрабатывает операции с базой данных для повторяющихся серий сессий
+type SeriesRepository struct {
+	db *sqlx.DB
+}
+
+// NewSeriesRepository создает новый репозиторий серий
+func NewSeriesRepository(db *sqlx.DB) *SeriesRepository {
+	return &SeriesRepository{db: db}
+}
+
+// Create создает серию и все ее занятия в одной транзакции.
+// occurrences - даты начала занятий, уже развернутые из RRULE.
+func (r *SeriesRepository) Create(ctx context.Context, creatorID uuid.UUID, req models.SessionSeriesRequest, rrule string, occurrences []time.Time) (*models.SessionSeries, []models.Session, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: failed to begin series transaction: %v", ErrDatabase, err)
+	}
+	defer tx.Rollback()
+
+	var series models.SessionSeries
+	query := `
+        INSERT INTO session_series (creator_id, rrule, dtstart, title, description, category, location, max_participants, duration_minutes, time_zone, tags,
+            latitude, longitude, venue_address, meeting_url, join_instructions, visibility,
+            requires_approval)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18)
+        RETURNING *`
+	err = tx.GetContext(ctx, &series, query,
+		creatorID, rrule, req.DateTime, req.Title, req.Description, req.Category, req.Location, req.MaxParticipants,
+		req.DurationMinutes, req.TimeZone, pq.Array(req.Tags), req.Latitude, req.Longitude, req.VenueAddress,
+		req.MeetingURL, req.JoinInstructions, req.Visibility, req.RequiresApproval)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: failed to create series: %v", ErrDatabase, err)
+	}
+
+	sessions := make([]models.Session, 0, len(occurrences))
+	occurrenceQuery := `
+        INSERT INTO sessions (title, description, category, date_time, location, max_participants, creator_id, series_id, original_start, end_time, time_zone, tags, search_language,
+            latitude, longitude, venue_address, meeting_url, join_instructions, visibility,
+            requires_approval)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $4, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19)
+        RETURNING *`
+	for _, start := range occurrences {
+		var session models.Session
+		err = tx.GetContext(ctx, &session, occurrenceQuery,
+			req.Title, req.Description, req.Category, start, req.Location, req.MaxParticipants, creatorID, series.ID,
+			start.Add(series.Duration()), series.TimeZone, series.Tags, req.SearchLanguage,
+			series.Latitude, series.Longitude, series.VenueAddress, series.MeetingURL, series.JoinInstructions, series.Visibility, series.RequiresApproval)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%w: failed to create series occurrence at %s: %v", ErrDatabase, start, err)
+		}
+		sessions = append(sessions, session)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, nil, fmt.Errorf("%w: failed to commit series transaction: %v", ErrDatabase, err)
+	}
+	return &series, sessions, nil
+}
+
+// GetByID извлекает серию по идентификатору
+func (r *SeriesRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.SessionSeries, error) {
+	var series models.SessionSeries
+	query := `SELECT * FROM session_series WHERE id = $1`
+	err := r.db.GetContext(ctx, &series, query, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrSeriesNotFound
+		}
+		return nil, fmt.Errorf("%w: failed to get series by id %s: %v", ErrDatabase, id, err)
+	}
+	return &series, nil
+}
+
+// GetOccurrences возвращает занятия серии в хронологическом порядке.
+// upcomingOnly - только опубликованные занятия, которые еще не начались.
+func (r *SeriesRepository) GetOccurrences(ctx context.Context, seriesID uuid.UUID, upcomingOnly bool) ([]models.Session, error) {
+	sessions := []models.Session{}
+	query := `SELECT * FROM sessions WHERE series_id = $1`
+	if upcomingOnly {
+		query += ` AND date_time > NOW() AND status = 'published'`
+	}
+	query += ` ORDER BY original_start ASC`
+	err := r.db.SelectContext(ctx, &sessions, query, seriesID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		log.Printf("ERROR getting occurrences for series %s: %v", seriesID, err)
+		return nil, fmt.Errorf("%w: failed to get series occurrences: %v", ErrDatabase, err)
+	}
+	return sessions, nil
+}
+
+// GetExdates возвращает исходные даты отмененных занятий серии
+func (r *SeriesRepository) GetExdates(ctx context.Context, seriesID uuid.UUID) ([]time.Time, error) {
+	exdates := []time.Time{}
+	query := `SELECT original_start FROM session_series_exdates WHERE series_id = $1 ORDER BY original_start ASC`
+	err := r.db.SelectContext(ctx, &exdates, query, seriesID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		log.Printf("ERROR getting exdates for series %s: %v", seriesID, err)
+		return nil, fmt.Errorf("%w: failed to get series exdates: %v", ErrDatabase, err)
+	}
+	return exdates, nil
+}
+
+// Delete удаляет серию вместе со всеми занятиями (ON DELETE CASCADE)
+func (r *SeriesRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM session_series WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("%w: failed to delete series %s: %v", ErrDatabase, id, err)
+	}
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		return ErrSeriesNotFound
+	}
+	return nil
+}
+
+// CancelFollowing отменяет занятие from и все последующие предстоящие занятия серии.
+// Правило серии не меняется: отмененные занятия выгружаются в ICS как EXDATE.
+// actorID записывается в историю версий занятий как автор изменения.
+func (r *SeriesRepository) CancelFollowing(ctx context.Context, seriesID, actorID uuid.UUID, from time.Time, reason string) ([]models.Session, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to begin series transaction: %v", ErrDatabase, err)
+	}
+	defer tx.Rollback()
+	if err := setActor(ctx, tx, actorID); err != nil {
+		return nil, err
+	}
+
+	cancelled := []models.Session{}
+	query := `
+        UPDATE sessions
+        SET status = $3, cancelled_at = NOW(), cancellation_reason = NULLIF($4, ''), updated_at = NOW()
+        WHERE series_id = $1 AND original_start >= $2 AND status = ANY($5)
+        RETURNING *`
+	err = tx.SelectContext(ctx, &cancelled, query, seriesID, from, models.SessionStatusCancelled, reason,
+		pq.Array(models.StatusesBefore(models.SessionStatusCancelled)))
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("%w: failed to cancel following occurrences: %v", ErrDatabase, err)
+	}
+
+	waitlistQuery := `
+        DELETE FROM session_waitlist w
+        USING sessions s
+        WHERE w.session_id = s.id AND s.series_id = $1 AND s.original_start >= $2 AND s.status = $3`
+	if _, err := tx.ExecContext(ctx, waitlistQuery, seriesID, from, models.SessionStatusCancelled); err != nil {
+		return nil, fmt.Errorf("%w: failed to clear waitlists of cancelled occurrences: %v", ErrDatabase, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("%w: failed to commit series transaction: %v", ErrDatabase, err)
+	}
+	return cancelled, nil
+}
+
+// UpdateFollowing применяет изменение к занятию from и всем последующим.
+// Если truncatedRule пустой, изменяется вся серия на месте; иначе исходная серия
+// обрезается по truncatedRule, а занятия начиная с from переносятся в новую серию tail.
+// Время занятий сдвигается на shift относительно их исходного времени по правилу,
+// участники сохраняются. actorID записывается в историю версий занятий.
+func (r *SeriesRepository) UpdateFollowing(ctx context.Context, seriesID, actorID uuid.UUID, from time.Time, truncatedRule string, tail models.SessionSeries, shift time.Duration) (*models.SessionSeries, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to begin series transaction: %v", ErrDatabase, err)
+	}
+	defer tx.Rollback()
+	if err := setActor(ctx, tx, actorID); err != nil {
+		return nil, err
+	}
+
+	var target models.SessionSeries
+	if truncatedRule == "" {
+		query := `
+            UPDATE session_series
+            SET rrule = $2, dtstart = $3, title = $4, description = $5, category = $6, location = $7, max_participants = $8,
+                duration_minutes = $9, time_zone = $10, tags = $11, latitude = $12, longitude = $13, venue_address = $14,
+                meeting_url = $15, join_instructions = $16, visibility = $17, requires_approval = $18
+            WHERE id = $1
+            RETURNING *`
+		err = tx.GetContext(ctx, &target, query, seriesID,
+			tail.RRule, tail.DTStart, tail.Title, tail.Description, tail.Category, tail.Location, tail.MaxParticipants,
+			tail.DurationMinutes, tail.TimeZone, tail.Tags, tail.Latitude, tail.Longitude, tail.VenueAddress,
+			tail.MeetingURL, tail.JoinInstructions, tail.Visibility, tail.RequiresApproval)
+	} else {
+		if _, err := tx.ExecContext(ctx, `UPDATE session_series SET rrule = $2 WHERE id = $1`, seriesID, truncatedRule); err != nil {
+			return nil, fmt.Errorf("%w: failed to truncate series rule: %v", ErrDatabase, err)
+		}
+		query := `
+            INSERT INTO session_series (creator_id, rrule, dtstart, title, description, category, location, max_participants, duration_minutes, time_zone, tags,
+                latitude, longitude, venue_address, meeting_url, join_instructions, visibility,
+                requires_approval)
+            VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18)
+            RETURNING *`
+		err = tx.GetContext(ctx, &target, query,
+			tail.CreatorID, tail.RRule, tail.DTStart, tail.Title, tail.Description, tail.Category, tail.Location, tail.MaxParticipants,
+			tail.DurationMinutes, tail.TimeZone, tail.Tags, tail.Latitude, tail.Longitude, tail.VenueAddress,
+			tail.MeetingURL, tail.JoinInstructions, tail.Visibility, tail.RequiresApproval)
+	}
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrSeriesNotFound
+		}
+		return nil, fmt.Errorf("%w: failed to save series: %v", ErrDatabase, err)
+	}
+
+	shiftSeconds := shift.Seconds()
+	occurrencesQuery := `
+        UPDATE sessions
+        SET series_id = $3,
+            original_start = original_start + make_interval(secs => $4),
+            date_time = original_start + make_interval(secs => $4),
+            end_time = original_start + make_interval(secs => $4) + make_interval(mins => $10),
+            time_zone = $11, tags = $12, latitude = $13, longitude = $14, venue_address = $15,
+            meeting_url = $16, join_instructions = $17, visibility = $18, requires_approval = $19,
+            title = $5, description = $6, category = $7, location = $8, max_participants = $9,
+            is_override = FALSE,
+            updated_at = NOW()
+        WHERE series_id = $1 AND original_start >= $2`
+	_, err = tx.ExecContext(ctx, occurrencesQuery, seriesID, from, target.ID, shiftSeconds,
+		tail.Title, tail.Description, tail.Category, tail.Location, tail.MaxParticipants, tail.DurationMinutes, tail.TimeZone, tail.Tags,
+		tail.Latitude, tail.Longitude, tail.VenueAddress, tail.MeetingURL, tail.JoinInstructions, tail.Visibility, tail.RequiresApproval)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to update following occurrences: %v", ErrDatabase, err)
+	}
+
+	exdatesQuery := `
+        UPDATE session_series_exdates
+        SET series_id = $3, original_start = original_start + make_interval(secs => $4)
+        WHERE series_id = $1 AND original_start >= $2`
+	if _, err := tx.ExecContext(ctx, exdatesQuery, seriesID, from, target.ID, shiftSeconds); err != nil {
+		return nil, fmt.Errorf("%w: failed to move following exdates: %v", ErrDatabase, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("%w: failed to commit series transaction: %v", ErrDatabase, err)
+	}
+	return &target, nil
+}
+
+// MaxParticipantsFrom возвращает наибольшее число участников среди занятий серии, начиная с from
+func (r *SeriesRepository) MaxParticipantsFrom(ctx context.Context, seriesID uuid.UUID, from time.Time) (int, error) {
+	var joined int
+	query := `
+        SELECT COALESCE(MAX(cnt), 0) FROM (
+            SELECT COUNT(sp.user_id) AS cnt
+            FROM sessions s
+            LEFT JOIN session_participants sp ON sp.session_id = s.id
+            WHERE s.series_id = $1 AND s.original_start >= $2
+            GROUP BY s.id
+        ) counts`
+	if err := r.db.GetContext(ctx, &joined, query, seriesID, from); err != nil {
+		return 0, fmt.Errorf("%w: failed to count series participants: %v", ErrDatabase, err)
+	}
+	return joined, nil
+}
@@ -25,6 +25,11 @@ var (
 	ErrDatabase             = errors.New("database error")          // Общая ошибка БД
 	ErrForbidden            = errors.New("operation forbidden")
 	ErrParticipantNotFound = errors.New("participant not found for this session")
+	ErrAlreadyWaitlisted    = errors.New("user is already on the waitlist for this session")
+	ErrSessionNotOpen       = errors.New("session is not open for joining")
+	ErrInvalidStatusTransition = errors.New("session status transition is not allowed")
+	ErrSessionVersionNotFound  = errors.New("session version not found")
+	ErrCapacityBelowParticipants = errors.New("max_participants is below the number of joined participants")
 )
 
 
@@ -67,19 +72,64 @@ func (r *SessionRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.
 
 // Create создает новый сеанс
 func (r *SessionRepository) Create(ctx context.Context, creatorID uuid.UUID, req models.SessionRequest) (*models.Session, error) {
+	return insertSession(ctx, r.db, creatorID, req)
+}
+
+// CreateMany создает несколько сессий в одной транзакции: либо все, либо ни одной
+func (r *SessionRepository) CreateMany(ctx context.Context, creatorID uuid.UUID, reqs []models.SessionRequest) ([]models.Session, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to begin transaction: %v", ErrDatabase, err)
+	}
+	defer tx.Rollback()
+
+	sessions := make([]models.Session, 0, len(reqs))
+	for i, req := range reqs {
+		session, err := insertSession(ctx, tx, creatorID, req)
+		if err != nil {
+			return nil, fmt.Errorf("session %d of %d: %w", i+1, len(reqs), err)
+		}
+		sessions = append(sessions, *session)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("%w: failed to commit sessions: %v", ErrDatabase, err)
+	}
+	return sessions, nil
+}
+
+// insertSession добавляет сессию через q (соединение или транзакцию)
+func insertSession(ctx context.Context, q sqlx.QueryerContext, creatorID uuid.UUID, req models.SessionRequest) (*models.Session, error) {
 	var createdSession models.Session
 	query := `
-        INSERT INTO sessions (title, description, category, date_time, location, max_participants, creator_id)
-        VALUES ($1, $2, $3, $4, $5, $6, $7)
+        INSERT INTO sessions (title, description, category, date_time, location, max_participants, creator_id, end_time, time_zone, status, tags, search_language,
+            latitude, longitude, venue_address, meeting_url, join_instructions, visibility,
+            requires_approval)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19)
         RETURNING *`
-	err := r.db.GetContext(ctx, &createdSession, query, // Используем GetContext
+	status := models.SessionStatusPublished
+	if req.Draft {
+		status = models.SessionStatusDraft
+	}
+	err := sqlx.GetContext(ctx, q, &createdSession, query,
 		req.Title,
 		req.Description,
 		req.Category,
 		req.DateTime,
 		req.Location,
 		req.MaxParticipants,
-		creatorID, // ID создателя теперь последний аргумент
+		creatorID,
+		req.EndTime, // req должен быть нормализован (SessionRequest.Normalize)
+		req.TimeZone,
+		status,
+		pq.Array(req.Tags),
+		req.SearchLanguage,
+		req.Latitude,
+		req.Longitude,
+		req.VenueAddress,
+		req.MeetingURL,
+		req.JoinInstructions,
+		req.Visibility,
+		req.RequiresApproval,
 	)
 	if err != nil {
 		// log.Printf("Error creating session for user %s: %v", creatorID, err)
@@ -88,15 +138,45 @@ func (r *SessionRepository) Create(ctx context.Context, creatorID uuid.UUID, req
 	return &createdSession, nil
 }
 
-// Update обновляет существующий сеанс
-func (r *SessionRepository) Update(ctx context.Context, id uuid.UUID, req models.SessionRequest) (*models.Session, error) {
+// Update обновляет существующий сеанс. actorID записывается в историю версий как автор изменения.
+// Если новая вместимость меньше числа участников, изменение отклоняется с ErrCapacityBelowParticipants,
+// а при req.CapacityPolicy = waitlist последние присоединившиеся переводятся в начало очереди ожидания.
+// При увеличении вместимости свободные места занимают пользователи из очереди.
+func (r *SessionRepository) Update(ctx context.Context, id, actorID uuid.UUID, req models.SessionRequest) (*models.Session, *models.SeatChanges, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: failed to begin update transaction: %v", ErrDatabase, err)
+	}
+	defer tx.Rollback()
+	if err := setActor(ctx, tx, actorID); err != nil {
+		return nil, nil, err
+	}
+
+	// Блокировка сессии не дает присоединиться новым участникам между подсчетом и изменением
+	seats, err := lockSessionSeats(ctx, tx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	var participants int
+	if err := tx.GetContext(ctx, &participants, `SELECT COUNT(*) FROM session_participants WHERE session_id = $1`, id); err != nil {
+		return nil, nil, fmt.Errorf("%w: failed to count participants: %v", ErrDatabase, err)
+	}
+	overflow := participants > req.MaxParticipants
+	if overflow && req.CapacityPolicy != models.CapacityPolicyWaitlist {
+		return nil, nil, fmt.Errorf("%w: %d already joined", ErrCapacityBelowParticipants, participants)
+	}
+
 	var updatedSession models.Session
 	query := `
         UPDATE sessions
-        SET title = $2, description = $3, category = $4, date_time = $5, location = $6, max_participants = $7, updated_at = NOW()
+        SET title = $2, description = $3, category = $4, date_time = $5, location = $6, max_participants = $7,
+            end_time = $8, time_zone = $9, tags = $10, search_language = $11,
+            latitude = $12, longitude = $13, venue_address = $14, meeting_url = $15, join_instructions = $16,
+            visibility = $17, requires_approval = $18, updated_at = NOW(),
+            is_override = (series_id IS NOT NULL) -- Занятие серии, измененное отдельно, становится исключением
         WHERE id = $1
         RETURNING *`
-	err := r.db.GetContext(ctx, &updatedSession, query, // Используем GetContext
+	err = tx.GetContext(ctx, &updatedSession, query,
 		id, // id теперь $1
 		req.Title,
 		req.Description,
@@ -104,47 +184,186 @@ func (r *SessionRepository) Update(ctx context.Context, id uuid.UUID, req models
 		req.DateTime,
 		req.Location,
 		req.MaxParticipants,
+		req.EndTime,
+		req.TimeZone,
+		pq.Array(req.Tags),
+		req.SearchLanguage,
+		req.Latitude,
+		req.Longitude,
+		req.VenueAddress,
+		req.MeetingURL,
+		req.JoinInstructions,
+		req.Visibility,
+		req.RequiresApproval,
 	)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			// Если сессия не найдена для обновления
-			return nil, ErrSessionNotFound
+			return nil, nil, ErrSessionNotFound
 		}
 		// log.Printf("Error updating session %s: %v", id, err)
-		return nil, fmt.Errorf("%w: failed to update session %s: %v", ErrDatabase, id, err)
+		return nil, nil, fmt.Errorf("%w: failed to update session %s: %v", ErrDatabase, id, err)
+	}
+
+	changes := &models.SeatChanges{}
+	if overflow {
+		changes.Waitlisted, err = moveOverflowToWaitlist(ctx, tx, id, req.MaxParticipants)
+	} else if seats.Status == models.SessionStatusPublished {
+		changes.Promoted, err = promoteFromWaitlist(ctx, tx, id, req.MaxParticipants)
 	}
-	return &updatedSession, nil
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, nil, fmt.Errorf("%w: failed to commit session update: %v", ErrDatabase, err)
+	}
+	return &updatedSession, changes, nil
 }
 
-// Delete удаляет сеанс
+// Delete удаляет сеанс. Если это занятие серии, его исходное время
+// записывается в session_series_exdates, чтобы оно не вернулось в календари через RRULE.
 func (r *SessionRepository) Delete(ctx context.Context, id uuid.UUID) error {
-	query := `DELETE FROM sessions WHERE id = $1`
-	result, err := r.db.ExecContext(ctx, query, id) // Используем ExecContext
+	var deletedCount int
+	query := `
+        WITH deleted AS (
+            DELETE FROM sessions WHERE id = $1
+            RETURNING series_id, original_start
+        ), excluded AS (
+            INSERT INTO session_series_exdates (series_id, original_start)
+            SELECT d.series_id, d.original_start FROM deleted d
+            WHERE d.series_id IS NOT NULL
+              AND NOT EXISTS (
+                  SELECT 1 FROM session_series_exdates e
+                  WHERE e.series_id = d.series_id AND e.original_start = d.original_start
+              )
+        )
+        SELECT COUNT(*) FROM deleted`
+	err := r.db.GetContext(ctx, &deletedCount, query, id)
 	if err != nil {
 		// log.Printf("Error deleting session %s: %v", id, err)
 		return fmt.Errorf("%w: failed to delete session %s: %v", ErrDatabase, id, err)
 	}
 
-	rowsAffected, err := result.RowsAffected()
+	if deletedCount == 0 {
+		return ErrSessionNotFound // Сессия не найдена для удаления
+	}
+
+	return nil
+}
+
+// Publish публикует черновик от имени actorID
+func (r *SessionRepository) Publish(ctx context.Context, id, actorID uuid.UUID) (*models.Session, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
 	if err != nil {
-		// log.Printf("Error getting rows affected after deleting session %s: %v", id, err)
-		return fmt.Errorf("%w: failed to check rows affected for session %s: %v", ErrDatabase, id, err)
+		return nil, fmt.Errorf("%w: failed to begin publish transaction: %v", ErrDatabase, err)
+	}
+	defer tx.Rollback()
+	if err := setActor(ctx, tx, actorID); err != nil {
+		return nil, err
 	}
 
-	if rowsAffected == 0 {
-		return ErrSessionNotFound // Сессия не найдена для удаления
+	var session models.Session
+	query := `
+        UPDATE sessions SET status = $2, updated_at = NOW()
+        WHERE id = $1 AND status = ANY($3)
+        RETURNING *`
+	err = tx.GetContext(ctx, &session, query, id, models.SessionStatusPublished, pq.Array(models.StatusesBefore(models.SessionStatusPublished)))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, r.transitionError(ctx, id, models.SessionStatusPublished)
+		}
+		return nil, fmt.Errorf("%w: failed to publish session %s: %v", ErrDatabase, id, err)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("%w: failed to commit publish transaction: %v", ErrDatabase, err)
+	}
+	return &session, nil
+}
+
+// Cancel отменяет сессию, сохраняя запись и список участников. Очередь ожидания очищается;
+// ID пользователей, стоявших в ней, возвращаются, чтобы контроллер мог их уведомить.
+func (r *SessionRepository) Cancel(ctx context.Context, id, actorID uuid.UUID, reason string) (*models.Session, []uuid.UUID, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: failed to begin cancel transaction: %v", ErrDatabase, err)
+	}
+	defer tx.Rollback()
+	if err := setActor(ctx, tx, actorID); err != nil {
+		return nil, nil, err
 	}
 
+	var session models.Session
+	query := `
+        UPDATE sessions
+        SET status = $2, cancelled_at = NOW(), cancellation_reason = NULLIF($3, ''), updated_at = NOW()
+        WHERE id = $1 AND status = ANY($4)
+        RETURNING *`
+	err = tx.GetContext(ctx, &session, query, id, models.SessionStatusCancelled, reason, pq.Array(models.StatusesBefore(models.SessionStatusCancelled)))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil, r.transitionError(ctx, id, models.SessionStatusCancelled)
+		}
+		return nil, nil, fmt.Errorf("%w: failed to cancel session %s: %v", ErrDatabase, id, err)
+	}
+
+	waitlisted := []uuid.UUID{}
+	err = tx.SelectContext(ctx, &waitlisted, `DELETE FROM session_waitlist WHERE session_id = $1 RETURNING user_id`, id)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, nil, fmt.Errorf("%w: failed to clear waitlist of cancelled session %s: %v", ErrDatabase, id, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, nil, fmt.Errorf("%w: failed to commit cancel transaction: %v", ErrDatabase, err)
+	}
+	return &session, waitlisted, nil
+}
+
+// CompleteEnded помечает завершенными опубликованные сессии, закончившиеся к моменту now
+func (r *SessionRepository) CompleteEnded(ctx context.Context, now time.Time) (int64, error) {
+	query := `
+        UPDATE sessions SET status = $1, updated_at = NOW()
+        WHERE status = ANY($2) AND end_time <= $3`
+	result, err := r.db.ExecContext(ctx, query, models.SessionStatusCompleted, pq.Array(models.StatusesBefore(models.SessionStatusCompleted)), now)
+	if err != nil {
+		return 0, fmt.Errorf("%w: failed to complete ended sessions: %v", ErrDatabase, err)
+	}
+	completed, _ := result.RowsAffected()
+	return completed, nil
+}
+
+// setActor передает триггеру истории версий автора изменений до конца транзакции tx.
+// uuid.Nil означает изменение системой.
+func setActor(ctx context.Context, tx *sqlx.Tx, actorID uuid.UUID) error {
+	if actorID == uuid.Nil {
+		return nil
+	}
+	if _, err := tx.ExecContext(ctx, `SELECT set_config('skillshare.actor_id', $1, true)`, actorID.String()); err != nil {
+		return fmt.Errorf("%w: failed to set change author: %v", ErrDatabase, err)
+	}
 	return nil
 }
 
+// transitionError объясняет, почему условный UPDATE статуса не затронул ни одной строки
+func (r *SessionRepository) transitionError(ctx context.Context, id uuid.UUID, to models.SessionStatus) error {
+	var current models.SessionStatus
+	err := r.db.GetContext(ctx, &current, `SELECT status FROM sessions WHERE id = $1`, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrSessionNotFound
+		}
+		return fmt.Errorf("%w: failed to get status of session %s: %v", ErrDatabase, id, err)
+	}
+	return fmt.Errorf("%w: %s -> %s", ErrInvalidStatusTransition, current, to)
+}
+
 // GetParticipants получает доступ ко всем участникам сеанса
 func (r *SessionRepository) GetParticipants(ctx context.Context, sessionID uuid.UUID) ([]models.User, error) {
 	users := []models.User{}
 	// Выбираем нужные поля пользователя, избегаем SELECT *
 	// Исключаем хеш пароля и рефреш токен
 	query := `
-        SELECT u.id, u.email, u.name, u.bio, u.skills, u.average_rating, u.created_at, u.updated_at, u.role, u.oauth_provider, u.oauth_id
+        SELECT u.id, u.email, u.name, u.bio, u.skills, u.average_rating, u.time_zone, u.created_at, u.updated_at, u.role, u.oauth_provider, u.oauth_id
         FROM users u
         JOIN session_participants sp ON u.id = sp.user_id
         WHERE sp.session_id = $1`
@@ -187,51 +406,294 @@ func (r *SessionRepository) IsParticipant(ctx context.Context, sessionID, userID
 	return exists, nil
 }
 
+// IsWaitlisted проверяет, стоит ли пользователь в очереди ожидания сессии
+func (r *SessionRepository) IsWaitlisted(ctx context.Context, sessionID, userID uuid.UUID) (bool, error) {
+	var exists bool
+	query := `SELECT EXISTS (SELECT 1 FROM session_waitlist WHERE session_id = $1 AND user_id = $2)`
+	if err := r.db.GetContext(ctx, &exists, query, sessionID, userID); err != nil {
+		return false, fmt.Errorf("%w: failed to check waitlist status: %v", ErrDatabase, err)
+	}
+	return exists, nil
+}
 
-// JoinSession добавляет пользователя в сессию (вставляет запись в session_participants)
-func (r *SessionRepository) JoinSession(ctx context.Context, sessionID, userID uuid.UUID) error {
-    // Проверка, не является ли пользователь уже участником
-    isAlreadyParticipant, err := r.IsParticipant(ctx, sessionID, userID)
-    if err != nil {
-        return err // Ошибка уже обернута в IsParticipant
-    }
-    if isAlreadyParticipant {
-        return ErrAlreadyJoined
-    }
 
-	query := `INSERT INTO session_participants (session_id, user_id) VALUES ($1, $2)`
-	_, err = r.db.ExecContext(ctx, query, sessionID, userID) // Используем ExecContext
+// JoinSession добавляет пользователя в сессию или, если мест нет, в конец очереди ожидания.
+// Вся проверка выполняется в одной транзакции под блокировкой строки сессии,
+// поэтому два пользователя не могут одновременно занять последнее место.
+// Для сессий invite_only код приглашения обязателен, и его использование засчитывается в той же транзакции.
+// Для сессий с одобрением создается заявка (статус pending), которая не занимает место до решения организаторов.
+func (r *SessionRepository) JoinSession(ctx context.Context, sessionID, userID uuid.UUID, req models.JoinSessionRequest) (*models.JoinResult, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
 	if err != nil {
-		return fmt.Errorf("%w: failed to join session: %v", ErrDatabase, err)
+		return nil, fmt.Errorf("%w: failed to begin join transaction: %v", ErrDatabase, err)
 	}
-	return nil
+	defer tx.Rollback() // Игнорируется после успешного Commit
+
+	seats, err := lockSessionSeats(ctx, tx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	if seats.Status != models.SessionStatusPublished {
+		return nil, ErrSessionNotOpen
+	}
+
+	var state struct {
+		IsParticipant bool `db:"is_participant"`
+		IsWaitlisted  bool `db:"is_waitlisted"`
+		IsPending     bool `db:"is_pending"`
+		Participants  int  `db:"participants"`
+	}
+	stateQuery := `
+        SELECT
+            EXISTS (SELECT 1 FROM session_participants WHERE session_id = $1 AND user_id = $2) AS is_participant,
+            EXISTS (SELECT 1 FROM session_waitlist WHERE session_id = $1 AND user_id = $2) AS is_waitlisted,
+            EXISTS (SELECT 1 FROM session_join_requests WHERE session_id = $1 AND user_id = $2 AND status = 'pending') AS is_pending,
+            (SELECT COUNT(*) FROM session_participants WHERE session_id = $1) AS participants`
+	if err := tx.GetContext(ctx, &state, stateQuery, sessionID, userID); err != nil {
+		return nil, fmt.Errorf("%w: failed to check participation state: %v", ErrDatabase, err)
+	}
+	if state.IsParticipant {
+		return nil, ErrAlreadyJoined
+	}
+	if state.IsWaitlisted {
+		return nil, ErrAlreadyWaitlisted
+	}
+	if state.IsPending {
+		return nil, ErrJoinRequestPending
+	}
+	if seats.Visibility == models.VisibilityInviteOnly {
+		if err := consumeInvite(ctx, tx, sessionID, req.InviteCode); err != nil {
+			return nil, err
+		}
+	}
+
+	var result *models.JoinResult
+	if seats.RequiresApproval {
+		if err := createJoinRequest(ctx, tx, sessionID, userID, req.Message); err != nil {
+			return nil, err
+		}
+		result = &models.JoinResult{Status: models.ParticipationStatusPending}
+	} else {
+		result, err = takeSeatOrWaitlist(ctx, tx, sessionID, userID, state.Participants, seats.MaxParticipants)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("%w: failed to commit join transaction: %v", ErrDatabase, err)
+	}
+	return result, nil
+}
+
+// takeSeatOrWaitlist добавляет пользователя в участники, если participants < maxParticipants,
+// иначе в конец очереди ожидания. Должна вызываться внутри транзакции после lockSessionSeats.
+func takeSeatOrWaitlist(ctx context.Context, tx *sqlx.Tx, sessionID, userID uuid.UUID, participants, maxParticipants int) (*models.JoinResult, error) {
+	result := &models.JoinResult{Status: models.ParticipationStatusJoined}
+	if participants < maxParticipants {
+		query := `INSERT INTO session_participants (session_id, user_id) VALUES ($1, $2)`
+		if _, err := tx.ExecContext(ctx, query, sessionID, userID); err != nil {
+			return nil, fmt.Errorf("%w: failed to join session: %v", ErrDatabase, err)
+		}
+		return result, nil
+	}
+	// Мест нет - ставим в очередь. Строка сессии заблокирована, поэтому
+	// позиция нового пользователя равна текущей длине очереди.
+	query := `
+        INSERT INTO session_waitlist (session_id, user_id) VALUES ($1, $2)
+        RETURNING (SELECT COUNT(*) FROM session_waitlist WHERE session_id = $1) + 1`
+	if err := tx.GetContext(ctx, &result.WaitlistPosition, query, sessionID, userID); err != nil {
+		return nil, fmt.Errorf("%w: failed to add user to waitlist: %v", ErrDatabase, err)
+	}
+	result.Status = models.ParticipationStatusWaitlisted
+	return result, nil
 }
 
-// LeaveSession удаляет пользователя из сессии (удаляет запись из session_participants)
-func (r *SessionRepository) LeaveSession(ctx context.Context, sessionID, userID uuid.UUID) error {
-	query := `DELETE FROM session_participants WHERE session_id = $1 AND user_id = $2`
-	result, err := r.db.ExecContext(ctx, query, sessionID, userID) // Используем ExecContext
+// LeaveSession удаляет пользователя из участников (или из очереди ожидания) сессии
+// либо отзывает его заявку на участие. Если освободилось место, первые пользователи из очереди автоматически становятся участниками;
+// их ID возвращаются, чтобы контроллер мог отправить уведомления.
+func (r *SessionRepository) LeaveSession(ctx context.Context, sessionID, userID uuid.UUID) ([]uuid.UUID, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to begin leave transaction: %v", ErrDatabase, err)
+	}
+	defer tx.Rollback()
+
+	seats, err := lockSessionSeats(ctx, tx, sessionID)
 	if err != nil {
-		// log.Printf("Error leaving session %s for user %s: %v", sessionID, userID, err)
-		return fmt.Errorf("%w: failed to leave session: %v", ErrDatabase, err)
+		return nil, err
 	}
 
+	result, err := tx.ExecContext(ctx, `DELETE FROM session_participants WHERE session_id = $1 AND user_id = $2`, sessionID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to leave session: %v", ErrDatabase, err)
+	}
 	rowsAffected, err := result.RowsAffected()
 	if err != nil {
-		// log.Printf("Error getting rows affected after leaving session %s for user %s: %v", sessionID, userID, err)
-		return fmt.Errorf("%w: failed to check rows affected for leave session: %v", ErrDatabase, err)
+		return nil, fmt.Errorf("%w: failed to check rows affected for leave session: %v", ErrDatabase, err)
 	}
 
 	if rowsAffected == 0 {
-		// Пользователь не был найден в этой сессии для удаления
-		return ErrNotJoined // Возвращаем кастомную ошибку
+		// Пользователь не участник - возможно, он уходит из очереди ожидания
+		result, err = tx.ExecContext(ctx, `DELETE FROM session_waitlist WHERE session_id = $1 AND user_id = $2`, sessionID, userID)
+		if err != nil {
+			return nil, fmt.Errorf("%w: failed to leave waitlist: %v", ErrDatabase, err)
+		}
+		rowsAffected, err = result.RowsAffected()
+		if err != nil {
+			return nil, fmt.Errorf("%w: failed to check rows affected for leave waitlist: %v", ErrDatabase, err)
+		}
+		if rowsAffected == 0 {
+			// Не в очереди - возможно, пользователь отзывает заявку на участие
+			if err := withdrawJoinRequest(ctx, tx, sessionID, userID); err != nil {
+				return nil, err
+			}
+		}
+		if err := tx.Commit(); err != nil {
+			return nil, fmt.Errorf("%w: failed to commit leave transaction: %v", ErrDatabase, err)
+		}
+		return nil, nil // Место в сессии не освобождалось
 	}
 
-	return nil
+	var promoted []uuid.UUID
+	if seats.Status == models.SessionStatusPublished { // В отмененную или прошедшую сессию из очереди не переводим
+		promoted, err = promoteFromWaitlist(ctx, tx, sessionID, seats.MaxParticipants)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("%w: failed to commit leave transaction: %v", ErrDatabase, err)
+	}
+	return promoted, nil
 }
 
+// sessionSeats - данные сессии, от которых зависит состав участников
+type sessionSeats struct {
+	MaxParticipants  int                      `db:"max_participants"`
+	Status           models.SessionStatus     `db:"status"`
+	Visibility       models.SessionVisibility `db:"visibility"`
+	RequiresApproval bool                     `db:"requires_approval"`
+}
+
+// lockSessionSeats блокирует строку сессии до конца транзакции и возвращает max_participants, статус,
+// видимость и признак одобрения заявок.
+// Все операции, меняющие состав участников, должны вызывать ее первой.
+func lockSessionSeats(ctx context.Context, tx *sqlx.Tx, sessionID uuid.UUID) (*sessionSeats, error) {
+	var seats sessionSeats
+	query := `SELECT max_participants, status, visibility, requires_approval FROM sessions WHERE id = $1 FOR UPDATE`
+	err := tx.GetContext(ctx, &seats, query, sessionID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrSessionNotFound
+		}
+		return nil, fmt.Errorf("%w: failed to lock session %s: %v", ErrDatabase, sessionID, err)
+	}
+	return &seats, nil
+}
 
-// GetRecommendedSessionsForUser получает рекомендуемые сессии для конкретного пользователя
+// promoteFromWaitlist переводит пользователей из начала очереди в участники, пока есть свободные места.
+// Должна вызываться внутри транзакции после lockSessionSeats.
+func promoteFromWaitlist(ctx context.Context, tx *sqlx.Tx, sessionID uuid.UUID, maxParticipants int) ([]uuid.UUID, error) {
+	promoted := []uuid.UUID{}
+	query := `
+        WITH free AS (
+            SELECT GREATEST($2 - COUNT(*), 0) AS seats FROM session_participants WHERE session_id = $1
+        ), next_in_line AS (
+            SELECT user_id FROM session_waitlist
+            WHERE session_id = $1
+            ORDER BY queued_at ASC, user_id ASC
+            LIMIT (SELECT seats FROM free)
+        ), moved AS (
+            DELETE FROM session_waitlist w
+            USING next_in_line n
+            WHERE w.session_id = $1 AND w.user_id = n.user_id
+            RETURNING w.user_id
+        )
+        INSERT INTO session_participants (session_id, user_id)
+        SELECT $1, user_id FROM moved
+        RETURNING user_id`
+	err := tx.SelectContext(ctx, &promoted, query, sessionID, maxParticipants)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("%w: failed to promote users from waitlist: %v", ErrDatabase, err)
+	}
+	return promoted, nil
+}
+
+// moveOverflowToWaitlist переводит последних присоединившихся участников, не помещающихся
+// в maxParticipants, в начало очереди ожидания (сохраняя их взаимный порядок) и возвращает их ID.
+// Должна вызываться внутри транзакции после lockSessionSeats.
+func moveOverflowToWaitlist(ctx context.Context, tx *sqlx.Tx, sessionID uuid.UUID, maxParticipants int) ([]uuid.UUID, error) {
+	moved := []uuid.UUID{}
+	query := `
+        WITH overflow AS (
+            SELECT user_id, ROW_NUMBER() OVER (ORDER BY joined_at DESC NULLS LAST, user_id DESC) AS rank
+            FROM session_participants
+            WHERE session_id = $1
+            ORDER BY joined_at DESC NULLS LAST, user_id DESC
+            LIMIT GREATEST((SELECT COUNT(*) FROM session_participants WHERE session_id = $1) - $2, 0)
+        ), removed AS (
+            DELETE FROM session_participants p
+            USING overflow o
+            WHERE p.session_id = $1 AND p.user_id = o.user_id
+            RETURNING p.user_id
+        ), head AS (
+            SELECT COALESCE(MIN(queued_at), clock_timestamp()) AS queued_at FROM session_waitlist WHERE session_id = $1
+        )
+        -- Раньше присоединившиеся стоят в очереди раньше: у последнего присоединившегося rank = 1
+        INSERT INTO session_waitlist (session_id, user_id, queued_at)
+        SELECT $1, o.user_id, head.queued_at - make_interval(secs => o.rank)
+        FROM overflow o
+        JOIN removed r ON r.user_id = o.user_id
+        CROSS JOIN head
+        RETURNING user_id`
+	err := tx.SelectContext(ctx, &moved, query, sessionID, maxParticipants)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("%w: failed to move participants to waitlist: %v", ErrDatabase, err)
+	}
+	return moved, nil
+}
+
+// categorySubtreeSQL возвращает подзапрос со slug категорий, у которых slug или любое из названий
+// совпадает с одним из значений параметра-массива param (в нижнем регистре), вместе со всеми подкатегориями
+func categorySubtreeSQL(param string) string {
+	return fmt.Sprintf(`(
+            WITH RECURSIVE matched AS (
+                SELECT c.id, c.slug FROM categories c
+                WHERE c.slug = ANY(%[1]s) OR EXISTS (SELECT 1 FROM jsonb_each_text(c.names) n WHERE lower(n.value) = ANY(%[1]s))
+                UNION
+                SELECT c.id, c.slug FROM categories c JOIN matched m ON c.parent_id = m.id
+            )
+            SELECT slug FROM matched)`, param)
+}
+
+// skillVariantsSQL возвращает подзапрос со всеми написаниями навыка из параметра param в нижнем регистре:
+// само значение, каноническое название и синонимы навыка, если он есть в справочнике
+func skillVariantsSQL(param string) string {
+	return fmt.Sprintf(`(
+            SELECT lower(%[1]s::text)
+            UNION
+            SELECT v.variant FROM skills sk
+            CROSS JOIN LATERAL (SELECT lower(sk.name) UNION SELECT a.alias FROM skill_aliases a WHERE a.skill_id = sk.id) AS v(variant)
+            WHERE lower(sk.name) = lower(%[1]s::text)
+               OR EXISTS (SELECT 1 FROM skill_aliases a WHERE a.skill_id = sk.id AND a.alias = lower(%[1]s::text)))`, param)
+}
+
+// lowerAll возвращает значения в нижнем регистре без пробелов по краям
+func lowerAll(values []string) []string {
+	lowered := make([]string, 0, len(values))
+	for _, value := range values {
+		if value = strings.ToLower(strings.TrimSpace(value)); value != "" {
+			lowered = append(lowered, value)
+		}
+	}
+	return lowered
+}
+
+// GetRecommendedSessionsForUser получает рекомендуемые сессии для конкретного пользователя.
+// Навык совпадает с категорией по slug или названию на любом языке без учета регистра;
+// рекомендуются и сессии подкатегорий.
 // limit - максимальное количество рекомендуемых сессий
 func (r *SessionRepository) GetRecommendedSessionsForUser(ctx context.Context, userID uuid.UUID, userSkills []string, limit int) ([]models.Session, error) {
 	sessions := []models.Session{}
@@ -251,13 +713,14 @@ func (r *SessionRepository) GetRecommendedSessionsForUser(ctx context.Context, u
 		query = `
 			SELECT s.* FROM sessions s
 			LEFT JOIN session_participants sp ON s.id = sp.session_id AND sp.user_id = $1
-			WHERE s.category = ANY($2)
+			WHERE s.category IN `+categorySubtreeSQL("$2")+`
 			  AND s.creator_id != $1
 			  AND sp.session_id IS NULL
+              AND s.status = 'published' AND s.visibility = 'public'
               AND s.date_time > NOW()
 			ORDER BY s.date_time ASC
 			LIMIT $3`
-		args = append(args, userID, pq.Array(userSkills), limit)
+		args = append(args, userID, pq.Array(lowerAll(userSkills)), limit)
 	} else {
 		// Если у пользователя нет навыков, рекомендуем просто новые или популярные сессии,
 		// к которым он не присоединился и которые он не создавал.
@@ -267,6 +730,7 @@ func (r *SessionRepository) GetRecommendedSessionsForUser(ctx context.Context, u
 			LEFT JOIN session_participants sp ON s.id = sp.session_id AND sp.user_id = $1
 			WHERE s.creator_id != $1
 			  AND sp.session_id IS NULL
+              AND s.status = 'published' AND s.visibility = 'public'
               AND s.date_time > NOW()
 			ORDER BY s.created_at DESC, s.date_time ASC
 			LIMIT $2`
@@ -291,7 +755,7 @@ func (r *SessionRepository) GetGeneralRecommendedSessions(ctx context.Context, l
     // Исключаем прошедшие сессии
 	query := `
 		SELECT * FROM sessions
-        WHERE date_time > NOW()
+        WHERE status = 'published' AND visibility = 'public' AND date_time > NOW()
 		ORDER BY date_time ASC, created_at DESC
 		LIMIT $1`
 	err := r.db.SelectContext(ctx, &sessions, query, limit)
@@ -311,7 +775,7 @@ func (r *SessionRepository) GetSessionsStartingSoon(ctx context.Context, beforeT
     sessions := []models.Session{}
     query := `
         SELECT * FROM sessions
-        WHERE date_time > NOW() AND date_time <= $1
+        WHERE status = 'published' AND date_time > NOW() AND date_time <= $1
         ORDER BY date_time ASC`
     err := r.db.SelectContext(ctx, &sessions, query, beforeTime)
     if err != nil && !errors.Is(err, sql.ErrNoRows) {
@@ -325,14 +789,32 @@ func (r *SessionRepository) GetSessionsStartingSoon(ctx context.Context, beforeT
 }
 
 
-// SearchSessions выполняет поиск и фильтрацию сессий
-func (r *SessionRepository) SearchSessions(ctx context.Context, filters models.SessionSearchFilters) ([]models.Session, int, error) { // Возвращаем также общее количество
-    var sessions []models.Session
-    var totalCount int
+// searchHeadlineOptions - параметры ts_headline для подсветки совпадений в результатах поиска
+const searchHeadlineOptions = "StartSel=<mark>, StopSel=</mark>, MaxWords=35, MinWords=15, MaxFragments=2"
 
-    // Базовый запрос
-    baseQuery := `
-        SELECT s.id, s.title, s.description, s.category, s.date_time, s.location, s.max_participants, s.creator_id, s.created_at, s.updated_at
+// earthRadiusKm - средний радиус Земли для формулы гаверсинусов
+const earthRadiusKm = 6371.0
+
+// haversineDistanceSQL возвращает SQL-выражение расстояния в км от точки ($latArg, $lngArg)
+// до координат сессии по формуле гаверсинусов. Работает на PostgreSQL без расширений.
+func haversineDistanceSQL(latArg, lngArg int) string {
+    // least(1, ...) защищает asin от выхода за область определения из-за погрешности округления
+    return fmt.Sprintf(`(%[1]g * 2 * asin(least(1, sqrt(
+                power(sin(radians(s.latitude - $%[2]d::float8) / 2), 2)
+                + cos(radians($%[2]d::float8)) * cos(radians(s.latitude)) * power(sin(radians(s.longitude - $%[3]d::float8) / 2), 2)))))`,
+        earthRadiusKm, latArg, lngArg)
+}
+
+// sessionSearchQuery - части запроса поиска сессий, общие для постраничной и keyset-пагинации
+type sessionSearchQuery struct {
+    selectSQL string        // SELECT ... (без FROM); при полнотекстовом поиске - с рангом и подсветкой
+    whereSQL  string        // " WHERE ..." или пустая строка
+    args      []interface{} // Аргументы условий WHERE
+    argID     int           // Номер следующего параметра запроса
+}
+
+// sessionSearchFrom - источник строк поиска сессий
+const sessionSearchFrom = `
         -- Дополнительные поля, если нужны (например, количество участников, средний рейтинг сессии)
         -- , COUNT(sp.user_id) as participant_count
         -- , COALESCE(AVG(f.rating), 0) as average_session_rating
@@ -340,57 +822,152 @@ func (r *SessionRepository) SearchSessions(ctx context.Context, filters models.S
         -- LEFT JOIN session_participants sp ON s.id = sp.session_id -- Если нужен participant_count
         -- LEFT JOIN feedback f ON s.id = f.session_id             -- Если нужен average_session_rating
     `
-    countQuery := `SELECT COUNT(DISTINCT s.id) FROM sessions s` // Для подсчета общего количества
+
+// buildSessionSearch собирает SELECT и условия WHERE поиска сессий по фильтрам (без сортировки и пагинации)
+func buildSessionSearch(filters models.SessionSearchFilters) sessionSearchQuery {
+    q := sessionSearchQuery{selectSQL: `
+        SELECT s.id, s.title, s.description, s.category, s.date_time, s.end_time, s.time_zone, s.location, s.latitude, s.longitude, s.venue_address, s.is_online, s.max_participants, s.tags, s.creator_id, s.series_id, s.original_start, s.is_override, s.status, s.cancelled_at, s.cancellation_reason, s.created_at, s.updated_at, s.search_language, s.visibility, s.requires_approval, s.version`}
 
     var whereClauses []string
     var args []interface{}
     argID := 1
 
-    if filters.CreatorID != nil && *filters.CreatorID != uuid.Nil {
-        whereClauses = append(whereClauses, fmt.Sprintf("s.creator_id = $%d", argID))
-        args = append(args, *filters.CreatorID)
+    if filters.HostID != nil && *filters.HostID != uuid.Nil {
+        // Владелец, соорганизаторы и помощники, принявшие приглашение
+        whereClauses = append(whereClauses, fmt.Sprintf("EXISTS (SELECT 1 FROM session_hosts h WHERE h.session_id = s.id AND h.user_id = $%d AND h.status = 'accepted')", argID))
+        args = append(args, *filters.HostID)
         argID++
-        log.Printf("SearchSessions: Filtering by CreatorID: %s", (*filters.CreatorID).String())
     }
 
+    // Черновики и отмененные сессии показываются только по явному запросу (например, в "Моих сессиях")
+    statuses := []string{string(models.SessionStatusPublished)}
+    if len(filters.Statuses) > 0 {
+        statuses = statuses[:0]
+        for _, status := range filters.Statuses {
+            statuses = append(statuses, string(status))
+        }
+    }
+    whereClauses = append(whereClauses, fmt.Sprintf("s.status = ANY($%d)", argID))
+    args = append(args, pq.Array(statuses))
+    argID++
+
+    // Сессии unlisted и invite_only не попадают в общие списки и поиск
+    visibilities := []string{string(models.VisibilityPublic)}
+    if len(filters.Visibilities) > 0 {
+        visibilities = visibilities[:0]
+        for _, visibility := range filters.Visibilities {
+            visibilities = append(visibilities, string(visibility))
+        }
+    }
+    whereClauses = append(whereClauses, fmt.Sprintf("s.visibility = ANY($%d)", argID))
+    args = append(args, pq.Array(visibilities))
+    argID++
+
     if filters.Query != "" {
-        // Поиск по названию и описанию
-        queryWords := strings.Fields(filters.Query)
-        for _, word := range queryWords {
-             whereClauses = append(whereClauses, fmt.Sprintf("(s.title ILIKE $%d OR s.description ILIKE $%d)", argID, argID+1))
-             args = append(args, "%"+word+"%", "%"+word+"%")
-             argID += 2
+        // Полнотекстовый поиск по названию и описанию (синтаксис веб-поиска: "фраза", -исключение, or).
+        // Запрос разбирается в той же конфигурации, что и документ, поэтому условие перечисляет
+        // поддерживаемые языки явно: так каждая ветка может использовать GIN-индекс по search_vector.
+        var languageClauses []string
+        for _, language := range models.SearchLanguages {
+            languageClauses = append(languageClauses, fmt.Sprintf(
+                "(s.search_language = '%[1]s'::regconfig AND s.search_vector @@ websearch_to_tsquery('%[1]s', $%[2]d))", language, argID))
         }
+        whereClauses = append(whereClauses, "("+strings.Join(languageClauses, " OR ")+")")
+        tsQuery := fmt.Sprintf("websearch_to_tsquery(s.search_language, $%d)", argID)
+        q.selectSQL += fmt.Sprintf(`,
+            ts_rank(s.search_vector, %[1]s) AS search_rank,
+            ts_headline(s.search_language, s.title, %[1]s, '%[2]s') AS title_highlight,
+            ts_headline(s.search_language, s.description, %[1]s, '%[2]s') AS description_highlight`, tsQuery, searchHeadlineOptions)
+        args = append(args, filters.Query)
+        argID++
     }
-    if filters.Category != "" {
-        whereClauses = append(whereClauses, fmt.Sprintf("s.category = $%d", argID))
-        args = append(args, filters.Category)
+    if filters.Category != "" { // Категория вместе с подкатегориями
+        whereClauses = append(whereClauses, "s.category IN "+categorySubtreeSQL(fmt.Sprintf("$%d", argID)))
+        args = append(args, pq.Array(lowerAll([]string{filters.Category})))
+        argID++
+    }
+    if filters.Skill != "" {
+        // Навык ищется без учета регистра среди тегов сессии и навыков ее создателя,
+        // вместе с каноническим названием и синонимами навыка из справочника
+        variants := skillVariantsSQL(fmt.Sprintf("$%d", argID))
+        whereClauses = append(whereClauses, fmt.Sprintf(`(
+            EXISTS (SELECT 1 FROM unnest(s.tags) AS tag WHERE lower(tag) IN %[1]s)
+            OR EXISTS (SELECT 1 FROM users cu, unnest(cu.skills) AS skill WHERE cu.id = s.creator_id AND lower(skill) IN %[1]s))`, variants))
+        args = append(args, filters.Skill)
+        argID++
+    }
+    if filters.MinRating > 0 {
+        // Подходит сессия с достаточным средним рейтингом по отзывам либо с достаточным рейтингом создателя
+        whereClauses = append(whereClauses, fmt.Sprintf(`(
+            (SELECT AVG(f.rating) FROM feedback f WHERE f.session_id = s.id) >= $%d
+            OR (SELECT cu.average_rating FROM users cu WHERE cu.id = s.creator_id) >= $%d)`, argID, argID))
+        args = append(args, filters.MinRating)
+        argID++
+    }
+    if filters.Location != "" {
+        whereClauses = append(whereClauses, fmt.Sprintf("s.location ILIKE $%d", argID))
+        args = append(args, "%"+filters.Location+"%")
         argID++
     }
+    if filters.AvailableSlots {
+        whereClauses = append(whereClauses, "(SELECT COUNT(*) FROM session_participants sp WHERE sp.session_id = s.id) < s.max_participants")
+    }
+    if filters.Near != nil {
+        // Очные сессии в радиусе и онлайн-сессии: у последних расстояния нет, они идут в конце списка.
+        // Отбор по широте (1° ≈ 111 км) позволяет использовать индекс до точного расчета.
+        distance := haversineDistanceSQL(argID, argID+1)
+        q.selectSQL += fmt.Sprintf(`,
+            CASE WHEN s.is_online THEN NULL ELSE %s END AS distance_km`, distance)
+        whereClauses = append(whereClauses, fmt.Sprintf(`(s.is_online OR (
+            s.latitude BETWEEN $%[1]d::float8 - $%[2]d::float8 / 111.045 AND $%[1]d::float8 + $%[2]d::float8 / 111.045
+            AND %[3]s <= $%[2]d::float8))`, argID, argID+2, distance))
+        args = append(args, filters.Near.Lat, filters.Near.Lng, filters.Near.RadiusKm)
+        argID += 3
+    }
 
+    // Сессия попадает в интервал [DateFrom, DateTo], если пересекается с ним по времени
     if filters.DateFrom != nil {
-        whereClauses = append(whereClauses, fmt.Sprintf("s.date_time >= $%d", argID))
+        whereClauses = append(whereClauses, fmt.Sprintf("s.end_time > $%d", argID))
         args = append(args, *filters.DateFrom)
         argID++
     }
     if filters.DateTo != nil {
-        whereClauses = append(whereClauses, fmt.Sprintf("s.date_time <= $%d", argID))
+        whereClauses = append(whereClauses, fmt.Sprintf("s.date_time < $%d", argID))
         args = append(args, *filters.DateTo)
         argID++
     }
-     if filters.ExcludePast { // По умолчанию true
-         whereClauses = append(whereClauses, "s.date_time > NOW()")
+     if filters.ExcludePast { // По умолчанию true; идущие сейчас сессии не считаются прошедшими
+         whereClauses = append(whereClauses, "s.end_time > NOW()")
      }
 
     // Сборка WHERE
-    whereSQL := ""
     if len(whereClauses) > 0 {
-        whereSQL = " WHERE " + strings.Join(whereClauses, " AND ")
+        q.whereSQL = " WHERE " + strings.Join(whereClauses, " AND ")
     }
+    q.args = args
+    q.argID = argID
+    return q
+}
+
+// SearchSessions выполняет поиск и фильтрацию сессий с постраничной пагинацией (Limit/Offset).
+// При заданном Query результаты сортируются по релевантности.
+func (r *SessionRepository) SearchSessions(ctx context.Context, filters models.SessionSearchFilters) ([]models.Session, int, error) { // Возвращаем также общее количество
+    var sessions []models.Session
+    var totalCount int
 
-    finalQuery := baseQuery + whereSQL
+    q := buildSessionSearch(filters)
+    countQuery := `SELECT COUNT(DISTINCT s.id) FROM sessions s` // Для подсчета общего количества
+    whereSQL, args, argID := q.whereSQL, q.args, q.argID
+
+    finalQuery := q.selectSQL + sessionSearchFrom + whereSQL
     // finalQuery += " GROUP BY s.id" 
-    finalQuery += " ORDER BY s.date_time ASC" // Или другой порядок
+    if filters.Near != nil {
+        finalQuery += " ORDER BY distance_km ASC NULLS LAST, s.date_time ASC" // Сначала ближайшие, онлайн - в конце
+    } else if filters.Query != "" {
+        finalQuery += " ORDER BY search_rank DESC, s.date_time ASC" // Сначала самые релевантные
+    } else {
+        finalQuery += " ORDER BY s.date_time ASC" // Или другой порядок
+    }
 
     // Добавляем пагинацию к args для основного запроса
     var pagedArgs []interface{}
@@ -433,6 +1010,77 @@ func (r *SessionRepository) SearchSessions(ctx context.Context, filters models.S
 }
 
 
+// SearchSessionsPage выполняет поиск сессий с keyset-пагинацией по (date_time, id).
+// Сортировка всегда по времени начала; общее количество считается только по запросу (page.WithCount).
+func (r *SessionRepository) SearchSessionsPage(ctx context.Context, filters models.SessionSearchFilters, page models.PageRequest) ([]models.Session, models.PageInfo, error) {
+    q := buildSessionSearch(filters)
+    keyset := newKeysetQuery(page, "s.date_time", "s.id", false, q.argID)
+    query := q.selectSQL + sessionSearchFrom + appendWhere(q.whereSQL, keyset.condition) + keyset.orderAndLimit
+    countQuery := `SELECT COUNT(*) FROM sessions s` + q.whereSQL
+    return r.selectSessionPage(ctx, query, append(q.args, keyset.args...), countQuery, q.args, page)
+}
+
+// selectSessionPage выбирает страницу сессий запросом keyset-пагинации и, если нужно, общее количество
+func (r *SessionRepository) selectSessionPage(ctx context.Context, query string, args []interface{}, countQuery string, countArgs []interface{}, page models.PageRequest) ([]models.Session, models.PageInfo, error) {
+    var fetched []models.Session
+    if err := r.db.SelectContext(ctx, &fetched, query, args...); err != nil {
+        log.Printf("ERROR fetching sessions page: %v, query: %s, args: %v", err, query, args)
+        return nil, models.PageInfo{}, fmt.Errorf("%w: failed to get sessions page: %v", ErrDatabase, err)
+    }
+    sessions, info := models.NewPageInfo(fetched, page, func(s *models.Session) (time.Time, uuid.UUID) {
+        return s.DateTime, s.ID
+    })
+    if sessions == nil {
+        sessions = []models.Session{}
+    }
+
+    if page.WithCount {
+        var total int
+        if err := r.db.GetContext(ctx, &total, countQuery, countArgs...); err != nil {
+            log.Printf("ERROR counting sessions: %v, query: %s, args: %v", err, countQuery, countArgs)
+            return nil, models.PageInfo{}, fmt.Errorf("%w: failed to count sessions: %v", ErrDatabase, err)
+        }
+        info.TotalItems = &total
+    }
+    return sessions, info, nil
+}
+
+// joinedSessionsWhere собирает условия выборки сессий, к которым присоединился пользователь.
+// Возвращает WHERE-часть, ее аргументы и номер следующего параметра.
+func joinedSessionsWhere(userID uuid.UUID, filters models.SessionSearchFilters) (string, []interface{}, int) {
+    conditions := []string{"sp.user_id = $1", "s.status <> 'draft'"}
+    args := []interface{}{userID}
+    argId := 2 // Start next arg index at 2
+
+    if filters.Category != "" {
+        conditions = append(conditions, "s.category IN "+categorySubtreeSQL(fmt.Sprintf("$%d", argId)))
+        args = append(args, pq.Array(lowerAll([]string{filters.Category})))
+        argId++
+    }
+    if filters.ExcludePast {
+        conditions = append(conditions, fmt.Sprintf("s.end_time >= $%d", argId))
+        args = append(args, time.Now()) 
+        argId++
+    }
+    return " WHERE " + strings.Join(conditions, " AND "), args, argId
+}
+
+// GetJoinedSessionsPage возвращает страницу сессий, к которым присоединился пользователь,
+// с keyset-пагинацией по (date_time, id) от поздних к ранним
+func (r *SessionRepository) GetJoinedSessionsPage(ctx context.Context, userID uuid.UUID, filters models.SessionSearchFilters, page models.PageRequest) ([]models.Session, models.PageInfo, error) {
+    whereClause, args, argId := joinedSessionsWhere(userID, filters)
+    keyset := newKeysetQuery(page, "s.date_time", "s.id", true, argId)
+    query := `
+        SELECT s.*
+        FROM sessions s
+        JOIN session_participants sp ON s.id = sp.session_id` + appendWhere(whereClause, keyset.condition) + keyset.orderAndLimit
+    countQuery := `
+        SELECT COUNT(*)
+        FROM sessions s
+        JOIN session_participants sp ON s.id = sp.session_id` + whereClause
+    return r.selectSessionPage(ctx, query, append(args, keyset.args...), countQuery, args, page)
+}
+
 // GetJoinedSessionsByUserID извлекает все сеансы, к которым присоединился пользователь
 func (r *SessionRepository) GetJoinedSessionsByUserID(ctx context.Context, userID uuid.UUID, filters models.SessionSearchFilters) ([]models.Session, int, error) {
     var sessions []models.Session
@@ -448,25 +1096,7 @@ func (r *SessionRepository) GetJoinedSessionsByUserID(ctx context.Context, userI
         FROM sessions s
         JOIN session_participants sp ON s.id = sp.session_id
     `
-    conditions := []string{"sp.user_id = $1"}
-    args := []interface{}{userID}
-    argId := 2 // Start next arg index at 2
-
-    if filters.Category != "" {
-        conditions = append(conditions, fmt.Sprintf("s.category = $%d", argId))
-        args = append(args, filters.Category)
-        argId++
-    }
-    if filters.ExcludePast {
-        conditions = append(conditions, fmt.Sprintf("s.date_time >= $%d", argId))
-        args = append(args, time.Now()) 
-        argId++
-    }
-
-    whereClause := ""
-    if len(conditions) > 0 {
-        whereClause = " WHERE " + strings.Join(conditions, " AND ")
-    }
+    whereClause, args, argId := joinedSessionsWhere(userID, filters)
 
     finalCountQuery := countBaseQuery + whereClause
     err := r.db.GetContext(ctx, &totalCount, finalCountQuery, args...)
@@ -490,3 +1120,84 @@ func (r *SessionRepository) GetJoinedSessionsByUserID(ctx context.Context, userI
     }
     return sessions, totalCount, nil
 }
+
+// userScheduleCTE - активные (черновики и опубликованные) сессии пользователя $1,
+// в которых он организатор с принятой ролью или участник
+const userScheduleCTE = `
+        WITH schedule AS (
+            SELECT s.id AS session_id, s.title, s.date_time, s.end_time, s.time_zone, s.status, 'host' AS role
+            FROM sessions s
+            JOIN session_hosts h ON h.session_id = s.id
+            WHERE h.user_id = $1 AND h.status = 'accepted' AND s.status IN ('draft', 'published')
+            UNION
+            SELECT s.id, s.title, s.date_time, s.end_time, s.time_zone, s.status, 'participant'
+            FROM sessions s
+            JOIN session_participants sp ON sp.session_id = s.id
+            WHERE sp.user_id = $1 AND s.status IN ('draft', 'published')
+        )`
+
+// FindConflicts возвращает сессии пользователя, пересекающиеся по времени с интервалом [start, end).
+// excludeID исключает саму проверяемую сессию (при изменении); uuid.Nil - ничего не исключать.
+func (r *SessionRepository) FindConflicts(ctx context.Context, userID uuid.UUID, start, end time.Time, excludeID uuid.UUID) ([]models.SessionConflict, error) {
+	conflicts := []models.SessionConflict{}
+	query := userScheduleCTE + `
+        SELECT session_id, title, date_time, end_time, time_zone, status, role
+        FROM schedule
+        WHERE date_time < $3 AND end_time > $2 AND session_id <> $4
+        ORDER BY date_time ASC, session_id ASC`
+	if err := r.db.SelectContext(ctx, &conflicts, query, userID, start, end, excludeID); err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("%w: failed to find schedule conflicts: %v", ErrDatabase, err)
+	}
+	return conflicts, nil
+}
+
+// ListClashes возвращает пары предстоящих сессий пользователя, пересекающихся друг с другом
+func (r *SessionRepository) ListClashes(ctx context.Context, userID uuid.UUID) ([]models.ScheduleClash, error) {
+	clashes := []models.ScheduleClash{}
+	query := userScheduleCTE + `
+        SELECT a.session_id AS "first.session_id", a.title AS "first.title", a.date_time AS "first.date_time",
+               a.end_time AS "first.end_time", a.time_zone AS "first.time_zone", a.status AS "first.status", a.role AS "first.role",
+               b.session_id AS "second.session_id", b.title AS "second.title", b.date_time AS "second.date_time",
+               b.end_time AS "second.end_time", b.time_zone AS "second.time_zone", b.status AS "second.status", b.role AS "second.role"
+        FROM schedule a
+        JOIN schedule b ON (a.date_time, a.session_id) < (b.date_time, b.session_id)
+            AND a.date_time < b.end_time AND b.date_time < a.end_time
+        WHERE a.end_time > NOW() AND b.end_time > NOW()
+        ORDER BY a.date_time ASC, b.date_time ASC`
+	if err := r.db.SelectContext(ctx, &clashes, query, userID); err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("%w: failed to list schedule clashes: %v", ErrDatabase, err)
+	}
+	return clashes, nil
+}
+
+// ListVersions возвращает историю версий сессии, начиная с последней
+func (r *SessionRepository) ListVersions(ctx context.Context, sessionID uuid.UUID) ([]models.SessionVersion, error) {
+	versions := []models.SessionVersion{}
+	query := `
+        SELECT v.id, v.session_id, v.version, v.changed_by, u.name AS changed_by_name, v.changes, v.snapshot, v.created_at
+        FROM session_versions v
+        LEFT JOIN users u ON u.id = v.changed_by
+        WHERE v.session_id = $1
+        ORDER BY v.version DESC`
+	if err := r.db.SelectContext(ctx, &versions, query, sessionID); err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("%w: failed to list session versions: %v", ErrDatabase, err)
+	}
+	return versions, nil
+}
+
+// GetVersion возвращает версию version сессии
+func (r *SessionRepository) GetVersion(ctx context.Context, sessionID uuid.UUID, version int) (*models.SessionVersion, error) {
+	var v models.SessionVersion
+	query := `
+        SELECT v.id, v.session_id, v.version, v.changed_by, u.name AS changed_by_name, v.changes, v.snapshot, v.created_at
+        FROM session_versions v
+        LEFT JOIN users u ON u.id = v.changed_by
+        WHERE v.session_id = $1 AND v.version = $2`
+	if err := r.db.GetContext(ctx, &v, query, sessionID, version); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrSessionVersionNotFound
+		}
+		return nil, fmt.Errorf("%w: failed to get session version: %v", ErrDatabase, err)
+	}
+	return &v, nil
+}
@@ -0,0 +1,573 @@
+// repositories/session_repository_test.go
+package repositories_test
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/BuzzLyutic/Skill-sharing-web-platform/models"
+	"github.com/BuzzLyutic/Skill-sharing-web-platform/repositories"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newSessionRepoWithMock(t *testing.T) (*repositories.SessionRepository, sqlmock.Sqlmock) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	return repositories.NewSessionRepository(sqlx.NewDb(db, "sqlmock")), mock
+}
+
+func expectSeatLock(mock sqlmock.Sqlmock, sessionID uuid.UUID, maxParticipants int, status models.SessionStatus) {
+	expectSeatLockWithVisibility(mock, sessionID, maxParticipants, status, models.VisibilityPublic)
+}
+
+func expectSeatLockWithVisibility(mock sqlmock.Sqlmock, sessionID uuid.UUID, maxParticipants int, status models.SessionStatus, visibility models.SessionVisibility) {
+	expectSeatLockWithApproval(mock, sessionID, maxParticipants, status, visibility, false)
+}
+
+func expectSeatLockWithApproval(mock sqlmock.Sqlmock, sessionID uuid.UUID, maxParticipants int, status models.SessionStatus, visibility models.SessionVisibility, requiresApproval bool) {
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT max_participants, status, visibility, requires_approval FROM sessions WHERE id = $1 FOR UPDATE`)).
+		WithArgs(sessionID).
+		WillReturnRows(sqlmock.NewRows([]string{"max_participants", "status", "visibility", "requires_approval"}).
+			AddRow(maxParticipants, status, visibility, requiresApproval))
+}
+
+func expectSeatState(mock sqlmock.Sqlmock, sessionID, userID uuid.UUID, maxParticipants int, isParticipant, isWaitlisted bool, participants int) {
+	expectSeatStateWithVisibility(mock, sessionID, userID, maxParticipants, isParticipant, isWaitlisted, participants, models.VisibilityPublic)
+}
+
+func expectSeatStateWithVisibility(mock sqlmock.Sqlmock, sessionID, userID uuid.UUID, maxParticipants int, isParticipant, isWaitlisted bool, participants int, visibility models.SessionVisibility) {
+	mock.ExpectBegin()
+	expectSeatLockWithVisibility(mock, sessionID, maxParticipants, models.SessionStatusPublished, visibility)
+	mock.ExpectQuery(`SELECT\s+EXISTS`).
+		WithArgs(sessionID, userID).
+		WillReturnRows(sqlmock.NewRows([]string{"is_participant", "is_waitlisted", "is_pending", "participants"}).
+			AddRow(isParticipant, isWaitlisted, false, participants))
+}
+
+func TestSessionRepository_JoinSession_FreeSeat(t *testing.T) {
+	repo, mock := newSessionRepoWithMock(t)
+	sessionID, userID := uuid.New(), uuid.New()
+
+	expectSeatState(mock, sessionID, userID, 2, false, false, 1)
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO session_participants (session_id, user_id) VALUES ($1, $2)`)).
+		WithArgs(sessionID, userID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	result, err := repo.JoinSession(context.Background(), sessionID, userID, models.JoinSessionRequest{})
+
+	require.NoError(t, err)
+	assert.Equal(t, models.ParticipationStatusJoined, result.Status)
+	assert.Zero(t, result.WaitlistPosition)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSessionRepository_JoinSession_FullGoesToWaitlist(t *testing.T) {
+	repo, mock := newSessionRepoWithMock(t)
+	sessionID, userID := uuid.New(), uuid.New()
+
+	expectSeatState(mock, sessionID, userID, 2, false, false, 2)
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO session_waitlist (session_id, user_id) VALUES ($1, $2)`)).
+		WithArgs(sessionID, userID).
+		WillReturnRows(sqlmock.NewRows([]string{"position"}).AddRow(3))
+	mock.ExpectCommit()
+
+	result, err := repo.JoinSession(context.Background(), sessionID, userID, models.JoinSessionRequest{})
+
+	require.NoError(t, err)
+	assert.Equal(t, models.ParticipationStatusWaitlisted, result.Status)
+	assert.Equal(t, 3, result.WaitlistPosition)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSessionRepository_JoinSession_AlreadyWaitlisted(t *testing.T) {
+	repo, mock := newSessionRepoWithMock(t)
+	sessionID, userID := uuid.New(), uuid.New()
+
+	expectSeatState(mock, sessionID, userID, 2, false, true, 2)
+	mock.ExpectRollback()
+
+	_, err := repo.JoinSession(context.Background(), sessionID, userID, models.JoinSessionRequest{})
+
+	assert.True(t, errors.Is(err, repositories.ErrAlreadyWaitlisted))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSessionRepository_JoinSession_InviteOnlyRequiresCode(t *testing.T) {
+	repo, mock := newSessionRepoWithMock(t)
+	sessionID, userID := uuid.New(), uuid.New()
+
+	expectSeatStateWithVisibility(mock, sessionID, userID, 5, false, false, 0, models.VisibilityInviteOnly)
+	mock.ExpectRollback()
+
+	_, err := repo.JoinSession(context.Background(), sessionID, userID, models.JoinSessionRequest{})
+
+	assert.ErrorIs(t, err, repositories.ErrInviteRequired)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSessionRepository_JoinSession_InviteOnlyConsumesCode(t *testing.T) {
+	repo, mock := newSessionRepoWithMock(t)
+	sessionID, userID := uuid.New(), uuid.New()
+
+	expectSeatStateWithVisibility(mock, sessionID, userID, 5, false, false, 0, models.VisibilityInviteOnly)
+	mock.ExpectExec(`UPDATE session_invites SET use_count = use_count \+ 1`).
+		WithArgs(sessionID, "ABCD").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO session_participants (session_id, user_id) VALUES ($1, $2)`)).
+		WithArgs(sessionID, userID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	result, err := repo.JoinSession(context.Background(), sessionID, userID, models.JoinSessionRequest{InviteCode: "ABCD"})
+
+	require.NoError(t, err)
+	assert.Equal(t, models.ParticipationStatusJoined, result.Status)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSessionRepository_JoinSession_InviteUsedUp(t *testing.T) {
+	repo, mock := newSessionRepoWithMock(t)
+	sessionID, userID := uuid.New(), uuid.New()
+
+	expectSeatStateWithVisibility(mock, sessionID, userID, 5, false, false, 0, models.VisibilityInviteOnly)
+	mock.ExpectExec(`UPDATE session_invites SET use_count = use_count \+ 1`).
+		WithArgs(sessionID, "ABCD").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+
+	_, err := repo.JoinSession(context.Background(), sessionID, userID, models.JoinSessionRequest{InviteCode: "ABCD"})
+
+	assert.ErrorIs(t, err, repositories.ErrInvalidInvite)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSessionRepository_JoinSession_RequiresApprovalCreatesPendingRequest(t *testing.T) {
+	repo, mock := newSessionRepoWithMock(t)
+	sessionID, userID := uuid.New(), uuid.New()
+
+	mock.ExpectBegin()
+	expectSeatLockWithApproval(mock, sessionID, 5, models.SessionStatusPublished, models.VisibilityPublic, true)
+	mock.ExpectQuery(`SELECT\s+EXISTS`).
+		WithArgs(sessionID, userID).
+		WillReturnRows(sqlmock.NewRows([]string{"is_participant", "is_waitlisted", "is_pending", "participants"}).
+			AddRow(false, false, false, 0))
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO session_join_requests (session_id, user_id, message) VALUES ($1, $2, NULLIF($3, ''))`)).
+		WithArgs(sessionID, userID, "I have done the prerequisites").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	result, err := repo.JoinSession(context.Background(), sessionID, userID, models.JoinSessionRequest{Message: "I have done the prerequisites"})
+
+	require.NoError(t, err)
+	assert.Equal(t, models.ParticipationStatusPending, result.Status)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSessionRepository_JoinSession_PendingRequestIsRejected(t *testing.T) {
+	repo, mock := newSessionRepoWithMock(t)
+	sessionID, userID := uuid.New(), uuid.New()
+
+	mock.ExpectBegin()
+	expectSeatLockWithApproval(mock, sessionID, 5, models.SessionStatusPublished, models.VisibilityPublic, true)
+	mock.ExpectQuery(`SELECT\s+EXISTS`).
+		WithArgs(sessionID, userID).
+		WillReturnRows(sqlmock.NewRows([]string{"is_participant", "is_waitlisted", "is_pending", "participants"}).
+			AddRow(false, false, true, 0))
+	mock.ExpectRollback()
+
+	_, err := repo.JoinSession(context.Background(), sessionID, userID, models.JoinSessionRequest{})
+
+	assert.ErrorIs(t, err, repositories.ErrJoinRequestPending)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestJoinRequestRepository_Approve_FullSessionGoesToWaitlist(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	repo := repositories.NewJoinRequestRepository(sqlx.NewDb(db, "sqlmock"))
+	sessionID, userID, hostID := uuid.New(), uuid.New(), uuid.New()
+
+	mock.ExpectBegin()
+	expectSeatLockWithApproval(mock, sessionID, 1, models.SessionStatusPublished, models.VisibilityPublic, true)
+	mock.ExpectExec(`UPDATE session_join_requests\s+SET status = \$3`).
+		WithArgs(sessionID, userID, models.JoinRequestStatusApproved, "", hostID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT COUNT(*) FROM session_participants WHERE session_id = $1`)).
+		WithArgs(sessionID).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO session_waitlist (session_id, user_id) VALUES ($1, $2)`)).
+		WithArgs(sessionID, userID).
+		WillReturnRows(sqlmock.NewRows([]string{"position"}).AddRow(1))
+	mock.ExpectCommit()
+
+	result, err := repo.Approve(context.Background(), sessionID, userID, hostID, "")
+
+	require.NoError(t, err)
+	assert.Equal(t, models.ParticipationStatusWaitlisted, result.Status)
+	assert.Equal(t, 1, result.WaitlistPosition)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestJoinRequestRepository_Reject_NoPendingRequest(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	repo := repositories.NewJoinRequestRepository(sqlx.NewDb(db, "sqlmock"))
+	sessionID, userID, hostID := uuid.New(), uuid.New(), uuid.New()
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE session_join_requests\s+SET status = \$3`).
+		WithArgs(sessionID, userID, models.JoinRequestStatusRejected, "Not this time", hostID).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+
+	err = repo.Reject(context.Background(), sessionID, userID, hostID, "Not this time")
+
+	assert.ErrorIs(t, err, repositories.ErrJoinRequestNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSessionRepository_LeaveSession_PromotesFromWaitlist(t *testing.T) {
+	repo, mock := newSessionRepoWithMock(t)
+	sessionID, userID, waitingID := uuid.New(), uuid.New(), uuid.New()
+
+	mock.ExpectBegin()
+	expectSeatLock(mock, sessionID, 1, models.SessionStatusPublished)
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM session_participants WHERE session_id = $1 AND user_id = $2`)).
+		WithArgs(sessionID, userID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(`WITH free AS`).
+		WithArgs(sessionID, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"user_id"}).AddRow(waitingID))
+	mock.ExpectCommit()
+
+	promoted, err := repo.LeaveSession(context.Background(), sessionID, userID)
+
+	require.NoError(t, err)
+	assert.Equal(t, []uuid.UUID{waitingID}, promoted)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSessionRepository_JoinSession_CancelledSessionIsNotOpen(t *testing.T) {
+	repo, mock := newSessionRepoWithMock(t)
+	sessionID, userID := uuid.New(), uuid.New()
+
+	mock.ExpectBegin()
+	expectSeatLock(mock, sessionID, 5, models.SessionStatusCancelled)
+	mock.ExpectRollback()
+
+	_, err := repo.JoinSession(context.Background(), sessionID, userID, models.JoinSessionRequest{})
+
+	assert.True(t, errors.Is(err, repositories.ErrSessionNotOpen))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSessionRepository_LeaveSession_CancelledSessionDoesNotPromote(t *testing.T) {
+	repo, mock := newSessionRepoWithMock(t)
+	sessionID, userID := uuid.New(), uuid.New()
+
+	mock.ExpectBegin()
+	expectSeatLock(mock, sessionID, 1, models.SessionStatusCancelled)
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM session_participants WHERE session_id = $1 AND user_id = $2`)).
+		WithArgs(sessionID, userID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	promoted, err := repo.LeaveSession(context.Background(), sessionID, userID)
+
+	require.NoError(t, err)
+	assert.Empty(t, promoted)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSessionRepository_Cancel_CompletedSessionIsRejected(t *testing.T) {
+	repo, mock := newSessionRepoWithMock(t)
+	sessionID, actorID := uuid.New(), uuid.New()
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`SELECT set_config('skillshare.actor_id', $1, true)`)).
+		WithArgs(actorID.String()).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(`UPDATE sessions\s+SET status = \$2, cancelled_at = NOW\(\)`).
+		WithArgs(sessionID, models.SessionStatusCancelled, "too late", sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT status FROM sessions WHERE id = $1`)).
+		WithArgs(sessionID).
+		WillReturnRows(sqlmock.NewRows([]string{"status"}).AddRow(models.SessionStatusCompleted))
+	mock.ExpectRollback()
+
+	_, _, err := repo.Cancel(context.Background(), sessionID, actorID, "too late")
+
+	assert.True(t, errors.Is(err, repositories.ErrInvalidStatusTransition))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSessionRepository_SearchSessions_AppliesFilters(t *testing.T) {
+	repo, mock := newSessionRepoWithMock(t)
+	filters := models.SessionSearchFilters{
+		Skill:          "Go",
+		MinRating:      4,
+		Location:       "Berlin",
+		AvailableSlots: true,
+		Limit:          10,
+	}
+
+	mock.ExpectQuery(`SELECT COUNT\(DISTINCT s.id\) FROM sessions s WHERE .*unnest\(s.tags\).*AVG\(f.rating\).*s.location ILIKE \$5.*session_participants`).
+		WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), "Go", 4.0, "%Berlin%").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	mock.ExpectQuery(`s.tags, s.creator_id.*FROM sessions s.*WHERE .*ORDER BY s.date_time ASC LIMIT \$6`).
+		WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), "Go", 4.0, "%Berlin%", 10).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	sessions, total, err := repo.SearchSessions(context.Background(), filters)
+
+	require.NoError(t, err)
+	assert.Empty(t, sessions)
+	assert.Zero(t, total)
+	assert.NoError(t, mock.ExpectationsWereMet())
+	assert.Equal(t, map[string]interface{}{
+		"skill": "Go", "min_rating": 4.0, "location": "Berlin", "available_slots": true,
+	}, filters.Applied())
+}
+
+func TestSessionRepository_SearchSessions_HostIDIncludesCoHosts(t *testing.T) {
+	repo, mock := newSessionRepoWithMock(t)
+	hostID := uuid.New()
+	filters := models.SessionSearchFilters{HostID: &hostID, Limit: 10}
+
+	mock.ExpectQuery(`SELECT COUNT\(DISTINCT s.id\) FROM sessions s WHERE EXISTS \(SELECT 1 FROM session_hosts h WHERE h.session_id = s.id AND h.user_id = \$1 AND h.status = 'accepted'\)`).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	mock.ExpectQuery(`FROM sessions s.*WHERE EXISTS \(SELECT 1 FROM session_hosts h`).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	_, _, err := repo.SearchSessions(context.Background(), filters)
+
+	require.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSessionRepository_SearchSessions_FullTextRanksResults(t *testing.T) {
+	repo, mock := newSessionRepoWithMock(t)
+	filters := models.SessionSearchFilters{Query: `go "web server"`, Limit: 10}
+
+	mock.ExpectQuery(`SELECT COUNT\(DISTINCT s.id\) FROM sessions s WHERE .*s.search_language = 'russian'::regconfig AND s.search_vector @@ websearch_to_tsquery\('russian', \$3\)`).
+		WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), filters.Query).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	mock.ExpectQuery(`ts_rank\(s.search_vector, websearch_to_tsquery\(s.search_language, \$3\)\) AS search_rank.*ts_headline.*ORDER BY search_rank DESC, s.date_time ASC LIMIT \$4`).
+		WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), filters.Query, 10).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "title", "search_rank", "title_highlight"}).
+			AddRow(uuid.New(), "Go web server", 0.6, "<mark>Go</mark> <mark>web</mark> <mark>server</mark>"))
+
+	sessions, total, err := repo.SearchSessions(context.Background(), filters)
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, total)
+	require.Len(t, sessions, 1)
+	require.NotNil(t, sessions[0].SearchRank)
+	assert.InDelta(t, 0.6, *sessions[0].SearchRank, 1e-9)
+	assert.Contains(t, *sessions[0].TitleHighlight, "<mark>Go</mark>")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSessionRepository_SearchSessionsPage_UsesCursor(t *testing.T) {
+	repo, mock := newSessionRepoWithMock(t)
+	cursor := &models.Cursor{Time: time.Date(2025, 5, 1, 10, 0, 0, 0, time.UTC), ID: uuid.New()}
+	first, second := uuid.New(), uuid.New()
+
+	mock.ExpectQuery(`FROM sessions s.*WHERE s.status = ANY\(\$1\) AND s.visibility = ANY\(\$2\) AND \(s.date_time, s.id\) > \(\$3, \$4\) ORDER BY s.date_time ASC, s.id ASC LIMIT \$5`).
+		WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), cursor.Time, cursor.ID, 2).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "date_time"}).
+			AddRow(first, cursor.Time.Add(time.Hour)).
+			AddRow(second, cursor.Time.Add(2*time.Hour)))
+
+	sessions, info, err := repo.SearchSessionsPage(context.Background(), models.SessionSearchFilters{}, models.PageRequest{Limit: 1, Cursor: cursor})
+
+	require.NoError(t, err)
+	require.Len(t, sessions, 1)
+	assert.Equal(t, first, sessions[0].ID)
+	assert.NotNil(t, info.NextCursor)
+	assert.NotNil(t, info.PrevCursor)
+	assert.Nil(t, info.TotalItems)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSessionRepository_SearchSessions_NearSortsByDistance(t *testing.T) {
+	repo, mock := newSessionRepoWithMock(t)
+	filters := models.SessionSearchFilters{
+		Near:  &models.GeoFilter{Lat: 52.52, Lng: 13.405, RadiusKm: 10},
+		Limit: 10,
+	}
+	near, online := uuid.New(), uuid.New()
+
+	mock.ExpectQuery(`SELECT COUNT\(DISTINCT s.id\) FROM sessions s WHERE .*\(s.is_online OR \(\s*s.latitude BETWEEN \$3::float8 - \$5::float8 / 111.045.*asin.*<= \$5::float8\)\)`).
+		WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), 52.52, 13.405, 10.0).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+	// Координаты и признак онлайн-сессии должны попадать в выборку, а не только в условия и сортировку
+	mock.ExpectQuery(`SELECT s.id, .*s.location, s.latitude, s.longitude, s.venue_address, s.is_online, .*CASE WHEN s.is_online THEN NULL ELSE .* END AS distance_km.*ORDER BY distance_km ASC NULLS LAST, s.date_time ASC LIMIT \$6`).
+		WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), 52.52, 13.405, 10.0, 10).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "latitude", "longitude", "is_online", "distance_km"}).
+			AddRow(near, 52.53, 13.41, false, 1.7).
+			AddRow(online, nil, nil, true, nil))
+
+	sessions, total, err := repo.SearchSessions(context.Background(), filters)
+
+	require.NoError(t, err)
+	assert.Equal(t, 2, total)
+	require.Len(t, sessions, 2)
+	require.NotNil(t, sessions[0].DistanceKm)
+	assert.InDelta(t, 1.7, *sessions[0].DistanceKm, 1e-9)
+	require.NotNil(t, sessions[0].Latitude)
+	assert.InDelta(t, 52.53, *sessions[0].Latitude, 1e-9)
+	assert.False(t, sessions[0].IsOnline)
+	assert.True(t, sessions[1].IsOnline)
+	assert.Nil(t, sessions[1].DistanceKm)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSessionRepository_FindConflicts(t *testing.T) {
+	repo, mock := newSessionRepoWithMock(t)
+	userID, sessionID, otherID := uuid.New(), uuid.New(), uuid.New()
+	start := time.Date(2025, 4, 1, 10, 0, 0, 0, time.UTC)
+	end := start.Add(time.Hour)
+
+	mock.ExpectQuery(`WITH schedule AS .+ WHERE date_time < \$3 AND end_time > \$2 AND session_id <> \$4`).
+		WithArgs(userID, start, end, sessionID).
+		WillReturnRows(sqlmock.NewRows([]string{"session_id", "title", "date_time", "end_time", "time_zone", "status", "role"}).
+			AddRow(otherID, "Go", start.Add(30*time.Minute), end.Add(30*time.Minute), "UTC", models.SessionStatusPublished, models.ConflictRoleParticipant))
+
+	conflicts, err := repo.FindConflicts(context.Background(), userID, start, end, sessionID)
+	require.NoError(t, err)
+	require.Len(t, conflicts, 1)
+	assert.Equal(t, otherID, conflicts[0].SessionID)
+	assert.Equal(t, models.ConflictRoleParticipant, conflicts[0].Role)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSessionRepository_ListClashes(t *testing.T) {
+	repo, mock := newSessionRepoWithMock(t)
+	userID, firstID, secondID := uuid.New(), uuid.New(), uuid.New()
+	start := time.Date(2025, 4, 1, 10, 0, 0, 0, time.UTC)
+
+	columns := []string{}
+	for _, prefix := range []string{"first.", "second."} {
+		for _, column := range []string{"session_id", "title", "date_time", "end_time", "time_zone", "status", "role"} {
+			columns = append(columns, prefix+column)
+		}
+	}
+	mock.ExpectQuery(`WITH schedule AS .+ FROM schedule a\s+JOIN schedule b`).
+		WithArgs(userID).
+		WillReturnRows(sqlmock.NewRows(columns).AddRow(
+			firstID, "Go", start, start.Add(time.Hour), "UTC", models.SessionStatusPublished, models.ConflictRoleHost,
+			secondID, "Rust", start.Add(30*time.Minute), start.Add(2*time.Hour), "UTC", models.SessionStatusPublished, models.ConflictRoleParticipant,
+		))
+
+	clashes, err := repo.ListClashes(context.Background(), userID)
+	require.NoError(t, err)
+	require.Len(t, clashes, 1)
+	assert.Equal(t, firstID, clashes[0].First.SessionID)
+	assert.Equal(t, secondID, clashes[0].Second.SessionID)
+	assert.Equal(t, models.ConflictRoleParticipant, clashes[0].Second.Role)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSessionRepository_Update_RecordsAuthor(t *testing.T) {
+	repo, mock := newSessionRepoWithMock(t)
+	sessionID, actorID := uuid.New(), uuid.New()
+	start := time.Date(2025, 4, 1, 10, 0, 0, 0, time.UTC)
+	end := start.Add(time.Hour)
+	approval := false
+	req := models.SessionRequest{Title: "Go", Category: "programming", DateTime: start, EndTime: &end, Location: "online", MaxParticipants: 5, RequiresApproval: &approval}
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`SELECT set_config('skillshare.actor_id', $1, true)`)).
+		WithArgs(actorID.String()).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	expectSeatLock(mock, sessionID, 5, models.SessionStatusPublished)
+	expectParticipantCount(mock, sessionID, 3)
+	mock.ExpectQuery(`UPDATE sessions\s+SET title = \$2`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "title", "version"}).AddRow(sessionID, "Go", 4))
+	mock.ExpectQuery(`WITH free AS`).
+		WithArgs(sessionID, 5).
+		WillReturnRows(sqlmock.NewRows([]string{"user_id"}))
+	mock.ExpectCommit()
+
+	session, changes, err := repo.Update(context.Background(), sessionID, actorID, req)
+	require.NoError(t, err)
+	assert.Equal(t, 4, session.Version)
+	assert.Empty(t, changes.Promoted)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSessionRepository_GetVersion_NotFound(t *testing.T) {
+	repo, mock := newSessionRepoWithMock(t)
+	sessionID := uuid.New()
+
+	mock.ExpectQuery(`FROM session_versions v`).
+		WithArgs(sessionID, 7).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	_, err := repo.GetVersion(context.Background(), sessionID, 7)
+	assert.True(t, errors.Is(err, repositories.ErrSessionVersionNotFound))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func expectParticipantCount(mock sqlmock.Sqlmock, sessionID uuid.UUID, participants int) {
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT COUNT(*) FROM session_participants WHERE session_id = $1`)).
+		WithArgs(sessionID).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(participants))
+}
+
+func capacityRequest(maxParticipants int, policy models.CapacityPolicy) models.SessionRequest {
+	start := time.Date(2025, 4, 1, 10, 0, 0, 0, time.UTC)
+	end := start.Add(time.Hour)
+	approval := false
+	return models.SessionRequest{Title: "Go", Category: "programming", DateTime: start, EndTime: &end, Location: "online",
+		MaxParticipants: maxParticipants, RequiresApproval: &approval, CapacityPolicy: policy}
+}
+
+func TestSessionRepository_Update_RejectsCapacityBelowParticipants(t *testing.T) {
+	repo, mock := newSessionRepoWithMock(t)
+	sessionID := uuid.New()
+
+	mock.ExpectBegin()
+	expectSeatLock(mock, sessionID, 5, models.SessionStatusPublished)
+	expectParticipantCount(mock, sessionID, 4)
+	mock.ExpectRollback()
+
+	_, _, err := repo.Update(context.Background(), sessionID, uuid.Nil, capacityRequest(2, ""))
+
+	assert.True(t, errors.Is(err, repositories.ErrCapacityBelowParticipants))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSessionRepository_Update_MovesLatestJoinersToWaitlist(t *testing.T) {
+	repo, mock := newSessionRepoWithMock(t)
+	sessionID, lateID, latestID := uuid.New(), uuid.New(), uuid.New()
+
+	mock.ExpectBegin()
+	expectSeatLock(mock, sessionID, 5, models.SessionStatusPublished)
+	expectParticipantCount(mock, sessionID, 4)
+	mock.ExpectQuery(`UPDATE sessions\s+SET title = \$2`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "max_participants"}).AddRow(sessionID, 2))
+	mock.ExpectQuery(`WITH overflow AS .+ORDER BY joined_at DESC NULLS LAST`).
+		WithArgs(sessionID, 2).
+		WillReturnRows(sqlmock.NewRows([]string{"user_id"}).AddRow(latestID).AddRow(lateID))
+	mock.ExpectCommit()
+
+	_, changes, err := repo.Update(context.Background(), sessionID, uuid.Nil, capacityRequest(2, models.CapacityPolicyWaitlist))
+
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []uuid.UUID{lateID, latestID}, changes.Waitlisted)
+	assert.Empty(t, changes.Promoted)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
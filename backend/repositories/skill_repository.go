@@ -0,0 +1,316 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/BuzzLyutic/Skill-sharing-web-platform/models"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+var (
+	ErrSkillNotFound   = errors.New("skill not found")
+	ErrSkillNameTaken  = errors.New("skill name is already used by another skill or alias")
+	ErrSkillAliasTaken = errors.New("alias is already used by another skill")
+)
+
+// skillColumns - поля навыка вместе с синонимами и числом пользователей (таблица skills с псевдонимом s)
+const skillColumns = `s.id, s.name, s.created_at,
+        ARRAY(SELECT a.alias FROM skill_aliases a WHERE a.skill_id = s.id ORDER BY a.alias) AS aliases,
+        (SELECT COUNT(*) FROM user_skills us WHERE us.skill_id = s.id) AS user_count`
+
+// likeEscaper экранирует спецсимволы LIKE в пользовательском вводе
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// SkillRepository обрабатывает справочник навыков и их синонимов
+type SkillRepository struct {
+	db *sqlx.DB
+}
+
+// NewSkillRepository создает новый репозиторий навыков
+func NewSkillRepository(db *sqlx.DB) *SkillRepository {
+	return &SkillRepository{db: db}
+}
+
+var _ SkillRepositoryInterface = (*SkillRepository)(nil) // Static check
+
+// Canonicalize сопоставляет нормализованные названия (см. models.NormalizeSkills) с навыками справочника
+// по названию или синонимам без учета регистра. Неизвестные навыки добавляются в справочник.
+// Результат - в порядке входа, без повторов (например, "Go" и "golang" дают один навык);
+// у навыков заполнены только ID и Name.
+func (r *SkillRepository) Canonicalize(ctx context.Context, names []string) ([]models.Skill, error) {
+	skills := make([]models.Skill, 0, len(names))
+	seen := make(map[uuid.UUID]bool, len(names))
+	for _, name := range names {
+		skill, err := r.findOrCreate(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		if !seen[skill.ID] {
+			seen[skill.ID] = true
+			skills = append(skills, *skill)
+		}
+	}
+	return skills, nil
+}
+
+// findOrCreate возвращает навык по названию или синониму, создавая его при отсутствии
+func (r *SkillRepository) findOrCreate(ctx context.Context, name string) (*models.Skill, error) {
+	lookup := `
+        SELECT s.id, s.name FROM skills s WHERE lower(s.name) = $1
+        UNION ALL
+        SELECT s.id, s.name FROM skill_aliases a JOIN skills s ON s.id = a.skill_id WHERE a.alias = $1
+        LIMIT 1`
+	key := models.SkillKey(name)
+	// Вторая попытка поиска нужна, если тот же навык одновременно создал другой запрос
+	for attempt := 0; attempt < 2; attempt++ {
+		var skill models.Skill
+		err := r.db.GetContext(ctx, &skill, lookup, key)
+		if err == nil {
+			return &skill, nil
+		}
+		if !errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%w: failed to find skill %q: %v", ErrDatabase, name, err)
+		}
+		err = r.db.GetContext(ctx, &skill, `INSERT INTO skills (name) VALUES ($1) ON CONFLICT DO NOTHING RETURNING id, name`, name)
+		if err == nil {
+			return &skill, nil
+		}
+		if !errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%w: failed to create skill %q: %v", ErrDatabase, name, err)
+		}
+	}
+	return nil, fmt.Errorf("%w: failed to create skill %q", ErrDatabase, name)
+}
+
+// Suggest подбирает навыки для автодополнения: сначала совпадения по началу названия или синонима,
+// затем похожие по триграммам (опечатки), при равенстве - более популярные
+func (r *SkillRepository) Suggest(ctx context.Context, q string, limit int) ([]models.Skill, error) {
+	skills := []models.Skill{}
+	key := models.SkillKey(q)
+	if key == "" {
+		return skills, nil
+	}
+	query := `
+        WITH candidates AS (
+            SELECT id AS skill_id, lower(name) AS variant FROM skills
+            WHERE lower(name) LIKE $2 ESCAPE '\' OR lower(name) % $1
+            UNION ALL
+            SELECT skill_id, alias FROM skill_aliases
+            WHERE alias LIKE $2 ESCAPE '\' OR alias % $1
+        ), ranked AS (
+            SELECT skill_id, bool_or(variant LIKE $2 ESCAPE '\') AS prefix, max(similarity(variant, $1)) AS score
+            FROM candidates GROUP BY skill_id
+        )
+        SELECT ` + skillColumns + `
+        FROM ranked r JOIN skills s ON s.id = r.skill_id
+        ORDER BY r.prefix DESC, r.score DESC, user_count DESC, s.name
+        LIMIT $3`
+	if err := r.db.SelectContext(ctx, &skills, query, key, likeEscaper.Replace(key)+"%", limit); err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("%w: failed to suggest skills: %v", ErrDatabase, err)
+	}
+	return skills, nil
+}
+
+// List возвращает справочник навыков; q (необязательно) отбирает навыки, у которых название
+// или синоним содержит подстроку
+func (r *SkillRepository) List(ctx context.Context, q string) ([]models.Skill, error) {
+	skills := []models.Skill{}
+	query := `
+        SELECT ` + skillColumns + ` FROM skills s
+        WHERE $1 = '' OR lower(s.name) LIKE $2 ESCAPE '\'
+           OR EXISTS (SELECT 1 FROM skill_aliases a WHERE a.skill_id = s.id AND a.alias LIKE $2 ESCAPE '\')
+        ORDER BY lower(s.name)`
+	key := models.SkillKey(q)
+	if err := r.db.SelectContext(ctx, &skills, query, key, "%"+likeEscaper.Replace(key)+"%"); err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("%w: failed to list skills: %v", ErrDatabase, err)
+	}
+	return skills, nil
+}
+
+// GetByID возвращает навык с синонимами и числом пользователей
+func (r *SkillRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Skill, error) {
+	var skill models.Skill
+	if err := r.db.GetContext(ctx, &skill, `SELECT `+skillColumns+` FROM skills s WHERE s.id = $1`, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrSkillNotFound
+		}
+		return nil, fmt.Errorf("%w: failed to get skill: %v", ErrDatabase, err)
+	}
+	return &skill, nil
+}
+
+// Update переименовывает навык и заменяет его синонимы. Новое название сразу появляется в профилях.
+func (r *SkillRepository) Update(ctx context.Context, id uuid.UUID, req models.SkillRequest) (*models.Skill, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to begin skill transaction: %v", ErrDatabase, err)
+	}
+	defer tx.Rollback()
+
+	var oldName string
+	if err := tx.GetContext(ctx, &oldName, `SELECT name FROM skills WHERE id = $1 FOR UPDATE`, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrSkillNotFound
+		}
+		return nil, fmt.Errorf("%w: failed to lock skill: %v", ErrDatabase, err)
+	}
+
+	// Название и синонимы не должны совпадать с названиями и синонимами других навыков
+	var conflict string
+	conflictQuery := `
+        SELECT CASE WHEN v.value = lower($2) THEN 'name' ELSE 'alias' END
+        FROM (SELECT lower(name) AS value, id AS skill_id FROM skills
+              UNION ALL SELECT alias, skill_id FROM skill_aliases) v
+        WHERE v.skill_id <> $1 AND (v.value = lower($2) OR v.value = ANY($3))
+        ORDER BY 1 DESC
+        LIMIT 1`
+	err = tx.GetContext(ctx, &conflict, conflictQuery, id, req.Name, pq.Array(req.Aliases))
+	if err == nil {
+		if conflict == "name" {
+			return nil, ErrSkillNameTaken
+		}
+		return nil, ErrSkillAliasTaken
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("%w: failed to check skill names: %v", ErrDatabase, err)
+	}
+
+	if _, err := tx.ExecContext(ctx, `UPDATE skills SET name = $2 WHERE id = $1`, id, req.Name); err != nil {
+		return nil, skillWriteError(err, ErrSkillNameTaken)
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM skill_aliases WHERE skill_id = $1`, id); err != nil {
+		return nil, fmt.Errorf("%w: failed to replace skill aliases: %v", ErrDatabase, err)
+	}
+	if len(req.Aliases) > 0 {
+		insertAliases := `INSERT INTO skill_aliases (alias, skill_id) SELECT unnest($2::text[]), $1`
+		if _, err := tx.ExecContext(ctx, insertAliases, id, pq.Array(req.Aliases)); err != nil {
+			return nil, skillWriteError(err, ErrSkillAliasTaken)
+		}
+	}
+	if req.Name != oldName {
+		renameQuery := `UPDATE users SET skills = array_replace(skills, $1::varchar, $2::varchar) WHERE $1 = ANY(skills)`
+		if _, err := tx.ExecContext(ctx, renameQuery, oldName, req.Name); err != nil {
+			return nil, fmt.Errorf("%w: failed to rename skill in profiles: %v", ErrDatabase, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("%w: failed to commit skill update: %v", ErrDatabase, err)
+	}
+	return r.GetByID(ctx, id)
+}
+
+// mergeArraySQL - выражение, заменяющее в массиве column значения из $2 (без учета регистра) на $1,
+// без получившихся повторов и с сохранением порядка
+func mergeArraySQL(column string) string {
+	return fmt.Sprintf(`ARRAY(
+            SELECT d.value FROM (
+                SELECT DISTINCT ON (lower(m.value)) m.value, m.ord
+                FROM (SELECT CASE WHEN lower(x.value) = ANY($2) THEN $1 ELSE x.value END AS value, x.ord
+                      FROM unnest(%[1]s) WITH ORDINALITY AS x(value, ord)) m
+                ORDER BY lower(m.value), m.ord
+            ) d
+            ORDER BY d.ord)
+        WHERE EXISTS (SELECT 1 FROM unnest(%[1]s) AS v(value) WHERE lower(v.value) = ANY($2))`, column)
+}
+
+// Merge объединяет дубликаты req.SourceIDs в навык req.TargetID: профили пользователей и теги сессий,
+// серий и шаблонов переводятся на целевой навык, названия и синонимы дубликатов становятся его синонимами,
+// сами дубликаты удаляются. Изменения сессий записываются в их историю от имени actorID.
+func (r *SkillRepository) Merge(ctx context.Context, actorID uuid.UUID, req models.SkillMergeRequest) (*models.SkillMergeResult, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to begin skill merge: %v", ErrDatabase, err)
+	}
+	defer tx.Rollback()
+
+	ids := append([]uuid.UUID{req.TargetID}, req.SourceIDs...)
+	var locked []models.Skill
+	if err := tx.SelectContext(ctx, &locked, `SELECT id, name FROM skills WHERE id = ANY($1) ORDER BY id FOR UPDATE`, pq.Array(ids)); err != nil {
+		return nil, fmt.Errorf("%w: failed to lock skills: %v", ErrDatabase, err)
+	}
+	if len(locked) != len(ids) {
+		return nil, ErrSkillNotFound
+	}
+	var targetName string
+	for _, skill := range locked {
+		if skill.ID == req.TargetID {
+			targetName = skill.Name
+		}
+	}
+
+	// Все написания дубликатов: названия и синонимы в нижнем регистре
+	var variants pq.StringArray
+	variantsQuery := `
+        SELECT ARRAY(
+            SELECT lower(name) FROM skills WHERE id = ANY($1)
+            UNION SELECT alias FROM skill_aliases WHERE skill_id = ANY($1))`
+	if err := tx.GetContext(ctx, &variants, variantsQuery, pq.Array(req.SourceIDs)); err != nil {
+		return nil, fmt.Errorf("%w: failed to collect merged skill names: %v", ErrDatabase, err)
+	}
+
+	aliasQueries := []string{
+		`UPDATE skill_aliases SET skill_id = $1 WHERE skill_id = ANY($2)`,
+		`INSERT INTO skill_aliases (alias, skill_id)
+         SELECT lower(name), $1 FROM skills WHERE id = ANY($2)
+         ON CONFLICT (alias) DO UPDATE SET skill_id = EXCLUDED.skill_id`,
+		`DELETE FROM skill_aliases WHERE skill_id = $1 AND alias = (SELECT lower(name) FROM skills WHERE id = $1)`,
+	}
+	for _, query := range aliasQueries {
+		if _, err := tx.ExecContext(ctx, query, req.TargetID, pq.Array(req.SourceIDs)); err != nil {
+			return nil, fmt.Errorf("%w: failed to move skill aliases: %v", ErrDatabase, err)
+		}
+	}
+
+	result := &models.SkillMergeResult{Merged: len(req.SourceIDs)}
+	if err := setActor(ctx, tx, actorID); err != nil {
+		return nil, err
+	}
+	targets := []struct {
+		query   string
+		value   string
+		counter *int64
+	}{
+		// Профили хранят канонические названия, теги - в нижнем регистре (models.NormalizeTags)
+		{`UPDATE users SET skills = ` + mergeArraySQL("skills"), targetName, &result.Users},
+		{`UPDATE sessions SET tags = ` + mergeArraySQL("tags"), strings.ToLower(targetName), &result.Sessions},
+		{`UPDATE session_series SET tags = ` + mergeArraySQL("tags"), strings.ToLower(targetName), &result.Series},
+		{`UPDATE session_templates SET tags = ` + mergeArraySQL("tags"), strings.ToLower(targetName), &result.Templates},
+	}
+	for _, target := range targets {
+		res, err := tx.ExecContext(ctx, target.query, target.value, variants)
+		if err != nil {
+			return nil, fmt.Errorf("%w: failed to replace merged skills: %v", ErrDatabase, err)
+		}
+		*target.counter, _ = res.RowsAffected()
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM skills WHERE id = ANY($1)`, pq.Array(req.SourceIDs)); err != nil {
+		return nil, fmt.Errorf("%w: failed to delete merged skills: %v", ErrDatabase, err)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("%w: failed to commit skill merge: %v", ErrDatabase, err)
+	}
+
+	skill, err := r.GetByID(ctx, req.TargetID)
+	if err != nil {
+		return nil, err
+	}
+	result.Skill = *skill
+	return result, nil
+}
+
+// skillWriteError преобразует нарушение уникальности названия или синонима в ошибку репозитория
+func skillWriteError(err error, taken error) error {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "23505" { // unique_violation
+		return taken
+	}
+	return fmt.Errorf("%w: failed to save skill: %v", ErrDatabase, err)
+}
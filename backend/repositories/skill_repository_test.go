@@ -0,0 +1,68 @@
+package repositories_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/BuzzLyutic/Skill-sharing-web-platform/models"
+	"github.com/BuzzLyutic/Skill-sharing-web-platform/repositories"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newSkillRepoWithMock(t *testing.T) (*repositories.SkillRepository, sqlmock.Sqlmock) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	return repositories.NewSkillRepository(sqlx.NewDb(db, "sqlmock")), mock
+}
+
+func TestSkillRepository_Canonicalize_ResolvesAliasesAndCreatesUnknown(t *testing.T) {
+	repo, mock := newSkillRepoWithMock(t)
+	goID, elixirID := uuid.New(), uuid.New()
+	lookup := `SELECT s.id, s.name FROM skills s WHERE lower\(s.name\) = \$1\s+UNION ALL\s+SELECT s.id, s.name FROM skill_aliases a`
+
+	mock.ExpectQuery(lookup).WithArgs("golang").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(goID, "Go"))
+	mock.ExpectQuery(lookup).WithArgs("elixir").WillReturnRows(sqlmock.NewRows([]string{"id", "name"}))
+	mock.ExpectQuery(`INSERT INTO skills \(name\) VALUES \(\$1\) ON CONFLICT DO NOTHING RETURNING id, name`).WithArgs("Elixir").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(elixirID, "Elixir"))
+	mock.ExpectQuery(lookup).WithArgs("go").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(goID, "Go"))
+
+	skills, err := repo.Canonicalize(context.Background(), []string{"GoLang", "Elixir", "Go"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Go", "Elixir"}, models.SkillNames(skills))
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSkillRepository_Suggest_EscapesLikePattern(t *testing.T) {
+	repo, mock := newSkillRepoWithMock(t)
+
+	mock.ExpectQuery(`WITH candidates AS .*lower\(name\) % \$1.*ORDER BY r.prefix DESC, r.score DESC`).
+		WithArgs("c_%", `c\_\%%`, 5).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "created_at", "aliases", "user_count"}))
+
+	skills, err := repo.Suggest(context.Background(), "  C_% ", 5)
+	require.NoError(t, err)
+	assert.Empty(t, skills)
+	assert.NotNil(t, skills)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSkillRepository_Merge_UnknownSkill(t *testing.T) {
+	repo, mock := newSkillRepoWithMock(t)
+	target, source := uuid.New(), uuid.New()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT id, name FROM skills WHERE id = ANY\(\$1\) ORDER BY id FOR UPDATE`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(target, "Go"))
+	mock.ExpectRollback()
+
+	_, err := repo.Merge(context.Background(), uuid.New(), models.SkillMergeRequest{TargetID: target, SourceIDs: []uuid.UUID{source}})
+	assert.ErrorIs(t, err, repositories.ErrSkillNotFound)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
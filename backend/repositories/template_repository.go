@@ -0,0 +1,162 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/BuzzLyutic/Skill-sharing-web-platform/models"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+var (
+	ErrTemplateNotFound      = errors.New("session template not found")
+	ErrTemplateShareNotFound = errors.New("session template is not shared with this user")
+)
+
+// TemplateRepository обрабатывает шаблоны сессий и доступ к ним других пользователей
+type TemplateRepository struct {
+	db *sqlx.DB
+}
+
+// NewTemplateRepository создает новый репозиторий шаблонов
+func NewTemplateRepository(db *sqlx.DB) *TemplateRepository {
+	return &TemplateRepository{db: db}
+}
+
+// templateSelect выбирает шаблоны, доступные пользователю $1: собственные и те, которыми с ним поделились
+const templateSelect = `
+        SELECT t.*, (t.owner_id <> $1) AS is_shared
+        FROM session_templates t
+        WHERE (t.owner_id = $1 OR EXISTS (
+            SELECT 1 FROM session_template_shares s WHERE s.template_id = t.id AND s.user_id = $1
+        ))`
+
+// Create сохраняет шаблон пользователя. req должен быть нормализован (SessionTemplateRequest.Normalize).
+func (r *TemplateRepository) Create(ctx context.Context, ownerID uuid.UUID, req models.SessionTemplateRequest) (*models.SessionTemplate, error) {
+	var template models.SessionTemplate
+	query := `
+        INSERT INTO session_templates (owner_id, name, title, description, category, duration_minutes, time_zone, location,
+            latitude, longitude, venue_address, max_participants, tags, search_language, visibility, requires_approval)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)
+        RETURNING *, FALSE AS is_shared`
+	err := r.db.GetContext(ctx, &template, query,
+		ownerID, req.Name, req.Title, req.Description, req.Category, req.DurationMinutes, req.TimeZone, req.Location,
+		req.Latitude, req.Longitude, req.VenueAddress, req.MaxParticipants, pq.Array(req.Tags), req.SearchLanguage,
+		req.Visibility, req.RequiresApproval,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to create session template: %v", ErrDatabase, err)
+	}
+	return &template, nil
+}
+
+// ListForUser возвращает собственные и доступные пользователю шаблоны, сначала собственные
+func (r *TemplateRepository) ListForUser(ctx context.Context, userID uuid.UUID) ([]models.SessionTemplate, error) {
+	templates := []models.SessionTemplate{}
+	query := templateSelect + ` ORDER BY is_shared ASC, t.name ASC, t.created_at DESC`
+	err := r.db.SelectContext(ctx, &templates, query, userID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		log.Printf("ERROR listing session templates for user %s: %v", userID, err)
+		return nil, fmt.Errorf("%w: failed to list session templates: %v", ErrDatabase, err)
+	}
+	return templates, nil
+}
+
+// GetForUser возвращает шаблон, если он принадлежит пользователю или им поделились с пользователем.
+// Иначе - ErrTemplateNotFound, чтобы не раскрывать существование чужих шаблонов.
+func (r *TemplateRepository) GetForUser(ctx context.Context, templateID, userID uuid.UUID) (*models.SessionTemplate, error) {
+	var template models.SessionTemplate
+	query := templateSelect + ` AND t.id = $2`
+	err := r.db.GetContext(ctx, &template, query, userID, templateID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrTemplateNotFound
+		}
+		return nil, fmt.Errorf("%w: failed to get session template: %v", ErrDatabase, err)
+	}
+	return &template, nil
+}
+
+// Update перезаписывает шаблон владельца
+func (r *TemplateRepository) Update(ctx context.Context, templateID, ownerID uuid.UUID, req models.SessionTemplateRequest) (*models.SessionTemplate, error) {
+	var template models.SessionTemplate
+	query := `
+        UPDATE session_templates
+        SET name = $3, title = $4, description = $5, category = $6, duration_minutes = $7, time_zone = $8, location = $9,
+            latitude = $10, longitude = $11, venue_address = $12, max_participants = $13, tags = $14,
+            search_language = $15, visibility = $16, requires_approval = $17, updated_at = NOW()
+        WHERE id = $1 AND owner_id = $2
+        RETURNING *, FALSE AS is_shared`
+	err := r.db.GetContext(ctx, &template, query,
+		templateID, ownerID, req.Name, req.Title, req.Description, req.Category, req.DurationMinutes, req.TimeZone, req.Location,
+		req.Latitude, req.Longitude, req.VenueAddress, req.MaxParticipants, pq.Array(req.Tags), req.SearchLanguage,
+		req.Visibility, req.RequiresApproval,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrTemplateNotFound
+		}
+		return nil, fmt.Errorf("%w: failed to update session template: %v", ErrDatabase, err)
+	}
+	return &template, nil
+}
+
+// Delete удаляет шаблон владельца вместе с доступами
+func (r *TemplateRepository) Delete(ctx context.Context, templateID, ownerID uuid.UUID) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM session_templates WHERE id = $1 AND owner_id = $2`, templateID, ownerID)
+	if err != nil {
+		return fmt.Errorf("%w: failed to delete session template: %v", ErrDatabase, err)
+	}
+	if rowsAffected, _ := result.RowsAffected(); rowsAffected == 0 {
+		return ErrTemplateNotFound
+	}
+	return nil
+}
+
+// ListShares возвращает пользователей, которым владелец открыл шаблон
+func (r *TemplateRepository) ListShares(ctx context.Context, templateID uuid.UUID) ([]models.TemplateShare, error) {
+	shares := []models.TemplateShare{}
+	query := `
+        SELECT s.template_id, s.user_id, u.name, u.email, s.shared_at
+        FROM session_template_shares s
+        JOIN users u ON u.id = s.user_id
+        WHERE s.template_id = $1
+        ORDER BY s.shared_at ASC`
+	if err := r.db.SelectContext(ctx, &shares, query, templateID); err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("%w: failed to list template shares: %v", ErrDatabase, err)
+	}
+	return shares, nil
+}
+
+// Share открывает шаблон владельца пользователю userID. Повторный вызов ничего не меняет.
+func (r *TemplateRepository) Share(ctx context.Context, templateID, ownerID, userID uuid.UUID) error {
+	query := `
+        INSERT INTO session_template_shares (template_id, user_id)
+        SELECT id, $3::uuid FROM session_templates WHERE id = $1 AND owner_id = $2
+        ON CONFLICT (template_id, user_id) DO NOTHING`
+	if _, err := r.db.ExecContext(ctx, query, templateID, ownerID, userID); err != nil {
+		return fmt.Errorf("%w: failed to share session template: %v", ErrDatabase, err)
+	}
+	return nil
+}
+
+// Unshare закрывает пользователю доступ к шаблону владельца
+func (r *TemplateRepository) Unshare(ctx context.Context, templateID, ownerID, userID uuid.UUID) error {
+	query := `
+        DELETE FROM session_template_shares s
+        USING session_templates t
+        WHERE s.template_id = t.id AND t.id = $1 AND t.owner_id = $2 AND s.user_id = $3`
+	result, err := r.db.ExecContext(ctx, query, templateID, ownerID, userID)
+	if err != nil {
+		return fmt.Errorf("%w: failed to unshare session template: %v", ErrDatabase, err)
+	}
+	if rowsAffected, _ := result.RowsAffected(); rowsAffected == 0 {
+		return ErrTemplateShareNotFound
+	}
+	return nil
+}
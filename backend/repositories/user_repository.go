@@ -34,7 +34,7 @@ var _ UserRepositoryInterface = (*UserRepository)(nil) // Static check
 func (r *UserRepository) GetAll(ctx context.Context) ([]models.User, error) {
         users := []models.User{}
         query := `
-		SELECT id, email, oauth_provider, oauth_id, name, bio, skills, average_rating, created_at, updated_at, role
+		SELECT id, email, oauth_provider, oauth_id, name, bio, skills, average_rating, time_zone, created_at, updated_at, role
 		FROM users ORDER BY created_at DESC`
 	err := r.db.SelectContext(ctx, &users, query) // Используем SelectContext
 	if err != nil && !errors.Is(err, sql.ErrNoRows) {
@@ -70,16 +70,16 @@ func (r *UserRepository) Update(ctx context.Context, id uuid.UUID, req models.Us
         // Явно указываем обновляемые и возвращаемые поля
 	query := `
 		UPDATE users
-		SET name = $2, bio = $3, skills = $4, updated_at = NOW()
+		SET name = $2, bio = $3, skills = $4, time_zone = COALESCE(NULLIF($5, ''), time_zone), updated_at = NOW()
 		WHERE id = $1
-		RETURNING id, email, oauth_provider, oauth_id, name, bio, skills, average_rating, created_at, updated_at, role
+		RETURNING id, email, oauth_provider, oauth_id, name, bio, skills, average_rating, time_zone, created_at, updated_at, role
 	`
         var bio sql.NullString
         if req.Bio != "" { bio = sql.NullString{String: req.Bio, Valid: true} }
         skills := pq.Array(req.Skills)
         if req.Skills == nil { skills = pq.Array([]string{}) }
 
-	err := r.db.GetContext(ctx, &updatedUser, query, id, req.Name, bio, skills)
+	err := r.db.GetContext(ctx, &updatedUser, query, id, req.Name, bio, skills, req.TimeZone)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, ErrUserNotFound
@@ -1,12 +1,17 @@
 package routes
 
 import (
+        "log"
+        "net/http"
+
+        "github.com/BuzzLyutic/Skill-sharing-web-platform/checkin"
         "github.com/BuzzLyutic/Skill-sharing-web-platform/controllers"
         "github.com/BuzzLyutic/Skill-sharing-web-platform/handlers"
         "github.com/BuzzLyutic/Skill-sharing-web-platform/middleware"
         "github.com/BuzzLyutic/Skill-sharing-web-platform/repositories"
         "github.com/BuzzLyutic/Skill-sharing-web-platform/models"
         "github.com/BuzzLyutic/Skill-sharing-web-platform/config"
+        "github.com/BuzzLyutic/Skill-sharing-web-platform/storage"
         "github.com/gin-contrib/cors"
         "github.com/gin-gonic/gin"
         "github.com/jmoiron/sqlx"
@@ -16,6 +21,10 @@ import (
 func SetupRouter(db *sqlx.DB) *gin.Engine {
         r := gin.Default()
         cfg := config.LoadConfig()
+        // Коды отметки присутствия подписываются своим ключом, а не ключом токенов входа
+        if cfg.CheckInSecret == "" || cfg.CheckInSecret == cfg.JWTConfig.SecretKey {
+            log.Fatalf("CHECK_IN_SECRET must be set and differ from JWT_SECRET_KEY")
+        }
     
         jwtCfg := config.GetJWTConfig()
         // Middleware
@@ -26,7 +35,7 @@ func SetupRouter(db *sqlx.DB) *gin.Engine {
         // Настройка CORS
         corsConfig := cors.DefaultConfig()
         corsConfig.AllowOrigins = []string{"http://localhost:3000", "http://localhost:3001", "https://skill-sharing-web-platform-frontend.onrender.com", "https://accounts.google.com",}
-        corsConfig.AllowMethods = []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"}
+        corsConfig.AllowMethods = []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"}
         corsConfig.AllowHeaders = []string{"Origin", "Content-Type", "Authorization"}
         corsConfig.AllowCredentials = true
         r.Use(cors.New(corsConfig))
@@ -36,12 +45,43 @@ func SetupRouter(db *sqlx.DB) *gin.Engine {
         sessionRepo := repositories.NewSessionRepository(db)
         feedbackRepo := repositories.NewFeedbackRepository(db)
         notifRepo := repositories.NewNotificationRepository(db)
+        seriesRepo := repositories.NewSeriesRepository(db)
+        hostRepo := repositories.NewHostRepository(db)
+        inviteRepo := repositories.NewInviteRepository(db)
+        joinRequestRepo := repositories.NewJoinRequestRepository(db)
+        attendanceRepo := repositories.NewAttendanceRepository(db)
+        attachmentRepo := repositories.NewAttachmentRepository(db)
+        commentRepo := repositories.NewCommentRepository(db)
+        questionRepo := repositories.NewQuestionRepository(db)
+        templateRepo := repositories.NewTemplateRepository(db)
+        calendarFeedRepo := repositories.NewCalendarFeedRepository(db)
+        reconfirmRepo := repositories.NewReconfirmationRepository(db)
+        categoryRepo := repositories.NewCategoryRepository(db)
+        skillRepo := repositories.NewSkillRepository(db)
+
+        // Хранилище вложений
+        storageCfg := config.GetStorageConfig()
+        fileStorage, localFiles := newFileStorage(storageCfg, cfg.JWTConfig.SecretKey)
 
         // Инициализация контроллеров
-        userController := controllers.NewUserController(userRepo)
-        sessionController := controllers.NewSessionController(sessionRepo, userRepo, notifRepo)
-        feedbackController := controllers.NewFeedbackController(feedbackRepo, sessionRepo)
+        userController := controllers.NewUserController(userRepo, skillRepo)
+        sessionController := controllers.NewSessionController(sessionRepo, userRepo, notifRepo, seriesRepo, hostRepo, inviteRepo, reconfirmRepo, categoryRepo, cfg.FrontendURL)
+        seriesController := controllers.NewSeriesController(seriesRepo, sessionRepo, userRepo, notifRepo, hostRepo, categoryRepo)
+        hostController := controllers.NewHostController(hostRepo, sessionRepo, userRepo, notifRepo)
+        inviteController := controllers.NewInviteController(inviteRepo, sessionRepo, hostRepo, cfg.FrontendURL)
+        joinRequestController := controllers.NewJoinRequestController(joinRequestRepo, sessionRepo, hostRepo, notifRepo)
+        attendanceController := controllers.NewAttendanceController(attendanceRepo, sessionRepo, hostRepo, checkin.NewCoder(cfg.CheckInSecret, checkin.DefaultPeriod), cfg.FrontendURL)
+        attachmentController := controllers.NewAttachmentController(attachmentRepo, sessionRepo, hostRepo, fileStorage, storageCfg.MaxFileSize, storageCfg.URLTTL)
+        commentController := controllers.NewCommentController(commentRepo, sessionRepo, hostRepo, notifRepo)
+        questionController := controllers.NewQuestionController(questionRepo, sessionRepo, hostRepo)
+        templateController := controllers.NewTemplateController(templateRepo, sessionRepo, userRepo, categoryRepo)
+        importController := controllers.NewImportController(sessionRepo, categoryRepo)
+        calendarFeedController := controllers.NewCalendarFeedController(calendarFeedRepo, storageCfg.PublicURL, cfg.FrontendURL)
+        reconfirmationController := controllers.NewReconfirmationController(reconfirmRepo, sessionRepo, hostRepo, notifRepo)
+        feedbackController := controllers.NewFeedbackController(feedbackRepo, sessionRepo, attendanceRepo)
         notificationController := controllers.NewNotificationController(notifRepo)
+        categoryController := controllers.NewCategoryController(categoryRepo)
+        skillController := controllers.NewSkillController(skillRepo)
     
         // Инициализация обработчиков аутентификации
         authHandler := handlers.NewAuthHandler(db, jwtCfg)
@@ -60,6 +100,19 @@ func SetupRouter(db *sqlx.DB) *gin.Engine {
         }
 
         r.GET("/api/sessions/recommended", sessionController.GetRecommendedSessions)
+        r.GET("/api/categories", categoryController.List)
+        r.GET("/api/skills/suggest", skillController.Suggest) // Нужно и на форме регистрации
+
+        // Подписка на календарь: календари не передают JWT, доступ по секретному токену в ссылке
+        r.GET("/api/calendar/:token", calendarFeedController.Feed)
+        r.HEAD("/api/calendar/:token", calendarFeedController.Feed)
+
+        // Файлы локального хранилища: доступ проверяется подписью ссылки, а не JWT
+        if localFiles != nil {
+            filesHandler := gin.WrapH(http.StripPrefix("/files", localFiles))
+            r.GET("/files/*key", filesHandler)
+            r.HEAD("/files/*key", filesHandler)
+        }
     
         // API routes (защищенные маршруты)
         api := r.Group("/api")
@@ -77,6 +130,10 @@ func SetupRouter(db *sqlx.DB) *gin.Engine {
                 users.GET("/me", authHandler.GetMe)
                 users.PUT("/me", userController.UpdateMe)
                 users.PUT("/me/password", userController.ChangePassword)
+                users.GET("/me/conflicts", sessionController.GetMyConflicts)
+                users.GET("/me/calendar-feed", calendarFeedController.Status)
+                users.POST("/me/calendar-feed", calendarFeedController.Rotate)
+                users.DELETE("/me/calendar-feed", calendarFeedController.Revoke)
             }
 
             // Admin Routes
@@ -93,6 +150,19 @@ func SetupRouter(db *sqlx.DB) *gin.Engine {
                 adminSessions := admin.Group("/sessions")
                 {
                 adminSessions.DELETE("/:id", sessionController.AdminDeleteSession)
+                }
+                adminCategories := admin.Group("/categories")
+                {
+                    adminCategories.GET("", categoryController.List)
+                    adminCategories.POST("", categoryController.Create)
+                    adminCategories.PUT("/:id", categoryController.Update)
+                    adminCategories.DELETE("/:id", categoryController.Delete)
+                }
+                adminSkills := admin.Group("/skills")
+                {
+                    adminSkills.GET("", skillController.List)
+                    adminSkills.PUT("/:id", skillController.Update)
+                    adminSkills.POST("/merge", skillController.Merge)
                 }
 		    }
 
@@ -100,6 +170,7 @@ func SetupRouter(db *sqlx.DB) *gin.Engine {
             moderator.Use(moderatorAuth)
             {
                 moderator.DELETE("/sessions/:id", sessionController.Delete)
+                moderator.DELETE("/comments/:comment_id", commentController.ModeratorDelete)
             }
     
             // Session routes
@@ -111,8 +182,17 @@ func SetupRouter(db *sqlx.DB) *gin.Engine {
                 sessions.GET("/joined", sessionController.GetJoinedSessions)
                 sessions.GET("/:id", sessionController.GetByID)
                 sessions.POST("", sessionController.Create)
+                sessions.POST("/import", importController.Import)
                 sessions.PUT("/:id", sessionController.Update)
                 sessions.DELETE("/:id", sessionController.Delete)
+                sessions.POST("/:id/publish", sessionController.Publish)
+                sessions.POST("/:id/cancel", sessionController.Cancel)
+                sessions.POST("/:id/clone", sessionController.Clone)
+                sessions.GET("/:id/history", sessionController.History)
+                sessions.POST("/:id/history/:version/restore", sessionController.RestoreVersion)
+                sessions.GET("/:id/reconfirmations", reconfirmationController.List)
+                sessions.GET("/:id/reconfirmation", reconfirmationController.Get)
+                sessions.POST("/:id/reconfirmation", reconfirmationController.Respond)
                 sessions.GET("/:id/participants", sessionController.GetParticipants)
                 sessions.GET("/:id/ics", sessionController.ExportSessionICS)
                 
@@ -120,6 +200,67 @@ func SetupRouter(db *sqlx.DB) *gin.Engine {
 			    sessions.POST("/:id/join", sessionController.JoinSession)
 			    sessions.POST("/:id/leave", sessionController.LeaveSession)
 
+			    // Endpoints для организаторов сессии (владелец, соорганизаторы, помощники)
+			    hosts := sessions.Group("/:id/hosts")
+			    {
+				    hosts.GET("", hostController.ListHosts)
+				    hosts.POST("", hostController.Invite)
+				    hosts.POST("/accept", hostController.Accept)
+				    hosts.DELETE("/:user_id", hostController.Remove)
+			    }
+
+			    // Приглашения в сессии unlisted/invite_only (владелец и соорганизаторы)
+			    invites := sessions.Group("/:id/invites")
+			    {
+				    invites.GET("", inviteController.List)
+				    invites.POST("", inviteController.Create)
+				    invites.DELETE("/:invite_id", inviteController.Revoke)
+			    }
+
+			    // Заявки на участие в сессиях с одобрением (владелец и соорганизаторы)
+			    joinRequests := sessions.Group("/:id/requests")
+			    {
+				    joinRequests.GET("", joinRequestController.List)
+				    joinRequests.POST("/:user_id/approve", joinRequestController.Approve)
+				    joinRequests.POST("/:user_id/reject", joinRequestController.Reject)
+			    }
+
+			    // Отметка присутствия: организаторы отмечают участников и показывают код,
+			    // участники отмечаются сами по коду или QR
+			    sessions.GET("/:id/attendance", attendanceController.List)
+			    sessions.PUT("/:id/attendance", attendanceController.Update)
+			    sessions.GET("/:id/check-in/code", attendanceController.Code)
+			    sessions.POST("/:id/check-in", attendanceController.CheckIn)
+
+			    // Материалы сессии: загружают и удаляют организаторы, скачивают участники по подписанным ссылкам
+			    attachments := sessions.Group("/:id/attachments")
+			    {
+				    attachments.GET("", attachmentController.List)
+				    attachments.POST("", attachmentController.Upload)
+				    attachments.GET("/:attachment_id/download", attachmentController.Download)
+				    attachments.DELETE("/:attachment_id", attachmentController.Delete)
+			    }
+
+			    // Обсуждение сессии (организаторы и участники)
+			    comments := sessions.Group("/:id/comments")
+			    {
+				    comments.GET("", commentController.List)
+				    comments.POST("", commentController.Create)
+				    comments.PUT("/:comment_id", commentController.Update)
+				    comments.DELETE("/:comment_id", commentController.Delete)
+			    }
+
+			    // Вопросы к сессии (Q&A) с голосованием
+			    questions := sessions.Group("/:id/questions")
+			    {
+				    questions.GET("", questionController.List)
+				    questions.POST("", questionController.Create)
+				    questions.PATCH("/:question_id", questionController.Update)
+				    questions.DELETE("/:question_id", questionController.Delete)
+				    questions.POST("/:question_id/vote", questionController.Vote)
+				    questions.DELETE("/:question_id/vote", questionController.Unvote)
+			    }
+
 			    // Endpoints для отзывов/рейтингов
 			    feedback := sessions.Group("/:id/feedback")
 			    {
@@ -128,6 +269,30 @@ func SetupRouter(db *sqlx.DB) *gin.Engine {
 			    }
             }
 
+            // Session series routes (повторяющиеся сессии)
+            series := api.Group("/series")
+            {
+                series.POST("", seriesController.Create)
+                series.GET("/:id", seriesController.GetByID)
+                series.DELETE("/:id", seriesController.Delete)
+                series.POST("/:id/join", seriesController.JoinSeries)
+                series.POST("/:id/leave", seriesController.LeaveSeries)
+            }
+
+            // Шаблоны сессий
+            templates := api.Group("/templates")
+            {
+                templates.GET("", templateController.List)
+                templates.POST("", templateController.Create)
+                templates.GET("/:id", templateController.Get)
+                templates.PUT("/:id", templateController.Update)
+                templates.DELETE("/:id", templateController.Delete)
+                templates.POST("/:id/sessions", templateController.CreateSession)
+                templates.GET("/:id/shares", templateController.ListShares)
+                templates.POST("/:id/shares", templateController.Share)
+                templates.DELETE("/:id/shares/:user_id", templateController.Unshare)
+            }
+
             // Notification routes
             notifications := api.Group("/notifications")
             {
@@ -139,3 +304,36 @@ func SetupRouter(db *sqlx.DB) *gin.Engine {
     
         return r
     }
+
+    // newFileStorage создает хранилище вложений по настройкам. Для локального хранилища
+    // возвращает его же вторым значением, чтобы смонтировать обработчик подписанных ссылок.
+    func newFileStorage(cfg config.StorageConfig, jwtSecret string) (storage.Storage, *storage.LocalStorage) {
+        switch cfg.Backend {
+        case "s3":
+            s3Storage, err := storage.NewS3Storage(storage.S3Config{
+                Endpoint:  cfg.S3Endpoint,
+                Region:    cfg.S3Region,
+                Bucket:    cfg.S3Bucket,
+                AccessKey: cfg.S3AccessKey,
+                SecretKey: cfg.S3SecretKey,
+                PathStyle: cfg.S3PathStyle,
+            })
+            if err != nil {
+                log.Fatalf("Failed to configure S3 attachment storage: %v", err)
+            }
+            return s3Storage, nil
+        case "local":
+            // Ссылки на файлы подписываются своим ключом: утечка одного секрета не должна раскрывать другой
+            if cfg.SigningSecret == jwtSecret {
+                log.Fatalf("STORAGE_SIGNING_SECRET must differ from JWT_SECRET_KEY")
+            }
+            localStorage, err := storage.NewLocalStorage(cfg.LocalDir, cfg.PublicURL+"/files", cfg.SigningSecret)
+            if err != nil {
+                log.Fatalf("Failed to configure local attachment storage: %v", err)
+            }
+            return localStorage, localStorage
+        default:
+            log.Fatalf("Unknown STORAGE_BACKEND %q, expected local or s3", cfg.Backend)
+            return nil, nil
+        }
+    }
@@ -0,0 +1,165 @@
+package storage
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// LocalStorage хранит файлы в каталоге на диске и сам отдает их по подписанным ссылкам.
+// Ссылка ведет на baseURL, где должен быть смонтирован обработчик LocalStorage (ServeHTTP);
+// подпись HMAC-SHA256 покрывает ключ, срок действия и имя файла.
+type LocalStorage struct {
+	root    string
+	baseURL string
+	secret  []byte
+}
+
+// NewLocalStorage создает хранилище в каталоге root (создается при необходимости).
+// baseURL - внешний адрес, по которому смонтирован обработчик файлов, secret - ключ подписи ссылок.
+func NewLocalStorage(root, baseURL, secret string) (*LocalStorage, error) {
+	if secret == "" {
+		return nil, errors.New("local storage: signing secret is required")
+	}
+	if err := os.MkdirAll(root, 0o750); err != nil {
+		return nil, fmt.Errorf("local storage: failed to create %s: %w", root, err)
+	}
+	return &LocalStorage{root: root, baseURL: strings.TrimSuffix(baseURL, "/"), secret: []byte(secret)}, nil
+}
+
+// Put записывает файл во временный файл рядом с целевым и переименовывает его,
+// чтобы по ссылке никогда не отдавался недописанный файл
+func (s *LocalStorage) Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return fmt.Errorf("local storage: failed to create directory: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".upload-*")
+	if err != nil {
+		return fmt.Errorf("local storage: failed to create file: %w", err)
+	}
+	defer os.Remove(tmp.Name()) // После успешного Rename файла уже нет
+
+	written, err := io.Copy(tmp, io.LimitReader(body, size+1))
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("local storage: failed to write %s: %w", key, err)
+	}
+	if written != size {
+		return fmt.Errorf("local storage: expected %d bytes for %s, got %d", size, key, written)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("local storage: failed to store %s: %w", key, err)
+	}
+	return nil
+}
+
+// Delete удаляет файл
+func (s *LocalStorage) Delete(ctx context.Context, key string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("local storage: failed to delete %s: %w", key, err)
+	}
+	return nil
+}
+
+// SignedURL возвращает ссылку на обработчик файлов с подписью и сроком действия
+func (s *LocalStorage) SignedURL(ctx context.Context, key, fileName string, expiresAt time.Time) (string, error) {
+	if _, err := s.path(key); err != nil {
+		return "", err
+	}
+	expires := strconv.FormatInt(expiresAt.Unix(), 10)
+	query := url.Values{
+		"expires":   {expires},
+		"name":      {fileName},
+		"signature": {s.sign(key, expires, fileName)},
+	}
+	return s.baseURL + "/" + uriEncode(key, false) + "?" + query.Encode(), nil
+}
+
+// ServeHTTP отдает файл по подписанной ссылке. Путь запроса - ключ объекта
+// (обработчик монтируется через http.StripPrefix).
+func (s *LocalStorage) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	key := strings.TrimPrefix(r.URL.Path, "/")
+	query := r.URL.Query()
+	expires, fileName := query.Get("expires"), query.Get("name")
+	expiresUnix, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil || time.Now().Unix() >= expiresUnix {
+		http.Error(w, "link is invalid or expired", http.StatusForbidden)
+		return
+	}
+	if !hmac.Equal([]byte(query.Get("signature")), []byte(s.sign(key, expires, fileName))) {
+		http.Error(w, "link is invalid or expired", http.StatusForbidden)
+		return
+	}
+
+	path, err := s.path(key)
+	if err != nil {
+		http.Error(w, "not found", http.StatusNotFound)
+		return
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		http.Error(w, "not found", http.StatusNotFound)
+		return
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil {
+		http.Error(w, "not found", http.StatusNotFound)
+		return
+	}
+
+	// Тип определяется по расширению ключа, которое выбирает сервер, а не по имени от клиента:
+	// иначе файл "x.html" отдавался бы как text/html с нашего домена
+	contentType := mime.TypeByExtension(filepath.Ext(key))
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", contentDisposition(fileName))
+	w.Header().Set("Cache-Control", "private, max-age=0")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	http.ServeContent(w, r, fileName, info.ModTime(), file)
+}
+
+// sign вычисляет подпись ссылки
+func (s *LocalStorage) sign(key, expires, fileName string) string {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(key + "\n" + expires + "\n" + fileName))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// path преобразует ключ в путь внутри root, отклоняя попытки выйти за его пределы
+func (s *LocalStorage) path(key string) (string, error) {
+	clean := filepath.Clean(filepath.FromSlash(key))
+	if key == "" || filepath.IsAbs(clean) || clean == ".." || strings.HasPrefix(clean, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("local storage: invalid key %q", key)
+	}
+	return filepath.Join(s.root, clean), nil
+}
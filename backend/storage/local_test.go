@@ -0,0 +1,87 @@
+package storage
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocalStorage_SignedDownload(t *testing.T) {
+	s, err := NewLocalStorage(t.TempDir(), "http://api.example.com/files", "secret")
+	require.NoError(t, err)
+	server := httptest.NewServer(http.StripPrefix("/files", s))
+	defer server.Close()
+	s.baseURL = server.URL + "/files"
+
+	ctx := context.Background()
+	require.NoError(t, s.Put(ctx, "sessions/1/slides.pdf", strings.NewReader("%PDF-1.4 test"), 13, "application/pdf"))
+
+	signed, err := s.SignedURL(ctx, "sessions/1/slides.pdf", "Intro slides.pdf", time.Now().Add(time.Minute))
+	require.NoError(t, err)
+	resp, err := http.Get(signed)
+	require.NoError(t, err)
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "%PDF-1.4 test", string(body))
+	assert.Equal(t, "attachment; filename*=UTF-8''Intro%20slides.pdf", resp.Header.Get("Content-Disposition"))
+	assert.Equal(t, "application/pdf", resp.Header.Get("Content-Type"))
+	assert.Equal(t, "nosniff", resp.Header.Get("X-Content-Type-Options"))
+
+	for name, link := range map[string]string{
+		"tampered name": strings.Replace(signed, "name=Intro", "name=Other", 1),
+		"other key":     strings.Replace(signed, "/sessions/1/", "/sessions/2/", 1),
+	} {
+		resp, err := http.Get(link)
+		require.NoError(t, err, name)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode, name)
+	}
+
+	expired, err := s.SignedURL(ctx, "sessions/1/slides.pdf", "slides.pdf", time.Now().Add(-time.Second))
+	require.NoError(t, err)
+	resp, err = http.Get(expired)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+
+	require.NoError(t, s.Delete(ctx, "sessions/1/slides.pdf"))
+	require.NoError(t, s.Delete(ctx, "sessions/1/slides.pdf"))
+}
+
+func TestLocalStorage_ContentTypeIgnoresDownloadName(t *testing.T) {
+	s, err := NewLocalStorage(t.TempDir(), "http://api.example.com/files", "secret")
+	require.NoError(t, err)
+	server := httptest.NewServer(http.StripPrefix("/files", s))
+	defer server.Close()
+	s.baseURL = server.URL + "/files"
+
+	ctx := context.Background()
+	require.NoError(t, s.Put(ctx, "sessions/1/notes.txt", strings.NewReader("<html>"), 6, "text/plain"))
+
+	signed, err := s.SignedURL(ctx, "sessions/1/notes.txt", "x.html", time.Now().Add(time.Minute))
+	require.NoError(t, err)
+	resp, err := http.Get(signed)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.True(t, strings.HasPrefix(resp.Header.Get("Content-Type"), "text/plain"), resp.Header.Get("Content-Type"))
+}
+
+func TestLocalStorage_RejectsInvalidKeysAndShortBodies(t *testing.T) {
+	s, err := NewLocalStorage(t.TempDir(), "http://localhost/files", "secret")
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	assert.Error(t, s.Put(ctx, "../escape.txt", strings.NewReader("x"), 1, "text/plain"))
+	assert.Error(t, s.Put(ctx, "sessions/short.txt", strings.NewReader("abc"), 10, "text/plain"))
+	_, err = s.SignedURL(ctx, "../../etc/passwd", "passwd", time.Now().Add(time.Minute))
+	assert.Error(t, err)
+}
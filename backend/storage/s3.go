@@ -0,0 +1,250 @@
+package storage
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// S3Config - параметры подключения к S3-совместимому хранилищу
+type S3Config struct {
+	Endpoint  string // Например https://s3.eu-central-1.amazonaws.com или http://localhost:9000 для MinIO
+	Region    string
+	Bucket    string
+	AccessKey string
+	SecretKey string
+	PathStyle bool // Адрес вида endpoint/bucket/key (нужен MinIO) вместо bucket.endpoint/key
+}
+
+// S3Storage хранит файлы в бакете S3-совместимого хранилища.
+// Запросы подписываются AWS Signature Version 4, ссылки на скачивание - предподписанные URL.
+type S3Storage struct {
+	endpoint  *url.URL
+	region    string
+	bucket    string
+	accessKey string
+	secretKey string
+	pathStyle bool
+	client    *http.Client
+	now       func() time.Time
+}
+
+// maxPresignExpiry - максимальный срок действия предподписанного URL в S3
+const maxPresignExpiry = 7 * 24 * time.Hour
+
+// unsignedPayload - тело запроса не входит в подпись (разрешено S3 для потоковой загрузки)
+const unsignedPayload = "UNSIGNED-PAYLOAD"
+
+// NewS3Storage создает клиент хранилища. Существование бакета не проверяется.
+func NewS3Storage(cfg S3Config) (*S3Storage, error) {
+	endpoint, err := url.Parse(cfg.Endpoint)
+	if err != nil || endpoint.Host == "" || (endpoint.Scheme != "http" && endpoint.Scheme != "https") {
+		return nil, fmt.Errorf("s3 storage: invalid endpoint %q", cfg.Endpoint)
+	}
+	if cfg.Bucket == "" || cfg.AccessKey == "" || cfg.SecretKey == "" {
+		return nil, errors.New("s3 storage: bucket, access key and secret key are required")
+	}
+	region := cfg.Region
+	if region == "" {
+		region = "us-east-1"
+	}
+	return &S3Storage{
+		endpoint:  endpoint,
+		region:    region,
+		bucket:    cfg.Bucket,
+		accessKey: cfg.AccessKey,
+		secretKey: cfg.SecretKey,
+		pathStyle: cfg.PathStyle,
+		client:    &http.Client{Timeout: 5 * time.Minute},
+		now:       time.Now,
+	}, nil
+}
+
+// Put загружает объект одним запросом PUT
+func (s *S3Storage) Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, s.objectURL(key).String(), io.LimitReader(body, size))
+	if err != nil {
+		return fmt.Errorf("s3 storage: %w", err)
+	}
+	req.ContentLength = size
+	req.Header.Set("Content-Type", contentType)
+	return s.do(req, key, http.StatusOK)
+}
+
+// Delete удаляет объект
+func (s *S3Storage) Delete(ctx context.Context, key string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, s.objectURL(key).String(), nil)
+	if err != nil {
+		return fmt.Errorf("s3 storage: %w", err)
+	}
+	return s.do(req, key, http.StatusNoContent, http.StatusOK, http.StatusNotFound)
+}
+
+// SignedURL возвращает предподписанный GET URL объекта
+func (s *S3Storage) SignedURL(ctx context.Context, key, fileName string, expiresAt time.Time) (string, error) {
+	now := s.now().UTC()
+	expiresIn := expiresAt.Sub(now).Truncate(time.Second)
+	if expiresIn < time.Second || expiresIn > maxPresignExpiry {
+		return "", fmt.Errorf("s3 storage: link lifetime must be between 1s and %s", maxPresignExpiry)
+	}
+	extra := url.Values{"response-content-disposition": {contentDisposition(fileName)}}
+	return s.presign(http.MethodGet, key, extra, now, expiresIn), nil
+}
+
+// do подписывает и выполняет запрос, считая успешными коды okStatuses
+func (s *S3Storage) do(req *http.Request, key string, okStatuses ...int) error {
+	s.signRequest(req, s.now().UTC())
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("s3 storage: %s %s: %w", req.Method, key, err)
+	}
+	defer resp.Body.Close()
+	for _, status := range okStatuses {
+		if resp.StatusCode == status {
+			return nil
+		}
+	}
+	message, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	if resp.StatusCode == http.StatusNotFound {
+		return fmt.Errorf("%w: %s", ErrObjectNotFound, key)
+	}
+	return fmt.Errorf("s3 storage: %s %s: unexpected status %d: %s", req.Method, key, resp.StatusCode, strings.TrimSpace(string(message)))
+}
+
+// objectURL возвращает адрес объекта с учетом стиля адресации
+func (s *S3Storage) objectURL(key string) *url.URL {
+	u := *s.endpoint
+	path := "/" + key
+	if s.pathStyle {
+		path = "/" + s.bucket + path
+	} else {
+		u.Host = s.bucket + "." + u.Host
+	}
+	u.Path = strings.TrimSuffix(s.endpoint.Path, "/") + path
+	u.RawPath = strings.TrimSuffix(s.endpoint.EscapedPath(), "/") + uriEncode(path, false)
+	return &u
+}
+
+// signRequest добавляет к запросу заголовки подписи SigV4 (подписываются host, x-amz-content-sha256 и x-amz-date)
+func (s *S3Storage) signRequest(req *http.Request, now time.Time) {
+	amzDate := now.Format("20060102T150405Z")
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", unsignedPayload)
+
+	signedHeaders := "host;x-amz-content-sha256;x-amz-date"
+	canonicalHeaders := "host:" + req.URL.Host + "\n" +
+		"x-amz-content-sha256:" + unsignedPayload + "\n" +
+		"x-amz-date:" + amzDate + "\n"
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		canonicalQuery(req.URL.Query()),
+		canonicalHeaders,
+		signedHeaders,
+		unsignedPayload,
+	}, "\n")
+
+	scope := s.scope(now)
+	signature := s.signature(now, scope, amzDate, canonicalRequest)
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s.accessKey, scope, signedHeaders, signature))
+}
+
+// presign строит предподписанный URL (подпись в параметрах запроса, подписывается только host)
+func (s *S3Storage) presign(method, key string, extra url.Values, now time.Time, expiresIn time.Duration) string {
+	u := s.objectURL(key)
+	amzDate := now.Format("20060102T150405Z")
+	scope := s.scope(now)
+
+	query := url.Values{}
+	for k, v := range extra {
+		query[k] = v
+	}
+	query.Set("X-Amz-Algorithm", "AWS4-HMAC-SHA256")
+	query.Set("X-Amz-Credential", s.accessKey+"/"+scope)
+	query.Set("X-Amz-Date", amzDate)
+	query.Set("X-Amz-Expires", strconv.Itoa(int(expiresIn/time.Second)))
+	query.Set("X-Amz-SignedHeaders", "host")
+
+	canonicalRequest := strings.Join([]string{
+		method,
+		u.EscapedPath(),
+		canonicalQuery(query),
+		"host:" + u.Host + "\n",
+		"host",
+		unsignedPayload,
+	}, "\n")
+	signature := s.signature(now, scope, amzDate, canonicalRequest)
+
+	u.RawQuery = canonicalQuery(query) + "&X-Amz-Signature=" + signature
+	return u.String()
+}
+
+// scope - область действия подписи: дата/регион/s3/aws4_request
+func (s *S3Storage) scope(now time.Time) string {
+	return now.Format("20060102") + "/" + s.region + "/s3/aws4_request"
+}
+
+// signature вычисляет подпись канонического запроса ключом, производным от секрета, даты и региона
+func (s *S3Storage) signature(now time.Time, scope, amzDate, canonicalRequest string) string {
+	requestHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(requestHash[:])
+
+	key := hmacSHA256([]byte("AWS4"+s.secretKey), now.Format("20060102"))
+	key = hmacSHA256(key, s.region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	return hex.EncodeToString(hmacSHA256(key, stringToSign))
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+// canonicalQuery кодирует параметры, отсортированные по имени, как требует SigV4
+func canonicalQuery(query url.Values) string {
+	keys := make([]string, 0, len(query))
+	for k := range query {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	parts := make([]string, 0, len(keys))
+	for _, k := range keys {
+		values := append([]string(nil), query[k]...)
+		sort.Strings(values)
+		for _, v := range values {
+			parts = append(parts, uriEncode(k, true)+"="+uriEncode(v, true))
+		}
+	}
+	return strings.Join(parts, "&")
+}
+
+// uriEncode кодирует строку по RFC 3986, оставляя только незарезервированные символы
+// (и '/', если encodeSlash = false)
+func uriEncode(s string, encodeSlash bool) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', '0' <= c && c <= '9', c == '-', c == '_', c == '.', c == '~':
+			b.WriteByte(c)
+		case c == '/' && !encodeSlash:
+			b.WriteByte(c)
+		default:
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
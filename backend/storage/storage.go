@@ -0,0 +1,31 @@
+// Package storage хранит файлы вложений сессий. Хранилище скрыто за интерфейсом Storage:
+// LocalStorage пишет на локальный диск и отдает файлы сам, S3Storage работает с любым
+// S3-совместимым сервисом (AWS S3, MinIO). Скачивание в обоих случаях идет по подписанным
+// ссылкам с ограниченным сроком действия, поэтому проверка доступа выполняется один раз -
+// при выдаче ссылки.
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"time"
+)
+
+var ErrObjectNotFound = errors.New("stored object not found")
+
+// Storage - хранилище файлов по ключу
+type Storage interface {
+	// Put сохраняет size байт из body под ключом key
+	Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error
+	// Delete удаляет объект. Удаление отсутствующего объекта не считается ошибкой.
+	Delete(ctx context.Context, key string) error
+	// SignedURL возвращает ссылку на скачивание объекта, действующую до expiresAt.
+	// fileName подставляется в Content-Disposition ответа.
+	SignedURL(ctx context.Context, key, fileName string, expiresAt time.Time) (string, error)
+}
+
+// contentDisposition формирует заголовок Content-Disposition для скачивания файла fileName (RFC 6266)
+func contentDisposition(fileName string) string {
+	return `attachment; filename*=UTF-8''` + uriEncode(fileName, true)
+}
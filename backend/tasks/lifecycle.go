@@ -0,0 +1,50 @@
+// tasks/lifecycle.go
+package tasks
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/BuzzLyutic/Skill-sharing-web-platform/repositories"
+)
+
+// CompleteEndedSessions периодически переводит закончившиеся опубликованные сессии в статус completed
+func CompleteEndedSessions(sessionRepo *repositories.SessionRepository) {
+	ticker := time.NewTicker(10 * time.Minute)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			completed, err := sessionRepo.CompleteEnded(context.Background(), time.Now())
+			if err != nil {
+				log.Printf("ERROR completing ended sessions: %v", err)
+				continue
+			}
+			if completed > 0 {
+				log.Printf("INFO: Marked %d sessions as completed", completed)
+			}
+		}
+	}
+}
+
+// ExpireReconfirmations периодически закрывает запросы подтверждения участия, на которые не ответили до срока
+func ExpireReconfirmations(reconfirmRepo *repositories.ReconfirmationRepository) {
+	ticker := time.NewTicker(10 * time.Minute)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			expired, err := reconfirmRepo.ExpireOverdue(context.Background(), time.Now())
+			if err != nil {
+				log.Printf("ERROR expiring reconfirmations: %v", err)
+				continue
+			}
+			if expired > 0 {
+				log.Printf("INFO: Marked %d reconfirmations as expired", expired)
+			}
+		}
+	}
+}
@@ -64,7 +64,11 @@ func processReminders(
 		for _, participant := range participants {
 
 			notifMsg := fmt.Sprintf("Reminder: Your session '%s' is starting on %s.",
-				session.Title, session.DateTime.Format("Jan 2, 2006 at 3:04 PM"))
+				session.Title, session.DateTime.In(session.TimeLocationFor(&participant)).Format(models.DisplayTimeFormat))
+			// Напоминания получают только подтвержденные участники, поэтому ссылку на встречу можно показать
+			if details := session.MeetingDetailsText(); details != "" {
+				notifMsg += "\n" + details
+			}
 
 			newNotif := models.Notification{
 				UserID:      participant.ID,
@@ -46,11 +46,11 @@ export default function SessionDetailPage() {
       const [sessionRes, participantsRes, feedbackRes] = await Promise.all([
         apiClient.get<Session>(`/api/sessions/${sessionId}`),
         apiClient.get<User[]>(`/api/sessions/${sessionId}/participants`),
-        apiClient.get<Feedback[]>(`/api/sessions/${sessionId}/feedback`)
+        apiClient.get<{ data: Feedback[] }>(`/api/sessions/${sessionId}/feedback?limit=100`)
       ]);
       setSession(sessionRes.data);
       setParticipants(participantsRes.data);
-      setFeedbackList(feedbackRes.data);
+      setFeedbackList(feedbackRes.data.data);
     } catch (err: any) {
       console.error('Failed to fetch session data:', err);
       if (err.response?.status === 404) {
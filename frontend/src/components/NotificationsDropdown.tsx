@@ -19,8 +19,8 @@ export default function NotificationsDropdown() {
     if (!isAuthenticated) return;
     setIsLoading(true);
     try {
-      const response = await apiClient.get<Notification[]>('/api/notifications/unread?limit=5'); // Берем 5 последних
-      setNotifications(response.data);
+      const response = await apiClient.get<{ data: Notification[] }>('/api/notifications/unread?limit=5'); // Берем 5 последних
+      setNotifications(response.data.data);
     } catch (error) {
     } finally {
       setIsLoading(false);